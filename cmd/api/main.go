@@ -1,8 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"campusassistant-api/internal/config"
 	httpDelivery "campusassistant-api/internal/delivery/http"
@@ -10,6 +16,10 @@ import (
 	"campusassistant-api/pkg/logger"
 )
 
+// shutdownTimeout bounds how long in-flight requests may take to finish
+// after SIGINT/SIGTERM.
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	// 1. Load Configuration
 	cfg, err := config.LoadConfig()
@@ -32,7 +42,7 @@ func main() {
 	if cfg.Environment == "development" || cfg.DBAutoMigrate {
 		logger.Infof("Migrations enabled (Mode: %s, AutoMigrate: %v). Running...", cfg.Environment, cfg.DBAutoMigrate)
 		go func() {
-			if err := postgres.RunMigrations(db); err != nil {
+			if err := postgres.RunMigrations(db, cfg); err != nil {
 				log.Printf("Background migration failed: %v", err)
 			}
 		}()
@@ -40,13 +50,35 @@ func main() {
 		logger.Infof("Migrations skipped for production (Set DB_AUTO_MIGRATE=true to enable)")
 	}
 
-	// 4. Setup Router
-	r := httpDelivery.NewRouter(cfg, db)
+	// 4. Setup Router and start the background workers
+	r, workers := httpDelivery.NewRouter(cfg, db)
+	workers.Start()
 
 	// 5. Start Server
-	serverAddr := fmt.Sprintf(":%s", cfg.Port)
-	logger.Infof("Server listening on %s", serverAddr)
-	if err := r.Run(serverAddr); err != nil {
-		logger.Fatalf("Server failed to start: %v", err)
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%s", cfg.Port),
+		Handler: r,
+	}
+	go func() {
+		logger.Infof("Server listening on %s", srv.Addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Fatalf("Server failed to start: %v", err)
+		}
+	}()
+
+	// 6. Shut down gracefully: finish in-flight requests, then flush the
+	// workers (e.g. queued audit log entries)
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+	stop()
+
+	logger.Infof("Shutting down...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		logger.Errorf("Server shutdown failed: %v", err)
 	}
+	workers.Stop()
+	logger.Infof("Server stopped")
 }
@@ -0,0 +1,76 @@
+// Command openapi writes the API's OpenAPI document to docs/openapi.json.
+//
+// The spec is produced by the same router the server runs, so with -check it
+// fails (exit 1) whenever the committed file no longer matches the routes and
+// models, e.g. in CI:
+//
+//	go run ./cmd/openapi -check
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+
+	"campusassistant-api/internal/config"
+	httpDelivery "campusassistant-api/internal/delivery/http"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func main() {
+	out := flag.String("o", "docs/openapi.json", "output file")
+	check := flag.Bool("check", false, "fail if the output file is out of date instead of writing it")
+	flag.Parse()
+
+	spec, err := generate()
+	if err != nil {
+		log.Fatalf("Failed to generate spec: %v", err)
+	}
+
+	if *check {
+		current, err := os.ReadFile(*out)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *out, err)
+		}
+		if !bytes.Equal(current, spec) {
+			fmt.Fprintf(os.Stderr, "%s is out of date; run `make openapi` and commit the result\n", *out)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if err := os.WriteFile(*out, spec, 0o644); err != nil {
+		log.Fatalf("Failed to write %s: %v", *out, err)
+	}
+}
+
+// generate builds the router without a live database and fetches the spec it
+// serves at /openapi.json.
+func generate() ([]byte, error) {
+	gin.SetMode(gin.ReleaseMode)
+	gin.DefaultWriter = io.Discard
+	logger.InitLogger("production")
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		return nil, err
+	}
+
+	r, _ := httpDelivery.NewRouter(&config.Config{Environment: "production"}, db)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		return nil, fmt.Errorf("GET /openapi.json returned %d", w.Code)
+	}
+	return w.Body.Bytes(), nil
+}
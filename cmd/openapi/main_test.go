@@ -0,0 +1,23 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"testing"
+)
+
+// TestSpecUpToDate fails when docs/openapi.json no longer matches the routes
+// and models; run `make openapi` and commit the result.
+func TestSpecUpToDate(t *testing.T) {
+	spec, err := generate()
+	if err != nil {
+		t.Fatalf("generate spec: %v", err)
+	}
+	current, err := os.ReadFile("../../docs/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(current, spec) {
+		t.Fatal("docs/openapi.json is out of date; run `make openapi` and commit the result")
+	}
+}
@@ -0,0 +1,54 @@
+// Command webhook-receiver is a local endpoint for trying out webhook
+// subscriptions. It verifies each request's signature, prints the event and
+// answers with the configured status, so retries can be exercised with
+// -status 500.
+//
+//	go run ./cmd/webhook-receiver -secret whsec_... -addr :9090
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io"
+	"log"
+	"net/http"
+
+	"campusassistant-api/pkg/webhook"
+)
+
+func main() {
+	addr := flag.String("addr", ":9090", "listen address")
+	secret := flag.String("secret", "", "subscription signing secret (skip verification when empty)")
+	status := flag.Int("status", http.StatusOK, "status code to answer with")
+	flag.Parse()
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		verdict := "unverified"
+		if *secret != "" {
+			if err := webhook.Verify(*secret, r.Header.Get(webhook.SignatureHeader), body, 0); err != nil {
+				log.Printf("rejected %s: %v", r.Header.Get(webhook.DeliveryHeader), err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+			verdict = "verified"
+		}
+
+		var pretty bytes.Buffer
+		if json.Indent(&pretty, body, "", "  ") != nil {
+			pretty.Write(body)
+		}
+		log.Printf("%s delivery=%s (%s)\n%s", r.Header.Get(webhook.EventHeader), r.Header.Get(webhook.DeliveryHeader), verdict, pretty.String())
+
+		w.WriteHeader(*status)
+	})
+
+	log.Printf("listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
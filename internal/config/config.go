@@ -2,7 +2,10 @@ package config
 
 import (
 	"log"
+	"strconv"
 	"strings"
+	"time"
+	_ "time/tzdata" // Time zones without a system zoneinfo database
 
 	"github.com/spf13/viper"
 )
@@ -22,6 +25,10 @@ type Config struct {
 	// API Security
 	APIKey string `mapstructure:"API_KEY"`
 
+	// Comma-separated IPs/CIDRs of reverse proxies whose X-Forwarded-For is
+	// believed; empty trusts none and uses the connection address
+	TrustedProxies string `mapstructure:"TRUSTED_PROXIES"`
+
 	// Migrations
 	DBAutoMigrate bool `mapstructure:"DB_AUTO_MIGRATE"`
 
@@ -29,6 +36,27 @@ type Config struct {
 	JWTSecret             string `mapstructure:"JWT_SECRET"`
 	JWTAccessTokenExpiry  int    `mapstructure:"JWT_ACCESS_TOKEN_EXPIRY"`  // in minutes
 	JWTRefreshTokenExpiry int    `mapstructure:"JWT_REFRESH_TOKEN_EXPIRY"` // in hours
+
+	// Idempotency-Key replay window
+	IdempotencyTTL int `mapstructure:"IDEMPOTENCY_TTL"` // in hours
+
+	// Lifetime of profile claim codes
+	ClaimCodeTTL int `mapstructure:"CLAIM_CODE_TTL"` // in hours
+
+	// Maximum number of current CRs per batch (0 = no limit)
+	CRMaxPerBatch int `mapstructure:"CR_MAX_PER_BATCH"`
+
+	// Days after a donation before a blood donor is listed again
+	BloodDonationCooldown int `mapstructure:"BLOOD_DONATION_COOLDOWN"`
+
+	// IANA time zone of the campus, for weekly schedules like office hours
+	Timezone string `mapstructure:"TIMEZONE"`
+
+	// Comma-separated hours before an exam at which the batch is reminded
+	ExamReminderHours string `mapstructure:"EXAM_REMINDER_HOURS"`
+
+	// Attendance percentage below which students are flagged
+	AttendanceThreshold float64 `mapstructure:"ATTENDANCE_THRESHOLD"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -46,16 +74,31 @@ func LoadConfig() (*Config, error) {
 	v.BindEnv("R2_ACCOUNT_ID")
 	v.BindEnv("R2_PUBLIC_URL")
 	v.BindEnv("API_KEY")
+	v.BindEnv("TRUSTED_PROXIES")
 	v.BindEnv("JWT_SECRET")
 	v.BindEnv("JWT_ACCESS_TOKEN_EXPIRY")
 	v.BindEnv("JWT_REFRESH_TOKEN_EXPIRY")
 	v.BindEnv("DB_AUTO_MIGRATE")
+	v.BindEnv("IDEMPOTENCY_TTL")
+	v.BindEnv("CLAIM_CODE_TTL")
+	v.BindEnv("CR_MAX_PER_BATCH")
+	v.BindEnv("BLOOD_DONATION_COOLDOWN")
+	v.BindEnv("TIMEZONE")
+	v.BindEnv("EXAM_REMINDER_HOURS")
+	v.BindEnv("ATTENDANCE_THRESHOLD")
 
 	// Default values
 	v.SetDefault("PORT", "8080")
 	v.SetDefault("ENVIRONMENT", "development")
 	v.SetDefault("JWT_ACCESS_TOKEN_EXPIRY", 60)   // 1 hour
 	v.SetDefault("JWT_REFRESH_TOKEN_EXPIRY", 168) // 7 days (168 hours)
+	v.SetDefault("IDEMPOTENCY_TTL", 24)           // 1 day
+	v.SetDefault("CLAIM_CODE_TTL", 720)           // 30 days
+	v.SetDefault("CR_MAX_PER_BATCH", 2)
+	v.SetDefault("BLOOD_DONATION_COOLDOWN", 90)
+	v.SetDefault("TIMEZONE", "Asia/Dhaka")
+	v.SetDefault("EXAM_REMINDER_HOURS", "72,24")
+	v.SetDefault("ATTENDANCE_THRESHOLD", 75)
 
 	if err := v.ReadInConfig(); err != nil {
 		log.Println("No .env file found, using environment variables")
@@ -71,3 +114,41 @@ func LoadConfig() (*Config, error) {
 
 	return &config, nil
 }
+
+// Location loads Timezone, falling back to UTC when it is unknown.
+func (c *Config) Location() *time.Location {
+	loc, err := time.LoadLocation(c.Timezone)
+	if err != nil {
+		log.Printf("Unknown TIMEZONE %q, using UTC: %v", c.Timezone, err)
+		return time.UTC
+	}
+	return loc
+}
+
+// TrustedProxyList splits TrustedProxies; nil means no proxy is trusted.
+func (c *Config) TrustedProxyList() []string {
+	var proxies []string
+	for _, part := range strings.Split(c.TrustedProxies, ",") {
+		if p := strings.TrimSpace(part); p != "" {
+			proxies = append(proxies, p)
+		}
+	}
+	return proxies
+}
+
+// ExamReminderLeads parses ExamReminderHours, skipping entries that are not
+// positive whole hours.
+func (c *Config) ExamReminderLeads() []time.Duration {
+	var leads []time.Duration
+	for _, part := range strings.Split(c.ExamReminderHours, ",") {
+		hours, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || hours <= 0 {
+			if strings.TrimSpace(part) != "" {
+				log.Printf("Ignoring EXAM_REMINDER_HOURS entry %q", part)
+			}
+			continue
+		}
+		leads = append(leads, time.Duration(hours)*time.Hour)
+	}
+	return leads
+}
@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+	"campusassistant-api/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AcademicCalendarHandler serves the academic calendars of universities and
+// departments. Writes go through usecase.AcademicCalendar; reads of single
+// events use the generic handler.
+type AcademicCalendarHandler struct {
+	*GenericHandler[domain.CalendarEvent]
+	calendar *usecase.AcademicCalendar
+}
+
+func NewAcademicCalendarHandler(u usecase.Usecase[domain.CalendarEvent], calendar *usecase.AcademicCalendar) *AcademicCalendarHandler {
+	return &AcademicCalendarHandler{
+		GenericHandler: NewGenericHandler(u),
+		calendar:       calendar,
+	}
+}
+
+// Create adds an event to a university's calendar, or a department's when
+// department_id is set.
+// POST /calendar-events
+func (h *AcademicCalendarHandler) Create(c *gin.Context) {
+	var event domain.CalendarEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	event.SetCreatedBy(uid)
+	event.SetUpdatedBy(uid)
+
+	created, err := h.calendar.Create(c.Request.Context(), &event)
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update replaces a calendar event.
+// PUT /calendar-events/:id
+func (h *AcademicCalendarHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var changes domain.CalendarEvent
+	if err := c.ShouldBindJSON(&changes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	changes.ID = id
+	changes.SetUpdatedBy(uid)
+
+	event, err := h.calendar.Update(c.Request.Context(), &changes)
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, event)
+}
+
+// UniversityRange lists the university-wide events of a ?month=YYYY-MM
+// (default this month) or ?from= to ?to=, optionally of one ?category=.
+// GET /universities/:id/calendar
+func (h *AcademicCalendarHandler) UniversityRange(c *gin.Context) {
+	h.occurrences(c, h.calendar.UniversityScope)
+}
+
+// DepartmentRange is UniversityRange for a department, with its own events
+// and its university's.
+// GET /departments/:id/calendar
+func (h *AcademicCalendarHandler) DepartmentRange(c *gin.Context) {
+	h.occurrences(c, h.calendar.DepartmentScope)
+}
+
+// UniversityFeed serves the university-wide events as an iCalendar feed
+// that calendar apps can subscribe to. It needs no API key.
+// GET /universities/:id/calendar.ics
+func (h *AcademicCalendarHandler) UniversityFeed(c *gin.Context) {
+	h.feed(c, h.calendar.UniversityScope)
+}
+
+// DepartmentFeed is UniversityFeed for a department.
+// GET /departments/:id/calendar.ics
+func (h *AcademicCalendarHandler) DepartmentFeed(c *gin.Context) {
+	h.feed(c, h.calendar.DepartmentScope)
+}
+
+// UniversityWorkingDay says whether ?date=YYYY-MM-DD (default today) is a
+// working day across the university.
+// GET /universities/:id/working-day
+func (h *AcademicCalendarHandler) UniversityWorkingDay(c *gin.Context) {
+	h.workingDay(c, h.calendar.UniversityScope)
+}
+
+// DepartmentWorkingDay is UniversityWorkingDay for a department.
+// GET /departments/:id/working-day
+func (h *AcademicCalendarHandler) DepartmentWorkingDay(c *gin.Context) {
+	h.workingDay(c, h.calendar.DepartmentScope)
+}
+
+type scopeFinder func(ctx context.Context, id uuid.UUID) (usecase.CalendarScope, error)
+
+func (h *AcademicCalendarHandler) occurrences(c *gin.Context, find scopeFinder) {
+	scope, ok := calendarScope(c, find)
+	if !ok {
+		return
+	}
+	occurrences, err := h.calendar.Range(c.Request.Context(), scope, c.Query("month"), c.Query("from"), c.Query("to"), c.Query("category"))
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  occurrences,
+		"count": len(occurrences),
+	})
+}
+
+func (h *AcademicCalendarHandler) feed(c *gin.Context, find scopeFinder) {
+	scope, ok := calendarScope(c, find)
+	if !ok {
+		return
+	}
+	cal, err := h.calendar.Feed(c.Request.Context(), scope)
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="calendar.ics"`)
+	c.Data(http.StatusOK, ical.ContentType, cal.Bytes())
+}
+
+func (h *AcademicCalendarHandler) workingDay(c *gin.Context, find scopeFinder) {
+	scope, ok := calendarScope(c, find)
+	if !ok {
+		return
+	}
+	day, err := h.calendar.WorkingDay(c.Request.Context(), scope, c.Query("date"))
+	if err != nil {
+		respondCalendarError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, day)
+}
+
+// calendarScope finds the calendar named by the :id path parameter,
+// responding with the error when it cannot.
+func calendarScope(c *gin.Context, find scopeFinder) (usecase.CalendarScope, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return usecase.CalendarScope{}, false
+	}
+	scope, err := find(c.Request.Context(), id)
+	if err != nil {
+		respondCalendarError(c, err)
+		return usecase.CalendarScope{}, false
+	}
+	return scope, true
+}
+
+func respondCalendarError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, usecase.ErrInvalidCalendarEvent), errors.Is(err, usecase.ErrInvalidCalendarRange), errors.Is(err, usecase.ErrInvalidDate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"net/http"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// adminScope is the part of the directory a signed-in admin manages:
+// everything for super admins, their university for university admins and
+// their department for department admins. Nil fields are unrestricted.
+type adminScope struct {
+	UniversityID *uuid.UUID
+	DepartmentID *uuid.UUID
+}
+
+// callerScope returns the admin scope from the JWT claims, or responds 403
+// for other roles and for admins whose token carries no university (or
+// department).
+func callerScope(c *gin.Context) (adminScope, bool) {
+	role, _ := c.Get("user_role")
+	universityID, _ := c.Get("university_id")
+	departmentID, _ := c.Get("department_id")
+	uni, _ := universityID.(uuid.UUID)
+	dept, _ := departmentID.(uuid.UUID)
+
+	switch r, _ := role.(string); domain.Role(r) {
+	case domain.RoleSuperAdmin:
+		return adminScope{}, true
+	case domain.RoleUniversityAdmin:
+		if uni != uuid.Nil {
+			return adminScope{UniversityID: &uni}, true
+		}
+	case domain.RoleDepartmentAdmin:
+		if uni != uuid.Nil && dept != uuid.Nil {
+			return adminScope{UniversityID: &uni, DepartmentID: &dept}, true
+		}
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+	return adminScope{}, false
+}
+
+// covers reports whether a record owned by the university and department
+// (nil when unset) lies inside the scope.
+func (s adminScope) covers(universityID, departmentID *uuid.UUID) bool {
+	if s.UniversityID != nil && (universityID == nil || *universityID != *s.UniversityID) {
+		return false
+	}
+	if s.DepartmentID != nil && (departmentID == nil || *departmentID != *s.DepartmentID) {
+		return false
+	}
+	return true
+}
+
+// restrict narrows a list filter to the scope, overriding any
+// university_id or department_id the caller asked for.
+func (s adminScope) restrict(filter map[string]interface{}) {
+	if s.UniversityID != nil {
+		filter["university_id"] = *s.UniversityID
+	}
+	if s.DepartmentID != nil {
+		filter["department_id"] = *s.DepartmentID
+	}
+}
@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// AlumniProfileRequest is what graduates may edit on their own record.
+// Name, student ID, batch and passing year stay with the admins.
+type AlumniProfileRequest struct {
+	Email         string         `json:"email"`
+	Phone         string         `json:"phone"`
+	CurrentStatus string         `json:"current_status"`
+	Organization  string         `json:"organization"`
+	Designation   string         `json:"designation"`
+	Location      string         `json:"location"`
+	Bio           string         `json:"bio"`
+	ProfileImage  string         `json:"profile_image"`
+	SocialLinks   datatypes.JSON `json:"social_links"`
+}
+
+// AlumniHandler serves the alumni directory: CRUD and search for admins,
+// claiming and self-service for graduates, and career aggregates.
+type AlumniHandler struct {
+	*GenericHandler[domain.Alumni]
+	*ClaimHandler[domain.Alumni]
+	repo domain.AlumniRepository
+}
+
+func NewAlumniHandler(u usecase.Usecase[domain.Alumni], repo domain.AlumniRepository, claims domain.ClaimRepository[domain.Alumni], codes *usecase.ClaimCodes, users usecase.Usecase[domain.User], events EventSink) *AlumniHandler {
+	return &AlumniHandler{
+		GenericHandler: NewGenericHandler(u),
+		ClaimHandler:   NewClaimHandler(u, claims, codes, users, events),
+		repo:           repo,
+	}
+}
+
+// GetMe returns the alumni record claimed by the signed-in user.
+// GET /alumni/me
+func (h *AlumniHandler) GetMe(c *gin.Context) {
+	alumni, ok := h.mine(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, alumni)
+}
+
+// UpdateMe replaces the self-editable fields of the signed-in user's record.
+// PUT /alumni/me
+func (h *AlumniHandler) UpdateMe(c *gin.Context) {
+	var req AlumniProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	alumni, ok := h.mine(c)
+	if !ok {
+		return
+	}
+	alumni.Email = req.Email
+	alumni.Phone = req.Phone
+	alumni.CurrentStatus = req.CurrentStatus
+	alumni.Organization = req.Organization
+	alumni.Designation = req.Designation
+	alumni.Location = req.Location
+	alumni.Bio = req.Bio
+	alumni.ProfileImage = req.ProfileImage
+	alumni.SocialLinks = req.SocialLinks
+	alumni.SetUpdatedBy(*alumni.UserID)
+
+	if err := h.Usecase.Update(c.Request.Context(), alumni); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, alumni)
+}
+
+// Stats aggregates where graduates work, overall and per batch. It takes
+// the list filters (e.g. department_id, passing_year) and ?top=N (default
+// 10, at most 50) for the length of each ranking.
+// GET /alumni/stats
+func (h *AlumniHandler) Stats(c *gin.Context) {
+	top, _ := strconv.Atoi(c.DefaultQuery("top", "10"))
+	if top <= 0 {
+		top = 10
+	} else if top > 50 {
+		top = 50
+	}
+
+	filter := listFilter(c)
+	delete(filter, "preload")
+
+	stats, err := h.repo.Stats(c.Request.Context(), filter, top)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// mine loads the record claimed by the signed-in user.
+func (h *AlumniHandler) mine(c *gin.Context) (*domain.Alumni, bool) {
+	userID, ok := c.Get("user_id")
+	uid, isUUID := userID.(uuid.UUID)
+	if !ok || !isUUID {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return nil, false
+	}
+
+	found, _, err := h.Usecase.GetAll(c.Request.Context(), map[string]interface{}{"user_id": uid}, 1, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	if len(found) == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "No alumni record is linked to your account; claim yours with its verification code"})
+		return nil, false
+	}
+	return &found[0], true
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"campusassistant-api/internal/usecase"
+	"campusassistant-api/pkg/logger"
+	"campusassistant-api/pkg/spreadsheet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttendanceRequest is a day's roll: the status of each student marked.
+type AttendanceRequest struct {
+	Note    string         `json:"note"`
+	Records []usecase.Mark `json:"records" binding:"required,dive"`
+}
+
+// AttendanceHandler serves class attendance of course offerings. Academic
+// admins, the batch's CRs and the course's teachers take and read it;
+// students see their own.
+type AttendanceHandler struct {
+	attendance *usecase.Attendance
+}
+
+func NewAttendanceHandler(attendance *usecase.Attendance) *AttendanceHandler {
+	return &AttendanceHandler{attendance: attendance}
+}
+
+// Take records or corrects the offering's attendance on a date.
+// PUT /course-offerings/:id/attendance/:date
+func (h *AttendanceHandler) Take(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	var req AttendanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	session, err := h.attendance.Take(c.Request.Context(), id, c.Param("date"), req.Note, req.Records)
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// Roll returns the offering's attendance on a date.
+// GET /course-offerings/:id/attendance/:date
+func (h *AttendanceHandler) Roll(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	session, err := h.attendance.Roll(c.Request.Context(), id, c.Param("date"))
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, session)
+}
+
+// Sessions lists the dates the offering's attendance was taken.
+// GET /course-offerings/:id/attendance
+func (h *AttendanceHandler) Sessions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	limit, offset := pageParams(c)
+
+	sessions, count, err := h.attendance.Sessions(c.Request.Context(), id, limit, offset)
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":   sessions,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// Summary returns each student's attendance in the offering, flagging
+// those below ?threshold= percent (default ATTENDANCE_THRESHOLD). With
+// ?format=csv or xlsx (or a matching Accept header) it is a spreadsheet.
+// GET /course-offerings/:id/attendance/summary
+func (h *AttendanceHandler) Summary(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	var threshold float64
+	if t := c.Query("threshold"); t != "" {
+		if threshold, err = strconv.ParseFloat(t, 64); err != nil || threshold <= 0 || threshold > 100 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "threshold must be a percentage above 0"})
+			return
+		}
+	}
+
+	summary, err := h.attendance.Summary(c.Request.Context(), id, threshold)
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+	if format, ok := exportFormat(c); ok {
+		writeAttendanceSummary(c, summary, format)
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
+
+// Mine returns the signed-in student's attendance per course.
+// GET /students/me/attendance
+func (h *AttendanceHandler) Mine(c *gin.Context) {
+	courses, err := h.attendance.Mine(c.Request.Context())
+	if err != nil {
+		respondAttendanceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  courses,
+		"count": len(courses),
+	})
+}
+
+// writeAttendanceSummary sends the summary as one row per student.
+func writeAttendanceSummary(c *gin.Context, summary *usecase.AttendanceSummary, format spreadsheet.Format) {
+	name := "attendance"
+	if summary.Offering.Course != nil {
+		name += "-" + strings.ToLower(summary.Offering.Course.CourseCode)
+	}
+	if summary.Offering.Section != "" {
+		name += "-" + strings.ToLower(summary.Offering.Section)
+	}
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("2006-01-02"), format)
+
+	c.Header("Content-Type", spreadsheet.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	w, err := spreadsheet.NewWriter(c.Writer, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	rows := [][]string{{"student_id", "name", "present", "late", "absent", "attended", "marked", "percentage", "below_threshold"}}
+	for _, s := range summary.Students {
+		rows = append(rows, []string{
+			s.Student.StudentID,
+			s.Student.Name,
+			strconv.Itoa(s.Present),
+			strconv.Itoa(s.Late),
+			strconv.Itoa(s.Absent),
+			strconv.Itoa(s.Attended),
+			strconv.Itoa(s.Marked),
+			strconv.FormatFloat(s.Percentage, 'f', 1, 64),
+			strconv.FormatBool(s.Below),
+		})
+	}
+	for _, row := range rows {
+		if err := w.Write(row); err != nil {
+			logger.Errorf("attendance export: %v", err)
+			return
+		}
+	}
+	if err := w.Close(); err != nil {
+		logger.Errorf("attendance export: %v", err)
+	}
+}
+
+func respondAttendanceError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, usecase.ErrNotAttendanceTaker), errors.Is(err, usecase.ErrNoStudentProfile):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidAttendance), errors.Is(err, usecase.ErrStudentNotInBatch), errors.Is(err, usecase.ErrInvalidDate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AuditLogHandler exposes the read-only audit trail to super admins.
+type AuditLogHandler struct {
+	repo domain.AuditLogRepository
+}
+
+func NewAuditLogHandler(repo domain.AuditLogRepository) *AuditLogHandler {
+	return &AuditLogHandler{repo: repo}
+}
+
+// GetAll lists audit logs, newest first.
+// GET /audit-logs?user_id=&entity_name=&entity_id=&action=&from=&to=
+func (h *AuditLogHandler) GetAll(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	if limit <= 0 {
+		limit = 20
+	} else if limit > 100 {
+		limit = 100
+	}
+
+	filter := make(map[string]interface{})
+
+	for _, f := range []string{"user_id", "entity_id"} {
+		if val := c.Query(f); val != "" {
+			id, err := uuid.Parse(val)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + f})
+				return
+			}
+			filter[f] = id
+		}
+	}
+
+	for _, f := range []string{"entity_name", "action", "ip_address"} {
+		if val := c.Query(f); val != "" {
+			filter[f] = val
+		}
+	}
+
+	// Time range accepts RFC3339 timestamps or plain dates (YYYY-MM-DD)
+	for _, f := range []string{"from", "to"} {
+		if val := c.Query(f); val != "" {
+			t, err := parseTimeParam(val)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + f + " (use RFC3339 or YYYY-MM-DD)"})
+				return
+			}
+			filter[f] = t
+		}
+	}
+
+	if search := c.Query("search"); search != "" {
+		filter["search"] = search
+	}
+
+	logs, count, err := h.repo.GetAll(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   logs,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+func parseTimeParam(val string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, val); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", val)
+}
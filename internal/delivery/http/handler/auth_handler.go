@@ -3,6 +3,7 @@ package handler
 import (
 	"campusassistant-api/internal/domain"
 	"campusassistant-api/pkg/auth"
+	"campusassistant-api/pkg/eventbus"
 	"errors"
 	"net/http"
 	"strings"
@@ -17,13 +18,15 @@ import (
 type AuthHandler struct {
 	db         *gorm.DB
 	jwtManager *auth.JWTManager
+	events     *eventbus.Bus
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(db *gorm.DB, jwtManager *auth.JWTManager) *AuthHandler {
+func NewAuthHandler(db *gorm.DB, jwtManager *auth.JWTManager, events *eventbus.Bus) *AuthHandler {
 	return &AuthHandler{
 		db:         db,
 		jwtManager: jwtManager,
+		events:     events,
 	}
 }
 
@@ -112,11 +115,24 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		IsVerified:   false,
 	}
 
-	if err := h.db.Create(&user).Error; err != nil {
+	// The account and its outbox event commit together
+	registered := domain.UserRegistered{User: &user}
+	if err := h.db.WithContext(c.Request.Context()).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&user).Error; err != nil {
+			return err
+		}
+		event, err := domain.NewOutboxEvent(registered)
+		if err != nil {
+			return err
+		}
+		return tx.Create(event).Error
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to create user"})
 		return
 	}
 
+	publish(c, h.events, registered)
+
 	// Generate tokens
 	accessToken, err := h.jwtManager.GenerateAccessToken(
 		user.ID,
@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"campusassistant-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GraduateRequest is the optional body of a graduation.
+type GraduateRequest struct {
+	PassingYear string `json:"passing_year"` // Defaults to the current year
+}
+
+// BatchProgressionHandler serves the year-end actions on a batch. Both
+// take ?dry_run=true to preview the outcome without changing anything.
+type BatchProgressionHandler struct {
+	progression *usecase.BatchProgression
+}
+
+func NewBatchProgressionHandler(progression *usecase.BatchProgression) *BatchProgressionHandler {
+	return &BatchProgressionHandler{progression: progression}
+}
+
+// Promote moves a batch to its next semester.
+// POST /batches/:id/promote?dry_run=true
+func (h *BatchProgressionHandler) Promote(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	plan, err := h.progression.Promote(c.Request.Context(), id, c.Query("dry_run") == "true")
+	if err != nil {
+		respondProgressionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+// Graduate marks a batch as graduated and adds its students to the alumni
+// directory.
+// POST /batches/:id/graduate?dry_run=true
+func (h *BatchProgressionHandler) Graduate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var req GraduateRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var createdBy uuid.UUID
+	if userID, exists := c.Get("user_id"); exists {
+		if uid, ok := userID.(uuid.UUID); ok {
+			createdBy = uid
+		}
+	}
+
+	plan, err := h.progression.Graduate(c.Request.Context(), id, req.PassingYear, createdBy, c.Query("dry_run") == "true")
+	if err != nil {
+		respondProgressionError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, plan)
+}
+
+func respondProgressionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch not found"})
+	case errors.Is(err, usecase.ErrBatchGraduated), errors.Is(err, usecase.ErrFinalSemester):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DonorProfileRequest opts the signed-in student in as a blood donor or
+// updates their entry. blood_group defaults to the one on their profile.
+type DonorProfileRequest struct {
+	BloodGroup    string     `json:"blood_group"`
+	LastDonatedAt *time.Time `json:"last_donated_at"`
+	IsAvailable   *bool      `json:"is_available"`
+}
+
+// BloodRequestRequest asks the eligible donors of a blood group, optionally
+// of one university, for help.
+type BloodRequestRequest struct {
+	BloodGroup   string     `json:"blood_group" binding:"required"`
+	UniversityID *uuid.UUID `json:"university_id"`
+	Hospital     string     `json:"hospital"`
+	Message      string     `json:"message"`
+	NeededBy     *time.Time `json:"needed_by"`
+}
+
+// BloodDonorHandler serves the opt-in donor registry and the request
+// contact flow. Listings never include phone numbers.
+type BloodDonorHandler struct {
+	donors *usecase.BloodDonors
+}
+
+func NewBloodDonorHandler(donors *usecase.BloodDonors) *BloodDonorHandler {
+	return &BloodDonorHandler{donors: donors}
+}
+
+// GetAll lists available donors past their donation cooldown, filtered by
+// blood_group, university_id and department_id.
+// GET /donors
+func (h *BloodDonorHandler) GetAll(c *gin.Context) {
+	limit, offset := pageParams(c)
+
+	filter := make(map[string]interface{})
+	if val := c.Query("blood_group"); val != "" {
+		group := domain.NormalizeBloodGroup(val)
+		if group == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": usecase.ErrInvalidBloodGroup.Error()})
+			return
+		}
+		filter["blood_group"] = group
+	}
+	for _, f := range []string{"university_id", "department_id"} {
+		if val := c.Query(f); val != "" {
+			id, err := uuid.Parse(val)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + f})
+				return
+			}
+			filter[f] = id
+		}
+	}
+
+	donors, count, err := h.donors.Eligible(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   donors,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetMe returns the signed-in user's donor entry.
+// GET /donors/me
+func (h *BloodDonorHandler) GetMe(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	donor, err := h.donors.Me(c.Request.Context(), uid)
+	if err != nil {
+		respondDonorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, donor)
+}
+
+// UpdateMe opts the signed-in student in, or updates their entry.
+// PUT /donors/me
+func (h *BloodDonorHandler) UpdateMe(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req DonorProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	donor, err := h.donors.OptIn(c.Request.Context(), uid, usecase.DonorProfile{
+		BloodGroup:    req.BloodGroup,
+		LastDonatedAt: req.LastDonatedAt,
+		IsAvailable:   req.IsAvailable,
+	})
+	if err != nil {
+		respondDonorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, donor)
+}
+
+// DeleteMe opts the signed-in user out and deletes their entry.
+// DELETE /donors/me
+func (h *BloodDonorHandler) DeleteMe(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.donors.OptOut(c.Request.Context(), uid); err != nil {
+		respondDonorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// CreateRequest notifies matching donors; see usecase.BloodDonors.Request.
+// POST /donors/requests
+func (h *BloodDonorHandler) CreateRequest(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var req BloodRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	request, err := h.donors.Request(c.Request.Context(), uid, domain.BloodRequest{
+		BloodGroup:   req.BloodGroup,
+		UniversityID: req.UniversityID,
+		Hospital:     req.Hospital,
+		Message:      req.Message,
+		NeededBy:     req.NeededBy,
+	})
+	if err != nil {
+		respondDonorError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, request)
+}
+
+// ListRequests lists the signed-in user's own requests.
+// GET /donors/requests
+func (h *BloodDonorHandler) ListRequests(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	limit, offset := pageParams(c)
+
+	requests, count, err := h.donors.Mine(c.Request.Context(), uid, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   requests,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetRequest returns a request to its requester (with the contact details
+// of donors who accepted) or to a donor it was sent to.
+// GET /donors/requests/:id
+func (h *BloodDonorHandler) GetRequest(c *gin.Context) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	request, err := h.donors.Get(c.Request.Context(), uid, id)
+	if err != nil {
+		respondDonorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, request)
+}
+
+// Accept shares the donor's phone number with the requester.
+// POST /donors/requests/:id/accept
+func (h *BloodDonorHandler) Accept(c *gin.Context) {
+	h.respond(c, true)
+}
+
+// Decline turns a request down.
+// POST /donors/requests/:id/decline
+func (h *BloodDonorHandler) Decline(c *gin.Context) {
+	h.respond(c, false)
+}
+
+func (h *BloodDonorHandler) respond(c *gin.Context, accept bool) {
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	match, err := h.donors.Respond(c.Request.Context(), uid, id, accept)
+	if err != nil {
+		respondDonorError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, match)
+}
+
+// pageParams reads limit (default 20, at most 100) and offset.
+func pageParams(c *gin.Context) (int, int) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if limit <= 0 {
+		limit = 20
+	} else if limit > 100 {
+		limit = 100
+	}
+	if offset < 0 {
+		offset = 0
+	}
+	return limit, offset
+}
+
+// currentUser returns the signed-in user's ID, or responds 401.
+func currentUser(c *gin.Context) (uuid.UUID, bool) {
+	userID, ok := c.Get("user_id")
+	uid, isUUID := userID.(uuid.UUID)
+	if !ok || !isUUID {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return uuid.Nil, false
+	}
+	return uid, true
+}
+
+func respondDonorError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, usecase.ErrNoEligibleDonors):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrNoStudentProfile):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidBloodGroup), errors.Is(err, usecase.ErrFutureDonation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrBloodMatchAnswered):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+	"campusassistant-api/pkg/codesheet"
+	"campusassistant-api/pkg/logger"
+	"campusassistant-api/pkg/spreadsheet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// claimSheetNote is printed on every PDF slip.
+const claimSheetNote = "Sign in to Campus Assistant and enter this code (or scan the QR code) under Claim profile. Do not share it."
+
+// BatchClaimCodes is the JSON form of a batch's freshly issued codes.
+type BatchClaimCodes struct {
+	BatchID   uuid.UUID          `json:"batch_id"`
+	BatchName string             `json:"batch_name"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`
+	Count     int                `json:"count"`
+	Codes     []StudentClaimCode `json:"codes"`
+}
+
+// StudentClaimCode is one student's new verification code.
+type StudentClaimCode struct {
+	ID               uuid.UUID `json:"id"`
+	Name             string    `json:"name"`
+	StudentID        string    `json:"student_id"`
+	VerificationCode string    `json:"verification_code"`
+}
+
+// ClaimCodeSheetHandler issues claim codes for a whole batch so they can be
+// handed out in person. Codes are stored hashed, so the response of this
+// request is the only copy; calling it again replaces them.
+type ClaimCodeSheetHandler struct {
+	batches  usecase.Usecase[domain.Batch]
+	students usecase.Usecase[domain.Student]
+}
+
+func NewClaimCodeSheetHandler(batches usecase.Usecase[domain.Batch], students usecase.Usecase[domain.Student]) *ClaimCodeSheetHandler {
+	return &ClaimCodeSheetHandler{batches: batches, students: students}
+}
+
+// Generate issues new codes for the unclaimed students of a batch and
+// returns them as JSON, CSV/XLSX or a printable PDF with QR codes.
+// POST /batches/:id/claim-codes?format=csv|xlsx|pdf&only_missing=true
+func (h *ClaimCodeSheetHandler) Generate(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	issuer, ok := h.students.(usecase.ClaimCodeIssuer[domain.Student])
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": usecase.ErrClaimCodesDisabled.Error()})
+		return
+	}
+
+	batch, err := h.batches.GetByID(c.Request.Context(), id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	students, err := issuer.IssueClaimCodes(c.Request.Context(), map[string]interface{}{"batch_id": id}, c.Query("only_missing") == "true")
+	if err != nil {
+		respondClaimCodeError(c, err)
+		return
+	}
+	sort.SliceStable(students, func(i, j int) bool {
+		if students[i].StudentID != students[j].StudentID {
+			return students[i].StudentID < students[j].StudentID
+		}
+		return students[i].Name < students[j].Name
+	})
+
+	out := BatchClaimCodes{
+		BatchID:   batch.ID,
+		BatchName: batch.Name,
+		Count:     len(students),
+		Codes:     make([]StudentClaimCode, len(students)),
+	}
+	for i, s := range students {
+		out.Codes[i] = StudentClaimCode{ID: s.ID, Name: s.Name, StudentID: s.StudentID, VerificationCode: s.IssuedCode}
+		if out.ExpiresAt == nil {
+			out.ExpiresAt = s.CodeExpiresAt
+		}
+	}
+
+	// The codes exist only in this response; keep proxies from storing it
+	c.Header("Cache-Control", "no-store")
+	filename := "claim-codes-" + batch.Slug
+
+	if strings.ToLower(c.Query("format")) == "pdf" || strings.Contains(c.GetHeader("Accept"), "application/pdf") {
+		// Render fully first so a failure is still reported as an error
+		var buf bytes.Buffer
+		if err := codesheet.WritePDF(&buf, claimSheet(out)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.pdf"`, filename))
+		c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+		return
+	}
+
+	if format, ok := exportFormat(c); ok {
+		c.Header("Content-Type", spreadsheet.ContentType(format))
+		c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.%s"`, filename, format))
+		c.Status(http.StatusOK)
+		if err := writeClaimCodes(c, format, out); err != nil {
+			logger.Errorf("claim code export for batch %s failed: %v", id, err)
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func writeClaimCodes(c *gin.Context, format spreadsheet.Format, out BatchClaimCodes) error {
+	w, err := spreadsheet.NewWriter(c.Writer, format)
+	if err != nil {
+		return err
+	}
+	expires := ""
+	if out.ExpiresAt != nil {
+		expires = out.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if err := w.Write([]string{"name", "student_id", "verification_code", "expires_at"}); err != nil {
+		return err
+	}
+	for _, code := range out.Codes {
+		if err := w.Write([]string{code.Name, code.StudentID, code.VerificationCode, expires}); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+func claimSheet(out BatchClaimCodes) codesheet.Sheet {
+	subtitle := fmt.Sprintf("%d codes", out.Count)
+	if out.ExpiresAt != nil {
+		subtitle += ", valid until " + out.ExpiresAt.Format("2 Jan 2006")
+	}
+
+	sheet := codesheet.Sheet{
+		Title:    "Profile claim codes - " + out.BatchName,
+		Subtitle: subtitle,
+		Note:     claimSheetNote,
+		Entries:  make([]codesheet.Entry, len(out.Codes)),
+	}
+	for i, code := range out.Codes {
+		sheet.Entries[i] = codesheet.Entry{Name: code.Name, Reference: "ID: " + code.StudentID, Code: code.VerificationCode}
+	}
+	return sheet
+}
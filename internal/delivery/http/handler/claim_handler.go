@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ClaimRequest carries the verification code handed out with a profile.
+type ClaimRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+// ClaimHandler lets users find and claim the roster profile (student,
+// teacher, staff or alumni) created for them, and admins reissue its code.
+// T must implement domain.Claimable.
+type ClaimHandler[T any] struct {
+	profiles usecase.Usecase[T]
+	claims   domain.ClaimRepository[T]
+	codes    *usecase.ClaimCodes
+	users    usecase.Usecase[domain.User]
+	events   EventSink
+
+	// Details, when set, copies extra fields of the claim request body onto
+	// the profile before it is saved.
+	Details func(c *gin.Context, profile *T) error
+}
+
+func NewClaimHandler[T any](profiles usecase.Usecase[T], claims domain.ClaimRepository[T], codes *usecase.ClaimCodes, users usecase.Usecase[domain.User], events EventSink) *ClaimHandler[T] {
+	return &ClaimHandler[T]{profiles: profiles, claims: claims, codes: codes, users: users, events: events}
+}
+
+// VerifyCode returns the unclaimed profile a code belongs to.
+// POST /<profiles>/verify-code
+func (h *ClaimHandler[T]) VerifyCode(c *gin.Context) {
+	var req ClaimRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Code is required"})
+		return
+	}
+
+	profile, ok := h.findByCode(c, req.Code)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, profile)
+}
+
+// ClaimProfile links the profile a code belongs to with the authenticated
+// user and gives the user the matching role, if the profile has one.
+// POST /<profiles>/claim-profile
+func (h *ClaimHandler[T]) ClaimProfile(c *gin.Context) {
+	var req ClaimRequest
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	userID, ok := c.Get("user_id")
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+	uid, ok := userID.(uuid.UUID)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	user, err := h.users.GetByID(ctx, uid)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	owned, _, err := h.profiles.GetAll(ctx, map[string]interface{}{"user_id": uid}, 1, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if len(owned) > 0 {
+		c.JSON(http.StatusConflict, gin.H{"error": "You have already claimed a profile"})
+		return
+	}
+
+	profile, ok := h.findByCode(c, req.Code)
+	if !ok {
+		return
+	}
+	if h.Details != nil {
+		if err := h.Details(c, profile); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	claimable := any(profile).(domain.Claimable)
+	hash, _ := claimable.ClaimCode()
+	now := time.Now()
+	claimable.Claim(uid, now)
+
+	role := claimable.ProfileRole()
+	promote := role != "" && user.Role.IsProfileRole() && user.Role != role
+	if promote {
+		user.Role = role
+	}
+
+	universityID, departmentID := claimable.ClaimScope()
+	claimed := domain.ProfileClaimed{
+		ProfileType:  claimable.ProfileType(),
+		ProfileID:    claimable.GetID(),
+		UserID:       uid,
+		UniversityID: universityID,
+		DepartmentID: departmentID,
+	}
+	err = h.events.write(c, claimed, func(ctx context.Context) error {
+		// The conditional update decides races; the rest only runs for the winner
+		if err := h.claims.Claim(ctx, claimable.GetID(), hash, uid, now); err != nil {
+			return err
+		}
+		if h.Details != nil {
+			if err := h.profiles.Update(ctx, profile); err != nil {
+				return err
+			}
+		}
+		if promote {
+			return h.users.Update(ctx, user)
+		}
+		return nil
+	})
+	if errors.Is(err, domain.ErrAlreadyClaimed) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Profile has already been claimed"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to claim profile: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// ReissueCode replaces the verification code of an unclaimed profile with
+// a new one and restarts its expiry. The response is the only place the
+// new code is shown.
+// POST /<profiles>/:id/claim-code
+func (h *ClaimHandler[T]) ReissueCode(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	issuer, ok := h.profiles.(usecase.ClaimCodeIssuer[T])
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": usecase.ErrClaimCodesDisabled.Error()})
+		return
+	}
+
+	profile, err := h.profiles.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	if userID, exists := c.Get("user_id"); exists {
+		if uid, ok := userID.(uuid.UUID); ok {
+			if auditable, ok := any(profile).(domain.Auditable); ok {
+				auditable.SetUpdatedBy(uid)
+			}
+		}
+	}
+
+	if err := issuer.IssueClaimCode(c.Request.Context(), profile); err != nil {
+		respondClaimCodeError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, withIssuedCode(profile))
+}
+
+// withIssuedCode renders a profile with the plain verification code it was
+// just issued. The code is not part of the model's JSON, so it never reaches
+// audit logs, revisions or event payloads.
+func withIssuedCode(profile any) any {
+	p, ok := profile.(domain.Claimable)
+	if !ok || p.IssuedClaimCode() == "" {
+		return profile
+	}
+
+	raw, err := json.Marshal(profile)
+	if err != nil {
+		return profile
+	}
+	out := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return profile
+	}
+	out["verification_code"] = p.IssuedClaimCode()
+	return out
+}
+
+func respondClaimCodeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, domain.ErrAlreadyClaimed):
+		c.JSON(http.StatusConflict, gin.H{"error": "Profile has already been claimed"})
+	case errors.Is(err, usecase.ErrClaimCodesDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func (h *ClaimHandler[T]) findByCode(c *gin.Context, code string) (*T, bool) {
+	profile, err := h.claims.FindByClaimCode(c.Request.Context(), h.codes.Hash(code), time.Now())
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid, expired or already claimed code"})
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return profile, true
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CourseTeachersRequest replaces the teachers of a course offering.
+type CourseTeachersRequest struct {
+	TeacherIDs []uuid.UUID `json:"teacher_ids"`
+}
+
+// CourseOfferingHandler serves course offerings and their teacher
+// assignments. Writes go through usecase.CourseOfferings, which checks that
+// the course, batch, semester and teachers fit together; reads use the
+// generic handler.
+type CourseOfferingHandler struct {
+	*GenericHandler[domain.CourseOffering]
+	offerings *usecase.CourseOfferings
+}
+
+func NewCourseOfferingHandler(u usecase.Usecase[domain.CourseOffering], offerings *usecase.CourseOfferings) *CourseOfferingHandler {
+	return &CourseOfferingHandler{
+		GenericHandler: NewGenericHandler(u),
+		offerings:      offerings,
+	}
+}
+
+// Create offers a course to a batch, assigning teacher_ids.
+// POST /course-offerings
+func (h *CourseOfferingHandler) Create(c *gin.Context) {
+	var offering domain.CourseOffering
+	if err := c.ShouldBindJSON(&offering); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	offering.SetCreatedBy(uid)
+	offering.SetUpdatedBy(uid)
+
+	created, err := h.offerings.Create(c.Request.Context(), &offering)
+	if err != nil {
+		respondCourseOfferingError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update changes the course, batch, semester, session or section.
+// PUT /course-offerings/:id
+func (h *CourseOfferingHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var changes domain.CourseOffering
+	if err := c.ShouldBindJSON(&changes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	changes.ID = id
+	changes.SetUpdatedBy(uid)
+
+	offering, err := h.offerings.Update(c.Request.Context(), &changes)
+	if err != nil {
+		respondCourseOfferingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, offering)
+}
+
+// SetTeachers replaces the teachers of an offering.
+// PUT /course-offerings/:id/teachers
+func (h *CourseOfferingHandler) SetTeachers(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	var req CourseTeachersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	offering, err := h.offerings.SetTeachers(c.Request.Context(), id, req.TeacherIDs)
+	if err != nil {
+		respondCourseOfferingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, offering)
+}
+
+// AddTeacher assigns one teacher to an offering.
+// POST /course-offerings/:id/teachers/:teacher_id
+func (h *CourseOfferingHandler) AddTeacher(c *gin.Context) {
+	h.changeTeacher(c, h.offerings.AddTeacher)
+}
+
+// RemoveTeacher unassigns one teacher from an offering.
+// DELETE /course-offerings/:id/teachers/:teacher_id
+func (h *CourseOfferingHandler) RemoveTeacher(c *gin.Context) {
+	h.changeTeacher(c, h.offerings.RemoveTeacher)
+}
+
+func (h *CourseOfferingHandler) changeTeacher(c *gin.Context, fn func(ctx context.Context, id, teacherID uuid.UUID) (*domain.CourseOffering, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	teacherID, err := uuid.Parse(c.Param("teacher_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid teacher ID format"})
+		return
+	}
+
+	offering, err := fn(c.Request.Context(), id, teacherID)
+	if err != nil {
+		respondCourseOfferingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, offering)
+}
+
+// TeacherCourses lists the offerings the signed-in teacher is assigned to,
+// with the usual list filters (batch_id, semester_id, session_id, ...).
+// GET /teachers/me/courses
+func (h *CourseOfferingHandler) TeacherCourses(c *gin.Context) {
+	limit, offset := pageParams(c)
+	filter := listFilter(c)
+	delete(filter, "preload")
+
+	offerings, count, err := h.offerings.TeacherCourses(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		respondCourseOfferingError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   offerings,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// StudentCourses returns the signed-in student's current semester and its
+// course offerings.
+// GET /students/me/courses
+func (h *CourseOfferingHandler) StudentCourses(c *gin.Context) {
+	courses, err := h.offerings.StudentCourses(c.Request.Context())
+	if err != nil {
+		respondCourseOfferingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, courses)
+}
+
+func respondCourseOfferingError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, usecase.ErrNoTeacherProfile), errors.Is(err, usecase.ErrNoStudentProfile):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrOfferingMismatch), errors.Is(err, usecase.ErrUnknownTeacher):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrDuplicateOffering):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -3,32 +3,178 @@ package handler
 import (
 	"campusassistant-api/internal/domain"
 	"campusassistant-api/internal/usecase"
+	"errors"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+// AppointCRRequest starts a CR term for a roster student. Name, contact
+// details and batch are copied from the student.
+type AppointCRRequest struct {
+	TargetStudentID uuid.UUID  `json:"target_student_id" binding:"required"`
+	TermStart       *time.Time `json:"term_start"`
+	TermEnd         *time.Time `json:"term_end"`
+	Fb              string     `json:"fb"`
+	ImageURL        string     `json:"image_url"`
+}
+
+// CrHandler serves CR records. Terms are started, handed over and ended
+// through the lifecycle, which keeps the students' IsCR flag and roles in
+// sync; reads use the generic handler.
 type CrHandler struct {
 	*GenericHandler[domain.CR]
+	lifecycle *usecase.CRLifecycle
 }
 
-func NewCrHandler(u usecase.Usecase[domain.CR]) *CrHandler {
+func NewCrHandler(u usecase.Usecase[domain.CR], lifecycle *usecase.CRLifecycle) *CrHandler {
 	return &CrHandler{
 		GenericHandler: NewGenericHandler(u),
+		lifecycle:      lifecycle,
 	}
 }
 
+// Create appoints a CR.
+// POST /crs
 func (h *CrHandler) Create(c *gin.Context) {
-	var cr domain.CR
-	if err := c.ShouldBindJSON(&cr); err != nil {
+	h.appoint(c, nil)
+}
+
+// Handover ends the term :id and appoints its successor in one step.
+// POST /crs/:id/handover
+func (h *CrHandler) Handover(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	h.appoint(c, &id)
+}
+
+func (h *CrHandler) appoint(c *gin.Context, replaces *uuid.UUID) {
+	var req AppointCRRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	appointment := usecase.CRAppointment{
+		StudentID: req.TargetStudentID,
+		TermStart: req.TermStart,
+		TermEnd:   req.TermEnd,
+		Fb:        req.Fb,
+		ImageURL:  req.ImageURL,
+		Replaces:  replaces,
+	}
+	if userID, exists := c.Get("user_id"); exists {
+		if uid, ok := userID.(uuid.UUID); ok {
+			appointment.AppointedBy = uid
+		}
+	}
+
+	cr, err := h.lifecycle.Appoint(c.Request.Context(), appointment)
+	if err != nil {
+		respondCRError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, cr)
+}
+
+// End ends a current term early.
+// POST /crs/:id/end
+func (h *CrHandler) End(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	cr, err := h.lifecycle.End(c.Request.Context(), id)
+	if err != nil {
+		respondCRError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cr)
+}
+
+// Update edits a term's details; see usecase.CRLifecycle.Update for what
+// can change.
+// PUT /crs/:id
+func (h *CrHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	var changes domain.CR
+	if err := c.ShouldBindJSON(&changes); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	changes.ID = id
+	if userID, exists := c.Get("user_id"); exists {
+		if uid, ok := userID.(uuid.UUID); ok {
+			changes.SetUpdatedBy(uid)
+		}
+	}
+
+	cr, err := h.lifecycle.Update(c.Request.Context(), &changes)
+	if err != nil {
+		respondCRError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, cr)
+}
+
+// Delete removes a CR record, ending its term first.
+// DELETE /crs/:id
+func (h *CrHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
 
-	if err := h.Usecase.Create(c.Request.Context(), &cr); err != nil {
+	if err := h.lifecycle.Remove(c.Request.Context(), id); err != nil {
+		respondCRError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// History lists every CR term of a batch, current ones first.
+// GET /batches/:id/crs/history
+func (h *CrHandler) History(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	crs, err := h.lifecycle.History(c.Request.Context(), id)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  crs,
+		"count": len(crs),
+	})
+}
 
-	c.JSON(http.StatusCreated, cr)
+func respondCRError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, usecase.ErrInvalidCRTerm):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrCRLimitReached), errors.Is(err, usecase.ErrAlreadyCR), errors.Is(err, usecase.ErrCRNotCurrent):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
 }
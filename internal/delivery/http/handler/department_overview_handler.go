@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"campusassistant-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DepartmentOverviewHandler serves the department home screen in one call.
+type DepartmentOverviewHandler struct {
+	usecase *usecase.DepartmentOverviewUsecase
+}
+
+func NewDepartmentOverviewHandler(u *usecase.DepartmentOverviewUsecase) *DepartmentOverviewHandler {
+	return &DepartmentOverviewHandler{usecase: u}
+}
+
+// Get returns the department, active batches, faculty, staff, current CRs
+// and course/resource counts.
+// GET /departments/:id/overview
+func (h *DepartmentOverviewHandler) Get(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	overview, err := h.usecase.Get(c.Request.Context(), id)
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+	case errors.Is(err, context.DeadlineExceeded):
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "Department overview timed out"})
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusOK, overview)
+	}
+}
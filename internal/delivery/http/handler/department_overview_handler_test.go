@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// overviewRepo knows one department; lookups of it block while slow is set.
+// Every other section is empty.
+type overviewRepo struct {
+	known uuid.UUID
+	slow  bool
+}
+
+func (r *overviewRepo) Department(ctx context.Context, id uuid.UUID) (*domain.Department, error) {
+	if r.slow {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if id != r.known {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &domain.Department{Base: domain.Base{ID: id}, Name: "CSE"}, nil
+}
+
+func (r *overviewRepo) ActiveBatches(context.Context, uuid.UUID) ([]domain.Batch, error) {
+	return nil, nil
+}
+
+func (r *overviewRepo) StudentCounts(context.Context, []uuid.UUID) (map[uuid.UUID]int64, error) {
+	return nil, nil
+}
+
+func (r *overviewRepo) Teachers(context.Context, uuid.UUID) ([]domain.Teacher, error) {
+	return nil, nil
+}
+
+func (r *overviewRepo) Staff(context.Context, uuid.UUID) ([]domain.Staff, error) { return nil, nil }
+func (r *overviewRepo) CurrentCRs(context.Context, uuid.UUID) ([]domain.CR, error) {
+	return nil, nil
+}
+func (r *overviewRepo) CountCourses(context.Context, uuid.UUID) (int64, error)   { return 0, nil }
+func (r *overviewRepo) CountResources(context.Context, uuid.UUID) (int64, error) { return 0, nil }
+
+func TestDepartmentOverviewStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	known := uuid.New()
+
+	tests := []struct {
+		name string
+		id   string
+		slow bool
+		want int
+	}{
+		{"known department", known.String(), false, http.StatusOK},
+		{"missing department", uuid.New().String(), false, http.StatusNotFound},
+		{"malformed ID", "cse", false, http.StatusBadRequest},
+		{"department lookup times out", known.String(), true, http.StatusGatewayTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &overviewRepo{known: known, slow: tt.slow}
+			h := NewDepartmentOverviewHandler(usecase.NewDepartmentOverviewUsecase(repo, 50*time.Millisecond))
+			r := gin.New()
+			r.GET("/departments/:id/overview", h.Get)
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/departments/"+tt.id+"/overview", nil))
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
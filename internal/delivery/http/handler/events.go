@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/eventbus"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// EventSink records workflow events raised by handlers: in the outbox, in
+// the same transaction as the write that caused them, and on the in-process
+// bus once that transaction has committed. Either part may be nil.
+type EventSink struct {
+	Outbox domain.Outbox
+	Bus    *eventbus.Bus
+}
+
+// write runs fn and records e with it. Bus subscriber failures are logged;
+// the write has already committed by then.
+func (s EventSink) write(c *gin.Context, e domain.Event, fn func(ctx context.Context) error) error {
+	ctx := c.Request.Context()
+
+	var err error
+	if s.Outbox == nil {
+		err = fn(ctx)
+	} else {
+		err = s.Outbox.Transaction(ctx, func(ctx context.Context) error {
+			if err := fn(ctx); err != nil {
+				return err
+			}
+			return s.Outbox.Add(ctx, e)
+		})
+	}
+	if err != nil {
+		return err
+	}
+
+	publish(c, s.Bus, e)
+	return nil
+}
+
+// publish emits a domain event on the in-process bus after a successful
+// write. The response is not affected by subscriber failures; they are logged.
+func publish(c *gin.Context, bus *eventbus.Bus, e eventbus.Event) {
+	if err := bus.Publish(c.Request.Context(), e); err != nil {
+		logger.Errorf("%s subscribers failed: %v", e.EventName(), err)
+	}
+}
@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+	"campusassistant-api/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExamHandler serves batches' exam schedules. Writes go through
+// usecase.Exams, which lets only academic admins and the batch's CRs change
+// them; reads use the generic handler.
+type ExamHandler struct {
+	*GenericHandler[domain.Exam]
+	exams *usecase.Exams
+}
+
+func NewExamHandler(u usecase.Usecase[domain.Exam], exams *usecase.Exams) *ExamHandler {
+	return &ExamHandler{
+		GenericHandler: NewGenericHandler(u),
+		exams:          exams,
+	}
+}
+
+// Create schedules an exam with its syllabus chapter_ids.
+// POST /exams
+func (h *ExamHandler) Create(c *gin.Context) {
+	var exam domain.Exam
+	if err := c.ShouldBindJSON(&exam); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	exam.SetCreatedBy(uid)
+	exam.SetUpdatedBy(uid)
+
+	created, err := h.exams.Create(c.Request.Context(), &exam)
+	if err != nil {
+		respondExamError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update reschedules an exam; chapter_ids, when given, replaces the
+// syllabus.
+// PUT /exams/:id
+func (h *ExamHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var changes domain.Exam
+	if err := c.ShouldBindJSON(&changes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	changes.ID = id
+	changes.SetUpdatedBy(uid)
+
+	exam, err := h.exams.Update(c.Request.Context(), &changes)
+	if err != nil {
+		respondExamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, exam)
+}
+
+// Delete cancels an exam.
+// DELETE /exams/:id
+func (h *ExamHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := h.exams.Delete(c.Request.Context(), id); err != nil {
+		respondExamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// Upcoming lists the batch's exams that have not ended, soonest first,
+// optionally of one ?type=.
+// GET /batches/:id/exams
+func (h *ExamHandler) Upcoming(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	limit, offset := pageParams(c)
+
+	exams, count, err := h.exams.Upcoming(c.Request.Context(), id, c.Query("type"), limit, offset)
+	if err != nil {
+		respondExamError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":   exams,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// Calendar serves the batch's exams as an iCalendar feed that calendar
+// apps can subscribe to. It needs no API key.
+// GET /batches/:id/exams.ics
+func (h *ExamHandler) Calendar(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	cal, err := h.exams.Calendar(c.Request.Context(), id)
+	if err != nil {
+		respondExamError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="exams.ics"`)
+	c.Data(http.StatusOK, ical.ContentType, cal.Bytes())
+}
+
+func respondExamError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, usecase.ErrNotBatchEditor):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidExam), errors.Is(err, usecase.ErrChapterNotInCourse):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
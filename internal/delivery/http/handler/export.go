@@ -0,0 +1,367 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/logger"
+	"campusassistant-api/pkg/spreadsheet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	uuidType      = reflect.TypeOf(uuid.UUID{})
+	jsonType      = reflect.TypeOf(datatypes.JSON{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+)
+
+// exportFormat picks CSV/XLSX from ?format= or the Accept header.
+func exportFormat(c *gin.Context) (spreadsheet.Format, bool) {
+	switch strings.ToLower(c.Query("format")) {
+	case "csv":
+		return spreadsheet.FormatCSV, true
+	case "xlsx":
+		return spreadsheet.FormatXLSX, true
+	}
+
+	accept := c.GetHeader("Accept")
+	if strings.Contains(accept, "text/csv") {
+		return spreadsheet.FormatCSV, true
+	}
+	if strings.Contains(accept, "spreadsheetml.sheet") {
+		return spreadsheet.FormatXLSX, true
+	}
+	return "", false
+}
+
+// exportColumn is one exported struct field. JSONB fields holding objects are
+// flattened into one column per key ("metadata.author"); anything else in a
+// JSONB field is written as raw JSON text.
+type exportColumn struct {
+	name   string
+	index  []int
+	isJSON bool
+	keys   map[string]bool
+	raw    bool
+	titles []string
+}
+
+// header returns the column titles for this field.
+func (col *exportColumn) header() []string {
+	if !col.isJSON || col.raw || len(col.keys) == 0 {
+		return []string{col.name}
+	}
+	keys := make([]string, 0, len(col.keys))
+	for k := range col.keys {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// exportColumns lists the scalar and JSONB fields of t by json tag, walking
+// embedded structs. Associations (nested structs and slices of structs) are skipped.
+// Use allowedColumns to narrow them to what the model allows exporting.
+func exportColumns(t reflect.Type) []*exportColumn {
+	var cols []*exportColumn
+
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			idx := append(append([]int{}, index...), i)
+
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				walk(f.Type, idx)
+				continue
+			}
+			if !f.IsExported() {
+				continue
+			}
+
+			name := strings.Split(f.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+
+			ft := f.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+
+			switch {
+			case ft == jsonType:
+				cols = append(cols, &exportColumn{name: name, index: idx, isJSON: true, keys: map[string]bool{}})
+			case ft == deletedAtType:
+				continue
+			case ft == timeType || ft == uuidType:
+				cols = append(cols, &exportColumn{name: name, index: idx})
+			case ft.Kind() == reflect.Struct, ft.Kind() == reflect.Map:
+				continue
+			case ft.Kind() == reflect.Slice && ft.Elem().Kind() == reflect.Struct:
+				continue
+			default:
+				cols = append(cols, &exportColumn{name: name, index: idx})
+			}
+		}
+	}
+	walk(t, nil)
+
+	return cols
+}
+
+// allowedColumns keeps the columns the model lists in ExportColumns, in
+// that order.
+func allowedColumns(cols []*exportColumn, allowed []string) []*exportColumn {
+	byName := make(map[string]*exportColumn, len(cols))
+	for _, col := range cols {
+		byName[col.name] = col
+	}
+	var out []*exportColumn
+	for _, name := range allowed {
+		if col, ok := byName[name]; ok {
+			out = append(out, col)
+		}
+	}
+	return out
+}
+
+// restrictExport narrows an export filter to the scope by the model's
+// university_id and department_id columns. It reports false when the model
+// lacks a column the scope needs, so scoped admins cannot export it.
+func (s adminScope) restrictExport(cols []*exportColumn, filter map[string]interface{}) bool {
+	has := make(map[string]bool, len(cols))
+	for _, col := range cols {
+		has[col.name] = true
+	}
+	if (s.UniversityID != nil && !has["university_id"]) || (s.DepartmentID != nil && !has["department_id"]) {
+		return false
+	}
+	s.restrict(filter)
+	return true
+}
+
+// decodeJSONField returns the parsed JSON value of a datatypes.JSON field.
+func decodeJSONField(v reflect.Value) (interface{}, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, false
+		}
+		v = v.Elem()
+	}
+	raw, ok := v.Interface().(datatypes.JSON)
+	if !ok || len(raw) == 0 {
+		return nil, false
+	}
+	var out interface{}
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return string(raw), true
+	}
+	return out, true
+}
+
+// flattenJSON writes nested object keys as dotted column names.
+func flattenJSON(prefix string, val interface{}, out map[string]string) {
+	switch v := val.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			flattenJSON(prefix+"."+k, child, out)
+		}
+	case nil:
+		out[prefix] = ""
+	case string:
+		out[prefix] = v
+	case float64:
+		out[prefix] = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		out[prefix] = strconv.FormatBool(v)
+	default:
+		raw, _ := json.Marshal(v)
+		out[prefix] = string(raw)
+	}
+}
+
+// formatCell renders a scalar field for a spreadsheet cell.
+func formatCell(v reflect.Value) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+
+	switch v.Type() {
+	case timeType:
+		t := v.Interface().(time.Time)
+		if t.IsZero() {
+			return ""
+		}
+		return t.Format(time.RFC3339)
+	case uuidType:
+		id := v.Interface().(uuid.UUID)
+		if id == uuid.Nil {
+			return ""
+		}
+		return id.String()
+	}
+
+	if v.Kind() == reflect.Slice && v.Type().Elem().Kind() != reflect.Uint8 {
+		parts := make([]string, v.Len())
+		for i := range parts {
+			parts[i] = formatCell(v.Index(i))
+		}
+		return strings.Join(parts, "; ")
+	}
+
+	return fmt.Sprint(v.Interface())
+}
+
+// export streams the filtered collection as CSV/XLSX, bypassing the page cap.
+// Only admins can export, only the columns T allows (see domain.Exportable)
+// and only the records of their university or department. When T has JSONB
+// fields a first cursor pass discovers their keys so the header is complete
+// before any row is written.
+func (h *GenericHandler[T]) export(c *gin.Context, filter map[string]interface{}, format spreadsheet.Format) {
+	if _, ok := c.Get("user_role"); !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Authorization header is required"})
+		return
+	}
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+	exportable, ok := any(new(T)).(domain.Exportable)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "This list cannot be exported"})
+		return
+	}
+	all := exportColumns(reflect.TypeOf(new(T)).Elem())
+	if !scope.restrictExport(all, filter) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		return
+	}
+
+	ctx := c.Request.Context()
+	cols := allowedColumns(all, exportable.ExportColumns())
+
+	copyFilter := func() map[string]interface{} {
+		f := make(map[string]interface{}, len(filter))
+		for k, v := range filter {
+			f[k] = v
+		}
+		return f
+	}
+
+	hasJSON := false
+	for _, col := range cols {
+		hasJSON = hasJSON || col.isJSON
+	}
+	if hasJSON {
+		err := h.Usecase.Stream(ctx, copyFilter(), func(entity *T) error {
+			v := reflect.ValueOf(entity).Elem()
+			for _, col := range cols {
+				if !col.isJSON || col.raw {
+					continue
+				}
+				val, ok := decodeJSONField(v.FieldByIndex(col.index))
+				if !ok {
+					continue
+				}
+				obj, isObj := val.(map[string]interface{})
+				if !isObj {
+					col.raw = true
+					continue
+				}
+				flat := make(map[string]string)
+				flattenJSON(col.name, obj, flat)
+				for k := range flat {
+					col.keys[k] = true
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	var header []string
+	for _, col := range cols {
+		col.titles = col.header()
+		header = append(header, col.titles...)
+	}
+
+	resource := c.FullPath()
+	resource = resource[strings.LastIndex(resource, "/")+1:]
+	filename := fmt.Sprintf("%s-%s.%s", resource, time.Now().Format("2006-01-02"), format)
+
+	c.Header("Content-Type", spreadsheet.ContentType(format))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	w, err := spreadsheet.NewWriter(c.Writer, format)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := w.Write(header); err != nil {
+		logger.Errorf("export %s: %v", resource, err)
+		return
+	}
+
+	err = h.Usecase.Stream(ctx, copyFilter(), func(entity *T) error {
+		v := reflect.ValueOf(entity).Elem()
+		record := make([]string, 0, len(header))
+		for _, col := range cols {
+			field := v.FieldByIndex(col.index)
+			if !col.isJSON {
+				record = append(record, formatCell(field))
+				continue
+			}
+
+			val, ok := decodeJSONField(field)
+			if col.raw || len(col.keys) == 0 {
+				if ok {
+					raw, _ := json.Marshal(val)
+					record = append(record, string(raw))
+				} else {
+					record = append(record, "")
+				}
+				continue
+			}
+
+			flat := make(map[string]string)
+			if ok {
+				flattenJSON(col.name, val, flat)
+			}
+			for _, key := range col.titles {
+				record = append(record, flat[key])
+			}
+		}
+		return w.Write(record)
+	})
+	if err != nil {
+		// Headers are already sent; the client gets a truncated file.
+		logger.Errorf("export %s aborted: %v", resource, err)
+	}
+
+	if err := w.Close(); err != nil {
+		logger.Errorf("export %s: %v", resource, err)
+	}
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// streamUsecase serves Stream from a fixed list; the export needs nothing else.
+type streamUsecase[T any] struct {
+	rows []T
+}
+
+func (u *streamUsecase[T]) Create(context.Context, *T) error { return errors.New("not implemented") }
+func (u *streamUsecase[T]) GetByID(context.Context, uuid.UUID) (*T, error) {
+	return nil, errors.New("not implemented")
+}
+func (u *streamUsecase[T]) GetAll(context.Context, map[string]interface{}, int, int) ([]T, int64, error) {
+	return nil, 0, errors.New("not implemented")
+}
+func (u *streamUsecase[T]) Update(context.Context, *T) error { return errors.New("not implemented") }
+func (u *streamUsecase[T]) Delete(context.Context, uuid.UUID) error {
+	return errors.New("not implemented")
+}
+
+func (u *streamUsecase[T]) Stream(_ context.Context, _ map[string]interface{}, fn func(*T) error) error {
+	for i := range u.rows {
+		if err := fn(&u.rows[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestExportFlattensResourceMetadata(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metadata := datatypes.JSON(`{"author":"Cormen","edition":3,"publisher":{"name":"MIT Press","city":"Cambridge"}}`)
+	resource := domain.Resource{
+		Base:         domain.Base{ID: uuid.New()},
+		Title:        "Introduction to Algorithms",
+		Type:         "book",
+		UploaderUID:  "firebase-uid",
+		UniversityID: uuid.New(),
+		Metadata:     &metadata,
+	}
+
+	h := NewGenericHandler[domain.Resource](&streamUsecase[domain.Resource]{rows: []domain.Resource{resource}})
+	r := gin.New()
+	r.GET("/resources", func(c *gin.Context) {
+		c.Set("user_role", string(domain.RoleSuperAdmin))
+		h.GetAll(c)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/resources?format=csv", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body %s", w.Code, w.Body.String())
+	}
+
+	records, err := csv.NewReader(w.Body).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want header and one row", len(records))
+	}
+	row := make(map[string]string)
+	for i, title := range records[0] {
+		row[title] = records[1][i]
+	}
+
+	want := map[string]string{
+		"title":                   "Introduction to Algorithms",
+		"metadata.author":         "Cormen",
+		"metadata.edition":        "3",
+		"metadata.publisher.name": "MIT Press",
+		"metadata.publisher.city": "Cambridge",
+		"university_id":           resource.UniversityID.String(),
+	}
+	for col, val := range want {
+		if got, ok := row[col]; !ok || got != val {
+			t.Errorf("column %q = %q (present %v), want %q", col, got, ok, val)
+		}
+	}
+	for _, col := range []string{"metadata", "uploader_uid", "years"} {
+		if _, ok := row[col]; ok {
+			t.Errorf("column %q exported, want it left out", col)
+		}
+	}
+}
@@ -11,6 +11,14 @@ import (
 	"github.com/google/uuid"
 )
 
+// Query parameters GetAll turns into equality filters. Exported so the
+// OpenAPI builder documents the same set the handler accepts.
+var (
+	ListUUIDFilters   = []string{"university_id", "department_id", "session_id", "user_id", "uploader_id", "semester_id", "course_category_id", "batch_id", "teacher_id", "course_id"}
+	ListStringFilters = []string{"course_year", "course_category", "course_code", "name", "slug", "mode", "type", "status", "batch", "year", "scope", "category", "organization", "designation", "location", "passing_year", "section", "room"}
+	ListIntFilters    = []string{"lesson_no", "chapter_no", "weekday"}
+)
+
 type GenericHandler[T any] struct {
 	Usecase usecase.Usecase[T]
 }
@@ -41,7 +49,7 @@ func (h *GenericHandler[T]) Create(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusCreated, entity)
+	c.JSON(http.StatusCreated, withIssuedCode(&entity))
 }
 
 func (h *GenericHandler[T]) GetByID(c *gin.Context) {
@@ -75,27 +83,53 @@ func (h *GenericHandler[T]) GetAll(c *gin.Context) {
 		limit = 100
 	}
 
+	filter := listFilter(c)
+
+	// DEBUG: Print filter map
+	// fmt.Printf("DEBUG: GetAll Filter for %T: %+v\n", *new(T), filter)
+
+	// CSV/XLSX export streams every matching row instead of one page
+	if format, ok := exportFormat(c); ok {
+		delete(filter, "preload")
+		h.export(c, filter, format)
+		return
+	}
+
+	entities, count, err := h.Usecase.GetAll(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   entities,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// listFilter collects the list filters (ListUUIDFilters, ListStringFilters,
+// ListIntFilters, search and include_details) from the query string.
+func listFilter(c *gin.Context) map[string]interface{} {
 	filter := make(map[string]interface{})
 
 	// Add filters
-	uuidFilters := []string{"university_id", "department_id", "session_id", "user_id", "uploader_id", "semester_id", "course_category_id", "batch_id"}
-	for _, f := range uuidFilters {
+	for _, f := range ListUUIDFilters {
 		if val := c.Query(f); val != "" {
 			filter[f] = val
 		}
 	}
 
 	// Add string filters
-	stringFilters := []string{"course_year", "course_category", "course_code", "name", "slug", "mode", "type", "status", "batch", "year", "blood_group", "scope", "category"}
-	for _, f := range stringFilters {
+	for _, f := range ListStringFilters {
 		if val := c.Query(f); val != "" {
 			filter[f] = val
 		}
 	}
 
 	// Add integer filters
-	intFilters := []string{"lesson_no", "chapter_no"}
-	for _, f := range intFilters {
+	for _, f := range ListIntFilters {
 		if val := c.Query(f); val != "" {
 			if intVal, err := strconv.Atoi(val); err == nil {
 				filter[f] = intVal
@@ -111,21 +145,7 @@ func (h *GenericHandler[T]) GetAll(c *gin.Context) {
 		filter["preload"] = true
 	}
 
-	// DEBUG: Print filter map
-	// fmt.Printf("DEBUG: GetAll Filter for %T: %+v\n", *new(T), filter)
-
-	entities, count, err := h.Usecase.GetAll(c.Request.Context(), filter, limit, offset)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, gin.H{
-		"data":   entities,
-		"count":  count,
-		"limit":  limit,
-		"offset": offset,
-	})
+	return filter
 }
 
 func (h *GenericHandler[T]) Update(c *gin.Context) {
@@ -0,0 +1,294 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+	"campusassistant-api/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// BookAppointmentRequest books one slot of an office hour; starts_at must
+// be one of the times listed by GET /office-hours/:id/slots.
+type BookAppointmentRequest struct {
+	OfficeHourID uuid.UUID `json:"office_hour_id" binding:"required"`
+	StartsAt     time.Time `json:"starts_at" binding:"required"`
+	Purpose      string    `json:"purpose"`
+}
+
+// AppointmentResponseRequest is the teacher's optional note when approving
+// or declining.
+type AppointmentResponseRequest struct {
+	Response string `json:"response"`
+}
+
+// OfficeHourHandler serves teachers' weekly office hours. Writes go through
+// usecase.OfficeHours, which lets only the teacher or an academic admin
+// change them; reads use the generic handler.
+type OfficeHourHandler struct {
+	*GenericHandler[domain.OfficeHour]
+	hours *usecase.OfficeHours
+}
+
+func NewOfficeHourHandler(u usecase.Usecase[domain.OfficeHour], hours *usecase.OfficeHours) *OfficeHourHandler {
+	return &OfficeHourHandler{
+		GenericHandler: NewGenericHandler(u),
+		hours:          hours,
+	}
+}
+
+// Create publishes office hours for teacher_id.
+// POST /office-hours
+func (h *OfficeHourHandler) Create(c *gin.Context) {
+	var hour domain.OfficeHour
+	if err := c.ShouldBindJSON(&hour); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	hour.SetCreatedBy(uid)
+	hour.SetUpdatedBy(uid)
+
+	if err := h.hours.CreateHour(c.Request.Context(), &hour); err != nil {
+		respondOfficeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, hour)
+}
+
+// Update replaces the schedule, location, note and active flag.
+// PUT /office-hours/:id
+func (h *OfficeHourHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var changes domain.OfficeHour
+	if err := c.ShouldBindJSON(&changes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	changes.ID = id
+	changes.SetUpdatedBy(uid)
+
+	hour, err := h.hours.UpdateHour(c.Request.Context(), &changes)
+	if err != nil {
+		respondOfficeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, hour)
+}
+
+// Delete removes office hours; booked appointments stay.
+// DELETE /office-hours/:id
+func (h *OfficeHourHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := h.hours.DeleteHour(c.Request.Context(), id); err != nil {
+		respondOfficeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// Slots lists bookable times from ?from=YYYY-MM-DD (default today) for
+// ?days=N (default 14, at most 60).
+// GET /office-hours/:id/slots
+func (h *OfficeHourHandler) Slots(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", "14"))
+	if days <= 0 {
+		days = 14
+	} else if days > 60 {
+		days = 60
+	}
+
+	slots, err := h.hours.Slots(c.Request.Context(), id, c.Query("from"), days)
+	if err != nil {
+		respondOfficeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  slots,
+		"count": len(slots),
+	})
+}
+
+// AppointmentHandler serves office-hour bookings to the student and the
+// teacher involved.
+type AppointmentHandler struct {
+	hours *usecase.OfficeHours
+}
+
+func NewAppointmentHandler(hours *usecase.OfficeHours) *AppointmentHandler {
+	return &AppointmentHandler{hours: hours}
+}
+
+// Create books a slot for the signed-in student.
+// POST /appointments
+func (h *AppointmentHandler) Create(c *gin.Context) {
+	var req BookAppointmentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	appointment, err := h.hours.Book(c.Request.Context(), usecase.Booking{
+		OfficeHourID: req.OfficeHourID,
+		StartsAt:     req.StartsAt,
+		Purpose:      req.Purpose,
+	})
+	if err != nil {
+		respondOfficeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, appointment)
+}
+
+// GetAll lists the signed-in user's appointments as a student or teacher,
+// optionally with one ?status=.
+// GET /appointments
+func (h *AppointmentHandler) GetAll(c *gin.Context) {
+	limit, offset := pageParams(c)
+
+	appointments, count, err := h.hours.Mine(c.Request.Context(), c.Query("status"), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   appointments,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetByID returns an appointment to the people involved.
+// GET /appointments/:id
+func (h *AppointmentHandler) GetByID(c *gin.Context) {
+	appointment, ok := h.load(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, appointment)
+}
+
+// Calendar returns the appointment as an iCalendar file.
+// GET /appointments/:id/ics
+func (h *AppointmentHandler) Calendar(c *gin.Context) {
+	appointment, ok := h.load(c)
+	if !ok {
+		return
+	}
+	c.Header("Content-Disposition", `attachment; filename="appointment.ics"`)
+	c.Data(http.StatusOK, ical.ContentType, usecase.AppointmentCalendar(appointment).Bytes())
+}
+
+// Approve confirms a pending booking.
+// POST /appointments/:id/approve
+func (h *AppointmentHandler) Approve(c *gin.Context) {
+	h.decide(c, h.hours.Approve)
+}
+
+// Decline turns a pending or approved booking down.
+// POST /appointments/:id/decline
+func (h *AppointmentHandler) Decline(c *gin.Context) {
+	h.decide(c, h.hours.Decline)
+}
+
+func (h *AppointmentHandler) decide(c *gin.Context, fn func(ctx context.Context, id uuid.UUID, response string) (*domain.Appointment, error)) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	var req AppointmentResponseRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	appointment, err := fn(c.Request.Context(), id, req.Response)
+	if err != nil {
+		respondOfficeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, appointment)
+}
+
+// Cancel withdraws the signed-in student's booking.
+// POST /appointments/:id/cancel
+func (h *AppointmentHandler) Cancel(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	appointment, err := h.hours.Cancel(c.Request.Context(), id)
+	if err != nil {
+		respondOfficeHourError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, appointment)
+}
+
+func (h *AppointmentHandler) load(c *gin.Context) (*domain.Appointment, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return nil, false
+	}
+
+	appointment, err := h.hours.Get(c.Request.Context(), id)
+	if err != nil {
+		respondOfficeHourError(c, err)
+		return nil, false
+	}
+	return appointment, true
+}
+
+func respondOfficeHourError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, usecase.ErrNotOfficeHourOwner), errors.Is(err, usecase.ErrNoStudentProfile):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidOfficeHour), errors.Is(err, usecase.ErrSlotUnavailable), errors.Is(err, usecase.ErrInvalidDate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrSlotTaken), errors.Is(err, usecase.ErrStudentBusy), errors.Is(err, usecase.ErrAppointmentClosed):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"campusassistant-api/internal/domain"
 	"campusassistant-api/internal/usecase"
+	"context"
 	"net/http"
 	"time"
 
@@ -14,12 +15,14 @@ import (
 type ResourceHandler struct {
 	*GenericHandler[domain.Resource]
 	Usecase usecase.Usecase[domain.Resource]
+	events  EventSink
 }
 
-func NewResourceHandler(u usecase.Usecase[domain.Resource]) *ResourceHandler {
+func NewResourceHandler(u usecase.Usecase[domain.Resource], events EventSink) *ResourceHandler {
 	return &ResourceHandler{
 		GenericHandler: NewGenericHandler[domain.Resource](u),
 		Usecase:        u,
+		events:         events,
 	}
 }
 
@@ -51,14 +54,13 @@ func (h *ResourceHandler) ApproveResource(c *gin.Context) {
 		}
 	}
 
-	if err := h.Usecase.Update(c.Request.Context(), resource); err != nil {
+	if err := h.events.write(c, domain.ResourceApproved{Resource: resource}, func(ctx context.Context) error {
+		return h.Usecase.Update(ctx, resource)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// TODO: FCM — send push notification to resource.UploaderUID
-	// notificationService.Send(resource.UploaderUID, "Your submission was approved! 🎉", resource.Title)
-
 	c.JSON(http.StatusOK, resource)
 }
 
@@ -97,14 +99,14 @@ func (h *ResourceHandler) RejectResource(c *gin.Context) {
 		}
 	}
 
-	if err := h.Usecase.Update(c.Request.Context(), resource); err != nil {
+	rejected := domain.ResourceRejected{Resource: resource, Reason: body.Reason}
+	if err := h.events.write(c, rejected, func(ctx context.Context) error {
+		return h.Usecase.Update(ctx, resource)
+	}); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	// TODO: FCM — send push notification to resource.UploaderUID
-	// notificationService.Send(resource.UploaderUID, "Your submission needs revision ❌", body.Reason)
-
 	c.JSON(http.StatusOK, resource)
 }
 
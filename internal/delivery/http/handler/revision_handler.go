@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"campusassistant-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Revision endpoints are available on every GenericHandler whose usecase
+// implements usecase.RevisionUsecase and whose model opts in via domain.Versioned.
+
+// revisions returns the revision-capable usecase or writes a 404.
+func (h *GenericHandler[T]) revisions(c *gin.Context) (usecase.RevisionUsecase[T], bool) {
+	ru, ok := h.Usecase.(usecase.RevisionUsecase[T])
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": usecase.ErrRevisionsDisabled.Error()})
+		return nil, false
+	}
+	return ru, true
+}
+
+// ListRevisions returns every snapshot of an entity, newest first.
+// GET /:entity/:id/revisions
+func (h *GenericHandler[T]) ListRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	ru, ok := h.revisions(c)
+	if !ok {
+		return
+	}
+
+	revisions, err := ru.ListRevisions(c.Request.Context(), id)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  revisions,
+		"count": len(revisions),
+	})
+}
+
+// GetRevision returns a single snapshot.
+// GET /:entity/:id/revisions/:rev
+func (h *GenericHandler[T]) GetRevision(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	version, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	ru, ok := h.revisions(c)
+	if !ok {
+		return
+	}
+
+	rev, err := ru.GetRevision(c.Request.Context(), id, version)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, rev)
+}
+
+// DiffRevisions compares two snapshots field by field.
+// GET /:entity/:id/revisions/diff?from=1&to=2
+func (h *GenericHandler[T]) DiffRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	from, errFrom := strconv.Atoi(c.Query("from"))
+	to, errTo := strconv.Atoi(c.Query("to"))
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Query params 'from' and 'to' must be revision numbers"})
+		return
+	}
+
+	ru, ok := h.revisions(c)
+	if !ok {
+		return
+	}
+
+	changes, err := ru.DiffRevisions(c.Request.Context(), id, from, to)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"from":    from,
+		"to":      to,
+		"changes": changes,
+	})
+}
+
+// RestoreRevision rolls the entity back to a previous snapshot.
+// POST /:entity/:id/revisions/:rev/restore
+func (h *GenericHandler[T]) RestoreRevision(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	version, err := strconv.Atoi(c.Param("rev"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid revision number"})
+		return
+	}
+
+	ru, ok := h.revisions(c)
+	if !ok {
+		return
+	}
+
+	entity, err := ru.RestoreRevision(c.Request.Context(), id, version)
+	if err != nil {
+		respondRevisionError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, entity)
+}
+
+func respondRevisionError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrRevisionsDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
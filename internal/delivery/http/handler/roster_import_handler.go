@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"campusassistant-api/internal/usecase"
+	"campusassistant-api/pkg/spreadsheet"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// maxImportRows keeps a single upload within one request's time budget.
+const maxImportRows = 5000
+
+// RosterImportHandler accepts CSV/XLSX uploads for students, teachers and staff.
+type RosterImportHandler[T any] struct {
+	importer *usecase.RosterImporter[T]
+}
+
+func NewRosterImportHandler[T any](importer *usecase.RosterImporter[T]) *RosterImportHandler[T] {
+	return &RosterImportHandler[T]{importer: importer}
+}
+
+// Import parses the uploaded file and creates one entity per row.
+// POST /:entity/import (multipart: file, university_id, department_id, dry_run, skip_duplicates)
+//
+// Admins may only import into their own university (and department).
+// With dry_run=true nothing is written and the row-level report is returned.
+// Otherwise the file is rejected (422) if any row is invalid or duplicated,
+// unless skip_duplicates=true, in which case duplicate rows are left out.
+func (h *RosterImportHandler[T]) Import(c *gin.Context) {
+	caller, ok := callerScope(c)
+	if !ok {
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No file uploaded"})
+		return
+	}
+
+	format, err := spreadsheet.FormatFromFilename(file.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	universityID, err := uuid.Parse(formOrQuery(c, "university_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "university_id is required"})
+		return
+	}
+	departmentID, err := uuid.Parse(formOrQuery(c, "department_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "department_id is required"})
+		return
+	}
+	if !caller.covers(&universityID, &departmentID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "You can only import into your own university or department"})
+		return
+	}
+
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer f.Close()
+
+	rows, err := spreadsheet.ReadRows(f, format, maxImportRows)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	scope := usecase.RosterScope{UniversityID: universityID, DepartmentID: departmentID}
+	opts := usecase.ImportOptions{
+		DryRun:         formOrQuery(c, "dry_run") == "true",
+		SkipDuplicates: formOrQuery(c, "skip_duplicates") == "true",
+	}
+
+	report, err := h.importer.Import(c.Request.Context(), rows, scope, opts)
+	if errors.Is(err, usecase.ErrImportRejected) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error(), "report": report})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if report.Created > 0 {
+		status = http.StatusCreated
+	}
+	c.JSON(status, report)
+}
+
+// formOrQuery reads a multipart form value, falling back to the query string.
+func formOrQuery(c *gin.Context, key string) string {
+	if v := c.PostForm(key); v != "" {
+		return v
+	}
+	return c.Query(key)
+}
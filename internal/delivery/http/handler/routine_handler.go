@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+	"campusassistant-api/pkg/ical"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RoutineHandler serves the structured class routine. Writes go through
+// usecase.Routines, which lets only academic admins and the batch's CRs
+// change it and refuses room and teacher clashes; reads use the generic
+// handler.
+type RoutineHandler struct {
+	*GenericHandler[domain.RoutineEntry]
+	routines *usecase.Routines
+}
+
+func NewRoutineHandler(u usecase.Usecase[domain.RoutineEntry], routines *usecase.Routines) *RoutineHandler {
+	return &RoutineHandler{
+		GenericHandler: NewGenericHandler(u),
+		routines:       routines,
+	}
+}
+
+// Create adds a weekly class to the routine.
+// POST /routine-entries
+func (h *RoutineHandler) Create(c *gin.Context) {
+	var entry domain.RoutineEntry
+	if err := c.ShouldBindJSON(&entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+	entry.SetCreatedBy(uid)
+	entry.SetUpdatedBy(uid)
+
+	created, err := h.routines.Create(c.Request.Context(), &entry)
+	if err != nil {
+		respondRoutineError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// Update replaces a weekly class.
+// PUT /routine-entries/:id
+func (h *RoutineHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	uid, ok := currentUser(c)
+	if !ok {
+		return
+	}
+
+	var changes domain.RoutineEntry
+	if err := c.ShouldBindJSON(&changes); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	changes.ID = id
+	changes.SetUpdatedBy(uid)
+
+	entry, err := h.routines.Update(c.Request.Context(), &changes)
+	if err != nil {
+		respondRoutineError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, entry)
+}
+
+// Delete removes a weekly class.
+// DELETE /routine-entries/:id
+func (h *RoutineHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if err := h.routines.Delete(c.Request.Context(), id); err != nil {
+		respondRoutineError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// Week returns the batch's weekly routine in effect on ?date=YYYY-MM-DD
+// (default today).
+// GET /batches/:id/routine
+func (h *RoutineHandler) Week(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	entries, err := h.routines.Week(c.Request.Context(), id, c.Query("date"))
+	if err != nil {
+		respondRoutineError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  entries,
+		"count": len(entries),
+	})
+}
+
+// Calendar serves the batch's classes as an iCalendar feed that calendar
+// apps can subscribe to. It needs no API key.
+// GET /batches/:id/routine.ics
+func (h *RoutineHandler) Calendar(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	cal, err := h.routines.Calendar(c.Request.Context(), id)
+	if err != nil {
+		respondRoutineError(c, err)
+		return
+	}
+	c.Header("Content-Disposition", `inline; filename="routine.ics"`)
+	c.Data(http.StatusOK, ical.ContentType, cal.Bytes())
+}
+
+// Today lists the signed-in student's classes on ?date=YYYY-MM-DD
+// (default today).
+// GET /students/me/classes
+func (h *RoutineHandler) Today(c *gin.Context) {
+	classes, err := h.routines.Today(c.Request.Context(), c.Query("date"))
+	if err != nil {
+		respondRoutineError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":  classes,
+		"count": len(classes),
+	})
+}
+
+func respondRoutineError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+	case errors.Is(err, usecase.ErrNotBatchEditor), errors.Is(err, usecase.ErrNoStudentProfile):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrInvalidRoutineEntry), errors.Is(err, usecase.ErrTeacherNotAssigned), errors.Is(err, usecase.ErrInvalidDate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrRoutineClash):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// GetBySlug returns one entity by slug. Scoped slugs (departments, batches,
+// halls, sessions) can be narrowed with the scope column as a query param,
+// e.g. ?university_id=...; without it an ambiguous slug is a 409. A slug
+// retired by a rename answers 301 to the current one.
+// GET /:entity/by-slug/:slug
+func (h *GenericHandler[T]) GetBySlug(c *gin.Context) {
+	su, ok := h.Usecase.(usecase.SlugUsecase[T])
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": usecase.ErrSlugsDisabled.Error()})
+		return
+	}
+
+	scope := uuid.Nil
+	if s, ok := any(new(T)).(domain.Slugged); ok && s.SlugScope() != "" {
+		if val := c.Query(s.SlugScope()); val != "" {
+			id, err := uuid.Parse(val)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + s.SlugScope()})
+				return
+			}
+			scope = id
+		}
+	}
+
+	requested := c.Param("slug")
+	entity, err := su.GetBySlug(c.Request.Context(), requested, scope)
+	if err != nil {
+		respondSlugError(c, err)
+		return
+	}
+
+	// Old slugs from before a rename redirect to the current URL.
+	if s, ok := any(entity).(domain.Slugged); ok && s.GetSlug() != requested {
+		target := strings.TrimSuffix(c.Request.URL.Path, requested) + s.GetSlug()
+		if c.Request.URL.RawQuery != "" {
+			target += "?" + c.Request.URL.RawQuery
+		}
+		c.Redirect(http.StatusMovedPermanently, target)
+		return
+	}
+
+	c.JSON(http.StatusOK, entity)
+}
+
+// SlugResolverHandler serves the hierarchical slug resolver.
+type SlugResolverHandler struct {
+	resolver *usecase.SlugResolver
+}
+
+func NewSlugResolverHandler(resolver *usecase.SlugResolver) *SlugResolverHandler {
+	return &SlugResolverHandler{resolver: resolver}
+}
+
+// Resolve turns a university/department/batch slug path into entities.
+// GET /resolve/*path (e.g. /resolve/du/psychology/batch-10)
+func (h *SlugResolverHandler) Resolve(c *gin.Context) {
+	slugs := strings.Split(strings.Trim(c.Param("path"), "/"), "/")
+	if len(slugs) == 1 && slugs[0] == "" {
+		slugs = nil
+	}
+
+	resolved, err := h.resolver.Resolve(c.Request.Context(), slugs)
+	if err != nil {
+		respondSlugError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, resolved)
+}
+
+func respondSlugError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidSlugPath):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, usecase.ErrSlugsDisabled):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		// Resolver errors name the level, e.g. `department "cse"`.
+		msg := "Not found"
+		if err != gorm.ErrRecordNotFound {
+			msg = strings.TrimSuffix(err.Error(), ": "+gorm.ErrRecordNotFound.Error()) + " not found"
+		}
+		c.JSON(http.StatusNotFound, gin.H{"error": msg})
+	case errors.Is(err, usecase.ErrSlugAmbiguous):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,120 +1,62 @@
 package handler
 
 import (
+	"net/http"
+
 	"campusassistant-api/internal/domain"
 	"campusassistant-api/internal/usecase"
-	"crypto/rand"
-	"fmt"
-	"math/big"
-	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 	"github.com/google/uuid"
 )
 
+// StudentClaimRequest is the student claim body: the code plus profile
+// fields the student may fill in while claiming.
+type StudentClaimRequest struct {
+	ClaimRequest
+	StudentID    string     `json:"student_id"`
+	Phone        string     `json:"phone"`
+	BloodGroup   string     `json:"blood_group"`
+	HallID       *uuid.UUID `json:"hall_id"`
+	BatchID      *uuid.UUID `json:"batch_id"`
+	SessionID    *uuid.UUID `json:"session_id"`
+	DepartmentID *uuid.UUID `json:"department_id"`
+	UniversityID *uuid.UUID `json:"university_id"`
+}
+
 type StudentHandler struct {
 	*GenericHandler[domain.Student]
+	*ClaimHandler[domain.Student]
 }
 
-func NewStudentHandler(u usecase.Usecase[domain.Student]) *StudentHandler {
+func NewStudentHandler(u usecase.Usecase[domain.Student], claims domain.ClaimRepository[domain.Student], codes *usecase.ClaimCodes, users usecase.Usecase[domain.User], events EventSink) *StudentHandler {
+	claimHandler := NewClaimHandler(u, claims, codes, users, events)
+	claimHandler.Details = studentClaimDetails
 	return &StudentHandler{
 		GenericHandler: NewGenericHandler(u),
+		ClaimHandler:   claimHandler,
 	}
 }
 
-func (h *StudentHandler) Create(c *gin.Context) {
-	var student domain.Student
-	if err := c.ShouldBindJSON(&student); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+// GetAll lists students. Filtering by blood group is refused: donors who
+// agreed to be contacted are listed by GET /donors.
+// GET /students
+func (h *StudentHandler) GetAll(c *gin.Context) {
+	if c.Query("blood_group") != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "blood_group is not a student filter; use GET /donors to find donors who opted in"})
 		return
 	}
-
-	// Generate a 6-digit numeric verification code if not provided
-	if student.VerificationCode == "" {
-		code, err := generateNumericCode(6)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate code"})
-			return
-		}
-		student.VerificationCode = code
-	}
-
-	if err := h.Usecase.Create(c.Request.Context(), &student); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusCreated, student)
+	h.GenericHandler.GetAll(c)
 }
 
-func (h *StudentHandler) VerifyCode(c *gin.Context) {
-	var req struct {
-		Code string `json:"code" binding:"required"`
-	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Code is required"})
-		return
-	}
-
-	filter := map[string]interface{}{
-		"verification_code": req.Code,
-		"is_claimed":        false,
+// studentClaimDetails applies the optional StudentClaimRequest fields.
+func studentClaimDetails(c *gin.Context, student *domain.Student) error {
+	var req StudentClaimRequest
+	if err := c.ShouldBindBodyWith(&req, binding.JSON); err != nil {
+		return err
 	}
 
-	students, _, err := h.Usecase.GetAll(c.Request.Context(), filter, 1, 0)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if len(students) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid or already claimed code"})
-		return
-	}
-
-	c.JSON(http.StatusOK, students[0])
-}
-
-func (h *StudentHandler) ClaimProfile(c *gin.Context) {
-	var req struct {
-		Code         string     `json:"code" binding:"required"`
-		UserID       uuid.UUID  `json:"user_id" binding:"required"`
-		StudentID    string     `json:"student_id"`
-		Phone        string     `json:"phone"`
-		BloodGroup   string     `json:"blood_group"`
-		HallID       *uuid.UUID `json:"hall_id"`
-		BatchID      *uuid.UUID `json:"batch_id"`
-		SessionID    *uuid.UUID `json:"session_id"`
-		DepartmentID *uuid.UUID `json:"department_id"`
-		UniversityID *uuid.UUID `json:"university_id"`
-	}
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-
-	filter := map[string]interface{}{
-		"verification_code": req.Code,
-		"is_claimed":        false,
-	}
-
-	students, _, err := h.Usecase.GetAll(c.Request.Context(), filter, 1, 0)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
-	}
-
-	if len(students) == 0 {
-		c.JSON(http.StatusNotFound, gin.H{"error": "Invalid or already claimed code"})
-		return
-	}
-
-	student := students[0]
-	student.UserID = &req.UserID
-	student.IsClaimed = true
-	student.VerificationCode = "" // Clear code after claim
-
-	// Update additional fields if provided
 	if req.StudentID != "" {
 		student.StudentID = req.StudentID
 	}
@@ -139,23 +81,5 @@ func (h *StudentHandler) ClaimProfile(c *gin.Context) {
 	if req.UniversityID != nil {
 		student.UniversityID = *req.UniversityID
 	}
-
-	if err := h.Usecase.Update(c.Request.Context(), &student); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update student profile: " + err.Error()})
-		return
-	}
-
-	c.JSON(http.StatusOK, student)
-}
-
-func generateNumericCode(length int) (string, error) {
-	result := ""
-	for i := 0; i < length; i++ {
-		num, err := rand.Int(rand.Reader, big.NewInt(10))
-		if err != nil {
-			return "", err
-		}
-		result += fmt.Sprintf("%d", num)
-	}
-	return result, nil
+	return nil
 }
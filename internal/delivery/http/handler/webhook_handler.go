@@ -0,0 +1,325 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/internal/usecase"
+	"campusassistant-api/pkg/webhook"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// WebhookHandler manages webhook subscriptions and their delivery log.
+// Create and update keep the signing secret out of request and response
+// bodies. University admins only see and manage their university's
+// subscriptions; only super admins may subscribe to every university.
+type WebhookHandler struct {
+	*GenericHandler[domain.WebhookSubscription]
+	repo       domain.WebhookRepository
+	dispatcher *usecase.WebhookDispatcher
+}
+
+func NewWebhookHandler(u usecase.Usecase[domain.WebhookSubscription], repo domain.WebhookRepository, dispatcher *usecase.WebhookDispatcher) *WebhookHandler {
+	return &WebhookHandler{
+		GenericHandler: NewGenericHandler(u),
+		repo:           repo,
+		dispatcher:     dispatcher,
+	}
+}
+
+// WebhookSecretResponse returns the signing secret. It is only shown on
+// create and rotate.
+type WebhookSecretResponse struct {
+	domain.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// Create registers a subscription with a freshly generated secret.
+// POST /webhooks
+func (h *WebhookHandler) Create(c *gin.Context) {
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+	var sub domain.WebhookSubscription
+	if err := c.ShouldBindJSON(&sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	scope.pin(&sub)
+	if err := usecase.ValidateWebhook(c.Request.Context(), &sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	sub.Secret = secret
+	sub.Active = true
+
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(uuid.UUID); ok {
+			sub.SetCreatedBy(id)
+			sub.SetUpdatedBy(id)
+		}
+	}
+
+	if err := h.Usecase.Create(c.Request.Context(), &sub); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, WebhookSecretResponse{WebhookSubscription: sub, Secret: secret})
+}
+
+// Update changes URL, events, scope or active flag. Omitted fields and the
+// secret are kept.
+// PUT /webhooks/:id
+func (h *WebhookHandler) Update(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	scope, sub, ok := h.subscription(c, id)
+	if !ok {
+		return
+	}
+	if err := c.ShouldBindJSON(sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub.ID = id
+	scope.pin(sub)
+	if err := usecase.ValidateWebhook(c.Request.Context(), sub); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if userID, exists := c.Get("user_id"); exists {
+		if uid, ok := userID.(uuid.UUID); ok {
+			sub.SetUpdatedBy(uid)
+		}
+	}
+
+	if err := h.Usecase.Update(c.Request.Context(), sub); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// GetAll lists the subscriptions in the caller's scope.
+// GET /webhooks
+func (h *WebhookHandler) GetAll(c *gin.Context) {
+	scope, ok := callerScope(c)
+	if !ok {
+		return
+	}
+	limit, offset := pageParams(c)
+	filter := listFilter(c)
+	scope.restrict(filter)
+
+	subs, count, err := h.Usecase.GetAll(c.Request.Context(), filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":   subs,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetByID returns a subscription in the caller's scope.
+// GET /webhooks/:id
+func (h *WebhookHandler) GetByID(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	if _, sub, ok := h.subscription(c, id); ok {
+		c.JSON(http.StatusOK, sub)
+	}
+}
+
+// Delete removes a subscription in the caller's scope.
+// DELETE /webhooks/:id
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	if _, _, ok := h.subscription(c, id); !ok {
+		return
+	}
+	if err := h.Usecase.Delete(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted successfully"})
+}
+
+// RotateSecret replaces the signing secret and returns the new one.
+// POST /webhooks/:id/rotate-secret
+func (h *WebhookHandler) RotateSecret(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+	if _, _, ok := h.subscription(c, id); !ok {
+		return
+	}
+
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if err := h.repo.UpdateSecret(c.Request.Context(), id, secret); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+
+	sub, err := h.Usecase.GetByID(c.Request.Context(), id)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, WebhookSecretResponse{WebhookSubscription: *sub, Secret: secret})
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first.
+// GET /webhooks/:id/deliveries?status=&event_name=
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return
+	}
+
+	if _, _, ok := h.subscription(c, id); !ok {
+		return
+	}
+	limit, offset := pageParams(c)
+
+	filter := make(map[string]interface{})
+	for _, f := range []string{"status", "event_name", "event_id"} {
+		if val := c.Query(f); val != "" {
+			filter[f] = val
+		}
+	}
+
+	deliveries, count, err := h.repo.ListDeliveries(c.Request.Context(), id, filter, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":   deliveries,
+		"count":  count,
+		"limit":  limit,
+		"offset": offset,
+	})
+}
+
+// GetDelivery returns one logged delivery with its payload and last response.
+// GET /webhooks/:id/deliveries/:delivery_id
+func (h *WebhookHandler) GetDelivery(c *gin.Context) {
+	id, deliveryID, ok := deliveryParams(c)
+	if !ok {
+		return
+	}
+	if _, _, ok := h.subscription(c, id); !ok {
+		return
+	}
+
+	delivery, err := h.repo.GetDelivery(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// Redeliver sends a logged delivery again and returns the new attempt.
+// POST /webhooks/:id/deliveries/:delivery_id/redeliver
+func (h *WebhookHandler) Redeliver(c *gin.Context) {
+	id, deliveryID, ok := deliveryParams(c)
+	if !ok {
+		return
+	}
+	if _, _, ok := h.subscription(c, id); !ok {
+		return
+	}
+
+	delivery, err := h.dispatcher.Redeliver(c.Request.Context(), id, deliveryID)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, delivery)
+}
+
+// subscription loads a subscription the caller may manage. Ones outside
+// their scope are answered 404, like missing ones.
+func (h *WebhookHandler) subscription(c *gin.Context, id uuid.UUID) (adminScope, *domain.WebhookSubscription, bool) {
+	scope, ok := callerScope(c)
+	if !ok {
+		return scope, nil, false
+	}
+	sub, err := h.Usecase.GetByID(c.Request.Context(), id)
+	if err != nil || !scope.covers(sub.UniversityID, sub.DepartmentID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook not found"})
+		return scope, nil, false
+	}
+	return scope, sub, true
+}
+
+// pin keeps a subscription inside the scope: scoped admins always
+// subscribe to their own university (and department).
+func (s adminScope) pin(sub *domain.WebhookSubscription) {
+	if s.UniversityID != nil {
+		sub.UniversityID = s.UniversityID
+	}
+	if s.DepartmentID != nil {
+		sub.DepartmentID = s.DepartmentID
+	}
+}
+
+func deliveryParams(c *gin.Context) (id, deliveryID uuid.UUID, ok bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID format"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	deliveryID, err = uuid.Parse(c.Param("delivery_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID format"})
+		return uuid.Nil, uuid.Nil, false
+	}
+	return id, deliveryID, true
+}
+
+func respondWebhookError(c *gin.Context, err error) {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
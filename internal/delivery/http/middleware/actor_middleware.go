@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"campusassistant-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ActorMiddleware copies the caller's identity onto the request context so
+// usecases can attribute changes (audit logs, revisions) without depending on Gin.
+// It must run after the JWT middleware to pick up the user ID.
+func ActorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		actor := domain.Actor{
+			IPAddress: c.ClientIP(),
+			UserAgent: c.Request.UserAgent(),
+		}
+		if userID, exists := c.Get("user_id"); exists {
+			if id, ok := userID.(uuid.UUID); ok {
+				actor.UserID = id
+			}
+		}
+		if role, exists := c.Get("user_role"); exists {
+			actor.Role, _ = role.(string)
+		}
+
+		c.Request = c.Request.WithContext(domain.WithActor(c.Request.Context(), actor))
+		c.Next()
+	}
+}
@@ -0,0 +1,296 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	IdempotencyHeader       = "Idempotency-Key"
+	InstallIDHeader         = "X-Install-ID"
+	idempotencyReplayHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength = 255
+	maxInstallIDLength      = 100
+
+	// idempotencyLease is how long a request may hold its key before a retry
+	// may take it over, in case the instance running it died. Running
+	// requests renew it every idempotencyRenewal.
+	idempotencyLease   = 2 * time.Minute
+	idempotencyRenewal = idempotencyLease / 4
+
+	// maxMemoryBody is the largest body kept in memory for hashing; bigger
+	// ones (and those of unknown length) are spooled to a temporary file.
+	maxMemoryBody = 1 << 20
+)
+
+// responseRecorder tees the response body so it can be stored for replay.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// IdempotencyMiddleware makes POST handlers safe to retry.
+// The first response for an Idempotency-Key (per client) is stored for ttl and
+// replayed on retries; a retry that arrives while the first request is still
+// running gets 409. The running request keeps renewing its lease, so a retry
+// takes the key over only once the instance running it died. Requests without
+// the header are passed through unchanged. Keys of signed-out callers are
+// scoped to their X-Install-ID, as every app shares the same API key.
+func IdempotencyMiddleware(repo domain.IdempotencyRepository, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := strings.TrimSpace(c.GetHeader(IdempotencyHeader))
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key is too long"})
+			return
+		}
+
+		clientID, ok := idempotencyClientID(c)
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Idempotency-Key requires signing in or an X-Install-ID header"})
+			return
+		}
+
+		requestHash, cleanup, err := hashRequest(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Failed to read request body"})
+			return
+		}
+		defer cleanup()
+
+		rec := &domain.IdempotencyKey{
+			Key:         key,
+			ClientID:    clientID,
+			Method:      c.Request.Method,
+			Path:        c.FullPath(),
+			RequestHash: requestHash,
+			Status:      domain.IdempotencyProcessing,
+			CreatedAt:   time.Now(),
+			ExpiresAt:   time.Now().Add(idempotencyLease),
+		}
+
+		existing, reserved, err := repo.Reserve(c.Request.Context(), rec)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Failed to check idempotency key"})
+			return
+		}
+
+		if !reserved {
+			if existing.Method != rec.Method || existing.Path != rec.Path || existing.RequestHash != rec.RequestHash {
+				c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "Idempotency-Key was already used for a different request"})
+				return
+			}
+			if existing.Status != domain.IdempotencyCompleted {
+				c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "A request with this Idempotency-Key is already in progress"})
+				return
+			}
+
+			c.Header(idempotencyReplayHeader, "true")
+			c.Data(existing.ResponseCode, existing.ContentType, existing.ResponseBody)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		completed := false
+		defer func() {
+			// Use a fresh context: the request context may already be cancelled.
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			// Server errors and panics free the key so the client can retry for real.
+			if !completed {
+				if err := repo.Release(ctx, rec.ID); err != nil {
+					logger.Errorf("failed to release idempotency key %s: %v", key, err)
+				}
+			}
+		}()
+		defer keepReserved(repo, rec.ID, idempotencyRenewal)()
+
+		c.Next()
+
+		status := recorder.Status()
+		if status >= http.StatusInternalServerError {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := repo.Complete(ctx, rec.ID, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes(), time.Now().Add(ttl)); err != nil {
+			logger.Errorf("failed to store idempotent response for key %s: %v", key, err)
+			return
+		}
+		completed = true
+	}
+}
+
+// StartIdempotencyCleanup periodically purges expired idempotency records.
+func StartIdempotencyCleanup(repo domain.IdempotencyRepository, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if _, err := repo.DeleteExpired(context.Background()); err != nil {
+				logger.Errorf("failed to purge idempotency keys: %v", err)
+			}
+		}
+	}()
+}
+
+// idempotencyClientID scopes keys to the authenticated user or, for signed-out
+// app traffic, to a fingerprint of the API key and the app install. It
+// reports false when neither identifies the caller.
+func idempotencyClientID(c *gin.Context) (string, bool) {
+	if userID, exists := c.Get("user_id"); exists {
+		if id, ok := userID.(uuid.UUID); ok && id != uuid.Nil {
+			return "user:" + id.String(), true
+		}
+	}
+	install := strings.TrimSpace(c.GetHeader(InstallIDHeader))
+	if install == "" || len(install) > maxInstallIDLength {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(c.GetHeader("X-API-Key") + "\x00" + install))
+	return "install:" + hex.EncodeToString(sum[:16]), true
+}
+
+// keepReserved renews the lease of a reserved key every interval until the
+// returned stop function is called.
+func keepReserved(repo domain.IdempotencyRepository, id uuid.UUID, interval time.Duration) (stop func()) {
+	done, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				if err := repo.Renew(ctx, id, time.Now().Add(idempotencyLease)); err != nil {
+					logger.Errorf("failed to renew idempotency key %s: %v", id, err)
+				}
+				cancel()
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// hashRequest fingerprints the body so a key reused for a different payload
+// is rejected, and puts the body back for the handler. Multipart forms are
+// hashed part by part, as clients pick a new boundary on every retry. The
+// returned cleanup removes the body's temporary file, if any.
+func hashRequest(c *gin.Context) (string, func(), error) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return "", func() {}, nil
+	}
+
+	body, cleanup, err := spoolBody(c.Request.Body, c.Request.ContentLength)
+	if err != nil {
+		return "", nil, err
+	}
+
+	h := sha256.New()
+	if mediaType, params, _ := mime.ParseMediaType(c.GetHeader("Content-Type")); mediaType == "multipart/form-data" {
+		err = hashMultipart(h, multipart.NewReader(body, params["boundary"]))
+	} else {
+		_, err = io.Copy(h, body)
+	}
+	if err == nil {
+		_, err = body.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	c.Request.Body = io.NopCloser(body)
+	return hex.EncodeToString(h.Sum(nil)), cleanup, nil
+}
+
+// hashMultipart writes each part's field name, file name, content type and
+// content to h.
+func hashMultipart(h hash.Hash, r *multipart.Reader) error {
+	for {
+		part, err := r.NextPart()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "%q %q %q\n", part.FormName(), part.FileName(), part.Header.Get("Content-Type"))
+		n, err := io.Copy(h, part)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(h, "\n%d\n", n)
+	}
+}
+
+// spoolBody reads body into memory, or into a temporary file when it is
+// larger than maxMemoryBody or of unknown length.
+func spoolBody(body io.Reader, length int64) (io.ReadSeeker, func(), error) {
+	if length >= 0 && length <= maxMemoryBody {
+		b, err := io.ReadAll(body)
+		if err != nil {
+			return nil, nil, err
+		}
+		return bytes.NewReader(b), func() {}, nil
+	}
+
+	f, err := os.CreateTemp("", "idempotent-body-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	if _, err := io.Copy(f, body); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return f, cleanup, nil
+}
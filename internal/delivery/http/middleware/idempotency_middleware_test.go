@@ -0,0 +1,187 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// memIdempotencyRepo is an in-memory domain.IdempotencyRepository.
+type memIdempotencyRepo struct {
+	mu       sync.Mutex
+	records  map[string]*domain.IdempotencyKey
+	renewals int
+}
+
+func newMemIdempotencyRepo() *memIdempotencyRepo {
+	return &memIdempotencyRepo{records: map[string]*domain.IdempotencyKey{}}
+}
+
+func (r *memIdempotencyRepo) Reserve(_ context.Context, rec *domain.IdempotencyKey) (*domain.IdempotencyKey, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.records[rec.ClientID+rec.Key]; ok && existing.ExpiresAt.After(time.Now()) {
+		copied := *existing
+		return &copied, false, nil
+	}
+	rec.ID = uuid.New()
+	copied := *rec
+	r.records[rec.ClientID+rec.Key] = &copied
+	return nil, true, nil
+}
+
+func (r *memIdempotencyRepo) find(id uuid.UUID) *domain.IdempotencyKey {
+	for _, rec := range r.records {
+		if rec.ID == id {
+			return rec
+		}
+	}
+	return nil
+}
+
+func (r *memIdempotencyRepo) Complete(_ context.Context, id uuid.UUID, code int, contentType string, body []byte, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rec := r.find(id); rec != nil {
+		rec.Status, rec.ResponseCode, rec.ContentType, rec.ResponseBody, rec.ExpiresAt = domain.IdempotencyCompleted, code, contentType, body, expiresAt
+	}
+	return nil
+}
+
+func (r *memIdempotencyRepo) Renew(_ context.Context, id uuid.UUID, expiresAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if rec := r.find(id); rec != nil && rec.Status == domain.IdempotencyProcessing {
+		rec.ExpiresAt = expiresAt
+		r.renewals++
+	}
+	return nil
+}
+
+func (r *memIdempotencyRepo) Release(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for k, rec := range r.records {
+		if rec.ID == id {
+			delete(r.records, k)
+		}
+	}
+	return nil
+}
+
+func (r *memIdempotencyRepo) DeleteExpired(context.Context) (int64, error) { return 0, nil }
+
+// upload builds a multipart form with a fresh random boundary.
+func upload(folder string, file []byte) ([]byte, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	_ = w.WriteField("folder", folder)
+	part, _ := w.CreateFormFile("image", "banner.png")
+	_, _ = part.Write(file)
+	_ = w.Close()
+	return buf.Bytes(), w.FormDataContentType()
+}
+
+func TestIdempotencyHashesRequestBodies(t *testing.T) {
+	banner := []byte("PNG banner")
+	large := bytes.Repeat([]byte("x"), maxMemoryBody+1)
+	jsonBody := func(s string) func() ([]byte, string) {
+		return func() ([]byte, string) { return []byte(s), "application/json" }
+	}
+	form := func(folder string, file []byte) func() ([]byte, string) {
+		return func() ([]byte, string) { return upload(folder, file) }
+	}
+
+	tests := []struct {
+		name       string
+		first      func() ([]byte, string)
+		retry      func() ([]byte, string)
+		wantStatus int
+	}{
+		{"same upload, new boundary", form("banners", banner), form("banners", banner), http.StatusCreated},
+		{"same large upload", form("banners", large), form("banners", large), http.StatusCreated},
+		{"other file", form("banners", banner), form("banners", []byte("PNG logo")), http.StatusUnprocessableEntity},
+		{"other field", form("banners", banner), form("logos", banner), http.StatusUnprocessableEntity},
+		{"same JSON", jsonBody(`{"title":"Exam"}`), jsonBody(`{"title":"Exam"}`), http.StatusCreated},
+		{"other JSON", jsonBody(`{"title":"Exam"}`), jsonBody(`{"title":"Quiz"}`), http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			r := gin.New()
+			r.POST("/upload", IdempotencyMiddleware(newMemIdempotencyRepo(), time.Hour), func(c *gin.Context) {
+				calls++
+				// The handler still gets the whole body
+				if file, err := c.FormFile("image"); err == nil {
+					c.JSON(http.StatusCreated, gin.H{"size": file.Size, "folder": c.PostForm("folder")})
+					return
+				}
+				body, _ := io.ReadAll(c.Request.Body)
+				c.Data(http.StatusCreated, "application/json", body)
+			})
+			send := func(build func() ([]byte, string)) *httptest.ResponseRecorder {
+				body, contentType := build()
+				req := httptest.NewRequest(http.MethodPost, "/upload", bytes.NewReader(body))
+				req.Header.Set("Content-Type", contentType)
+				req.Header.Set(IdempotencyHeader, "key-1")
+				req.Header.Set(InstallIDHeader, "install-1")
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				return w
+			}
+
+			first := send(tt.first)
+			if first.Code != http.StatusCreated || first.Body.Len() == 0 || strings.Contains(first.Body.String(), `"size":0`) {
+				t.Fatalf("first request = %d %s, want the handler to read the body", first.Code, first.Body.String())
+			}
+			retry := send(tt.retry)
+			if retry.Code != tt.wantStatus {
+				t.Fatalf("retry status = %d, want %d (body %s)", retry.Code, tt.wantStatus, retry.Body.String())
+			}
+			if tt.wantStatus == http.StatusCreated && (retry.Header().Get(idempotencyReplayHeader) != "true" || retry.Body.String() != first.Body.String()) {
+				t.Errorf("retry = %s, want the first response replayed", retry.Body.String())
+			}
+			if calls != 1 {
+				t.Errorf("handler ran %d times, want once", calls)
+			}
+		})
+	}
+}
+
+func TestKeepReservedRenewsTheLease(t *testing.T) {
+	repo := newMemIdempotencyRepo()
+	rec := &domain.IdempotencyKey{Key: "key-1", ClientID: "user:1", Status: domain.IdempotencyProcessing, ExpiresAt: time.Now().Add(time.Second)}
+	if _, _, err := repo.Reserve(context.Background(), rec); err != nil {
+		t.Fatal(err)
+	}
+
+	stop := keepReserved(repo, rec.ID, 5*time.Millisecond)
+	time.Sleep(30 * time.Millisecond)
+	stop()
+
+	repo.mu.Lock()
+	renewals, expiresAt := repo.renewals, repo.find(rec.ID).ExpiresAt
+	repo.mu.Unlock()
+	if renewals == 0 || expiresAt.Before(time.Now().Add(idempotencyLease-time.Second)) {
+		t.Fatalf("%d renewals, lease until %s; want it pushed a full lease ahead", renewals, expiresAt)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.renewals != renewals {
+		t.Errorf("lease renewed %d more times after stop", repo.renewals-renewals)
+	}
+}
@@ -40,16 +40,35 @@ func JWTMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
 		}
 
 		// Set user information in context
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
-		c.Set("user_role", claims.Role)
-		c.Set("university_id", claims.UniversityID)
-		c.Set("department_id", claims.DepartmentID)
+		setClaims(c, claims)
 
 		c.Next()
 	}
 }
 
+// OptionalJWTMiddleware sets user context when a valid Bearer token is sent,
+// but lets anonymous (API-key only) requests through untouched.
+func OptionalJWTMiddleware(jwtManager *auth.JWTManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			if claims, err := jwtManager.ValidateToken(parts[1]); err == nil {
+				setClaims(c, claims)
+			}
+		}
+
+		c.Next()
+	}
+}
+
+func setClaims(c *gin.Context, claims *auth.Claims) {
+	c.Set("user_id", claims.UserID)
+	c.Set("user_email", claims.Email)
+	c.Set("user_role", claims.Role)
+	c.Set("university_id", claims.UniversityID)
+	c.Set("department_id", claims.DepartmentID)
+}
+
 // RoleMiddleware checks if the user has one of the required roles
 func RoleMiddleware(allowedRoles ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"campusassistant-api/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RateLimitMiddleware rejects requests over limiter's budget with 429 and a
+// Retry-After header. key picks the bucket; requests it returns "" for are
+// not limited.
+func RateLimitMiddleware(limiter *ratelimit.Limiter, key func(c *gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		if ok, retryAfter := limiter.Allow(k); !ok {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many attempts, try again later"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// FailureLimitMiddleware rejects requests with 429 once key has used up
+// limiter's budget of failed requests, i.e. ones answered with one of the
+// failed statuses. Successful requests do not count.
+func FailureLimitMiddleware(limiter *ratelimit.Limiter, key func(c *gin.Context) string, failed ...int) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		k := key(c)
+		if k == "" {
+			c.Next()
+			return
+		}
+
+		if spent, retryAfter := limiter.Spent(k); spent {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, gin.H{"error": "Too many attempts, try again later"})
+			return
+		}
+
+		c.Next()
+
+		if slices.Contains(failed, c.Writer.Status()) {
+			limiter.Allow(k)
+		}
+	}
+}
+
+// GlobalKey puts every request in one bucket.
+func GlobalKey(name string) func(c *gin.Context) string {
+	return func(*gin.Context) string {
+		return "global:" + name
+	}
+}
+
+// ClientIPKey buckets requests by client IP. Only X-Forwarded-For set by a
+// trusted proxy is believed, so clients cannot pick their own bucket.
+func ClientIPKey(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// UserKey buckets requests by the authenticated user; anonymous requests
+// are not limited by it.
+func UserKey(c *gin.Context) string {
+	if id, ok := c.Get("user_id"); ok {
+		if uid, ok := id.(uuid.UUID); ok {
+			return "user:" + uid.String()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"campusassistant-api/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+func TestClientIPKeyIgnoresUntrustedForwardedFor(t *testing.T) {
+	r := gin.New()
+	if err := r.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	r.POST("/verify", RateLimitMiddleware(ratelimit.New(2, time.Minute), ClientIPKey), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	// Rotating X-Forwarded-For must not buy the client a fresh bucket
+	var codes []int
+	for _, forwarded := range []string{"1.1.1.1", "2.2.2.2", "3.3.3.3"} {
+		req := httptest.NewRequest(http.MethodPost, "/verify", nil)
+		req.RemoteAddr = "203.0.113.7:5000"
+		req.Header.Set("X-Forwarded-For", forwarded)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		codes = append(codes, w.Code)
+	}
+	if codes[2] != http.StatusTooManyRequests {
+		t.Fatalf("statuses = %v, want the third request limited", codes)
+	}
+}
+
+func TestFailureLimitMiddleware(t *testing.T) {
+	r := gin.New()
+	r.POST("/verify", FailureLimitMiddleware(ratelimit.New(2, time.Minute), GlobalKey("claim-code"), http.StatusNotFound), func(c *gin.Context) {
+		if c.Query("code") == "good" {
+			c.Status(http.StatusOK)
+			return
+		}
+		c.Status(http.StatusNotFound)
+	})
+
+	tests := []struct {
+		code string
+		want int
+	}{
+		{"good", http.StatusOK}, // successes are not counted
+		{"bad1", http.StatusNotFound},
+		{"good", http.StatusOK},
+		{"bad2", http.StatusNotFound},
+		{"bad3", http.StatusTooManyRequests},
+		{"good", http.StatusTooManyRequests}, // the cap holds for everyone
+	}
+	for i, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/verify?code="+tt.code, nil))
+		if w.Code != tt.want {
+			t.Fatalf("request %d (%s): status %d, want %d", i, tt.code, w.Code, tt.want)
+		}
+	}
+}
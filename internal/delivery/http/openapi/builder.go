@@ -0,0 +1,371 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	contentJSON = "application/json"
+	contentCSV  = "text/csv"
+	contentXLSX = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+)
+
+// exporter matches models whose lists admins can export as CSV/XLSX
+// (domain.Exportable).
+var exporter = reflect.TypeOf((*interface{ ExportColumns() []string })(nil)).Elem()
+
+// Doc describes what the builder cannot infer from a route on its own.
+// Zero fields keep the inferred value.
+type Doc struct {
+	Summary  string
+	Request  interface{} // JSON request body model
+	Response interface{} // JSON success body model
+	Status   int         // success status; 200 when zero
+	Security []string    // schemes required together; nil keeps the default
+	Public   bool        // no authentication at all
+	Query    []string    // extra string query parameters
+}
+
+// Builder collects route metadata while the router is wired and turns the
+// registered Gin routes into a Document.
+type Builder struct {
+	info        Info
+	basePath    string
+	collections map[string]reflect.Type
+	docs        map[string]Doc
+	filters     map[string]*Schema
+	schemas     map[string]*Schema
+}
+
+// NewBuilder starts a spec for the API mounted under basePath ("/api/v1").
+// Routes under basePath require the API key unless described as Public.
+func NewBuilder(info Info, basePath string) *Builder {
+	return &Builder{
+		info:        info,
+		basePath:    basePath,
+		collections: map[string]reflect.Type{},
+		docs:        map[string]Doc{},
+		filters:     map[string]*Schema{},
+		schemas:     map[string]*Schema{},
+	}
+}
+
+// ListFilters declares query parameters accepted by generic list endpoints.
+// They are documented on a collection only when its model has that field.
+func (b *Builder) ListFilters(typ, format string, names ...string) {
+	for _, name := range names {
+		b.filters[name] = &Schema{Type: typ, Format: format}
+	}
+}
+
+// Collection binds a CRUD group (its full Gin path) to the model it serves.
+func (b *Builder) Collection(path string, model interface{}) {
+	b.collections[path] = reflect.TypeOf(model)
+}
+
+// Describe attaches documentation to one route by method and full Gin path.
+func (b *Builder) Describe(method, path string, d Doc) {
+	b.docs[method+" "+path] = d
+}
+
+// Build produces the document for routes (usually engine.Routes()).
+func (b *Builder) Build(routes gin.RoutesInfo) *Document {
+	doc := &Document{
+		OpenAPI: "3.0.3",
+		Info:    b.info,
+		Servers: []Server{{URL: "/"}},
+		Paths:   map[string]PathItem{},
+		Components: Components{
+			Schemas: b.schemas,
+			SecuritySchemes: map[string]SecurityScheme{
+				SecurityAPIKey: {Type: "apiKey", Name: "X-API-Key", In: "header"},
+				SecurityBearer: {Type: "http", Scheme: "bearer", BearerFormat: "JWT"},
+			},
+		},
+	}
+	b.schemas["Error"] = &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"error": {Type: "string"}},
+	}
+
+	sorted := append(gin.RoutesInfo(nil), routes...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return sorted[i].Method < sorted[j].Method
+	})
+
+	tags := map[string]bool{}
+	for _, route := range sorted {
+		op := b.operation(route)
+		tags[op.Tags[0]] = true
+
+		path := openAPIPath(route.Path)
+		if doc.Paths[path] == nil {
+			doc.Paths[path] = PathItem{}
+		}
+		doc.Paths[path][strings.ToLower(route.Method)] = op
+	}
+
+	for name := range tags {
+		doc.Tags = append(doc.Tags, Tag{Name: name})
+	}
+	sort.Slice(doc.Tags, func(i, j int) bool { return doc.Tags[i].Name < doc.Tags[j].Name })
+
+	return doc
+}
+
+// operation infers one operation from the route, its collection model and
+// any Describe override.
+func (b *Builder) operation(route gin.RouteInfo) *Operation {
+	op := &Operation{
+		Tags:        []string{b.tag(route.Path)},
+		Summary:     handlerSummary(route.Handler),
+		OperationID: operationID(route.Method, strings.TrimPrefix(route.Path, b.basePath)),
+		Parameters:  pathParameters(route.Path),
+		Responses: map[string]Response{
+			"200":     {Description: "OK", Content: jsonContent(&Schema{Type: "object"})},
+			"default": b.errorResponse("Error"),
+		},
+	}
+
+	if strings.HasPrefix(route.Path, b.basePath+"/") {
+		op.Security = security(SecurityAPIKey)
+	}
+
+	b.inferCollection(route, op)
+
+	d, ok := b.docs[route.Method+" "+route.Path]
+	if !ok {
+		return op
+	}
+	if d.Summary != "" {
+		op.Summary = d.Summary
+	}
+	if d.Request != nil {
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(b.schemaOf(reflect.TypeOf(d.Request)))}
+	}
+	if d.Response != nil || d.Status != 0 {
+		status := d.Status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		resp := op.Responses["200"]
+		delete(op.Responses, "200")
+		if d.Response != nil {
+			resp = Response{Description: http.StatusText(status), Content: jsonContent(b.schemaOf(reflect.TypeOf(d.Response)))}
+		}
+		op.Responses[strconv.Itoa(status)] = resp
+	}
+	for _, q := range d.Query {
+		op.Parameters = append(op.Parameters, Parameter{Name: q, In: "query", Schema: &Schema{Type: "string"}})
+	}
+	switch {
+	case d.Public:
+		op.Security = nil
+	case d.Security != nil:
+		op.Security = security(d.Security...)
+	}
+	return op
+}
+
+// inferCollection fills in the standard CRUD shapes for routes directly on
+// a registered collection: POST/GET on the base and GET/PUT/DELETE on /:id.
+func (b *Builder) inferCollection(route gin.RouteInfo, op *Operation) {
+	var (
+		base  string
+		model reflect.Type
+	)
+	for path, t := range b.collections {
+		within := route.Path == path || strings.HasPrefix(route.Path, path+"/")
+		if within && len(path) > len(base) {
+			base, model = path, t
+		}
+	}
+	if model == nil {
+		return
+	}
+
+	name := model.Name()
+	ref := b.schemaOf(model)
+	resource := base[strings.LastIndex(base, "/")+1:]
+
+	switch rel := strings.TrimPrefix(route.Path, base); {
+	case rel == "" && route.Method == http.MethodPost:
+		op.Summary = "Create " + name
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(ref)}
+		delete(op.Responses, "200")
+		op.Responses["201"] = Response{Description: "Created", Content: jsonContent(ref)}
+	case rel == "" && route.Method == http.MethodGet:
+		op.Summary = "List " + resource
+		op.Parameters = append(op.Parameters, b.listParameters(model)...)
+		content := jsonContent(&Schema{
+			Type: "object",
+			Properties: map[string]*Schema{
+				"data":   {Type: "array", Items: ref},
+				"count":  {Type: "integer", Format: "int64"},
+				"limit":  {Type: "integer", Format: "int32"},
+				"offset": {Type: "integer", Format: "int32"},
+			},
+		})
+		if reflect.PointerTo(model).Implements(exporter) {
+			content[contentCSV] = MediaType{Schema: &Schema{Type: "string"}}
+			content[contentXLSX] = MediaType{Schema: &Schema{Type: "string", Format: "binary"}}
+		}
+		op.Responses["200"] = Response{Description: "OK", Content: content}
+	case rel == "/:id" && route.Method == http.MethodGet:
+		op.Summary = "Get " + name
+		op.Responses["200"] = Response{Description: "OK", Content: jsonContent(ref)}
+		op.Responses["404"] = b.errorResponse("Not found")
+	case rel == "/:id" && route.Method == http.MethodPut:
+		op.Summary = "Update " + name
+		op.RequestBody = &RequestBody{Required: true, Content: jsonContent(ref)}
+		op.Responses["200"] = Response{Description: "OK", Content: jsonContent(ref)}
+	case rel == "/:id" && route.Method == http.MethodDelete:
+		op.Summary = "Delete " + name
+		op.Responses["200"] = Response{Description: "Deleted", Content: jsonContent(&Schema{
+			Type:       "object",
+			Properties: map[string]*Schema{"message": {Type: "string"}},
+		})}
+	}
+}
+
+// listParameters documents paging, search, export and the filters that
+// apply to model.
+func (b *Builder) listParameters(model reflect.Type) []Parameter {
+	params := []Parameter{
+		{Name: "limit", In: "query", Description: "Page size (max 100)", Schema: &Schema{Type: "integer", Format: "int32"}},
+		{Name: "offset", In: "query", Schema: &Schema{Type: "integer", Format: "int32"}},
+		{Name: "search", In: "query", Description: "Case-insensitive match on name/title fields", Schema: &Schema{Type: "string"}},
+		{Name: "include_details", In: "query", Description: "Preload associations", Schema: &Schema{Type: "boolean"}},
+	}
+	if reflect.PointerTo(model).Implements(exporter) {
+		params = append(params, Parameter{Name: "format", In: "query", Description: "Admins only: stream every matching row in their scope as a file instead of a page", Schema: &Schema{Type: "string", Enum: []string{"csv", "xlsx"}}})
+	}
+
+	props := b.properties(model)
+	var names []string
+	for name := range b.filters {
+		if _, ok := props[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		schema := b.filters[name]
+		if len(props[name].Enum) > 0 {
+			schema = props[name]
+		}
+		params = append(params, Parameter{Name: name, In: "query", Schema: schema})
+	}
+	return params
+}
+
+func (b *Builder) errorResponse(description string) Response {
+	return Response{Description: description, Content: jsonContent(&Schema{Ref: "#/components/schemas/Error"})}
+}
+
+// tag groups operations by the first path segment after the API prefix.
+func (b *Builder) tag(path string) string {
+	rest := strings.TrimPrefix(path, b.basePath)
+	rest = strings.TrimPrefix(rest, "/")
+	if i := strings.Index(rest, "/"); i >= 0 {
+		rest = rest[:i]
+	}
+	if rest == "" {
+		return "default"
+	}
+	return rest
+}
+
+func jsonContent(s *Schema) map[string]MediaType {
+	return map[string]MediaType{contentJSON: {Schema: s}}
+}
+
+func security(schemes ...string) []map[string][]string {
+	req := map[string][]string{}
+	for _, s := range schemes {
+		req[s] = []string{}
+	}
+	return []map[string][]string{req}
+}
+
+// openAPIPath rewrites Gin's :param and *param segments as {param}.
+func openAPIPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		if strings.HasPrefix(p, ":") || strings.HasPrefix(p, "*") {
+			parts[i] = "{" + p[1:] + "}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+func pathParameters(path string) []Parameter {
+	var params []Parameter
+	for _, p := range strings.Split(path, "/") {
+		if !strings.HasPrefix(p, ":") && !strings.HasPrefix(p, "*") {
+			continue
+		}
+		name := p[1:]
+		schema := &Schema{Type: "string"}
+		switch {
+		case name == "id" || strings.HasSuffix(name, "_id"):
+			schema.Format = "uuid"
+		case name == "rev":
+			schema = &Schema{Type: "integer", Format: "int32"}
+		}
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: schema})
+	}
+	return params
+}
+
+// operationID turns "GET /resources/:id/approve" into "getResourcesByIdApprove".
+func operationID(method, path string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for _, seg := range strings.Split(path, "/") {
+		if seg == "" {
+			continue
+		}
+		if seg[0] == ':' || seg[0] == '*' {
+			sb.WriteString("By")
+			seg = seg[1:]
+		}
+		for _, word := range strings.FieldsFunc(seg, func(r rune) bool { return r == '-' || r == '_' || r == '.' }) {
+			sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return sb.String()
+}
+
+// handlerSummary derives "Approve resource" from
+// "...handler.(*ResourceHandler).ApproveResource-fm".
+func handlerSummary(handler string) string {
+	name := handler[strings.LastIndex(handler, ".")+1:]
+	name = strings.TrimSuffix(name, "-fm")
+	if name == "" || strings.HasPrefix(name, "func") {
+		return ""
+	}
+
+	var words []string
+	start := 0
+	for i, r := range name {
+		if i > 0 && unicode.IsUpper(r) {
+			words = append(words, strings.ToLower(name[start:i]))
+			start = i
+		}
+	}
+	words = append(words, strings.ToLower(name[start:]))
+
+	summary := strings.Join(words, " ")
+	return strings.ToUpper(summary[:1]) + summary[1:]
+}
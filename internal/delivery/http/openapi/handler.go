@@ -0,0 +1,58 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Marshal renders doc as indented JSON with a trailing newline, the form
+// committed to docs/openapi.json.
+func Marshal(doc *Document) ([]byte, error) {
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(out, '\n'), nil
+}
+
+// SpecHandler serves doc as JSON. It is rendered once at startup.
+func SpecHandler(doc *Document) gin.HandlerFunc {
+	body, err := Marshal(doc)
+	return func(c *gin.Context) {
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", body)
+	}
+}
+
+// DocsHandler serves a Swagger UI page that loads the spec from specURL.
+func DocsHandler(specURL string) gin.HandlerFunc {
+	html := fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head>
+    <title>Campus Assistant API</title>
+    <meta charset="utf-8">
+    <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+    <script>
+        window.ui = SwaggerUIBundle({
+            url: %q,
+            dom_id: '#swagger-ui',
+            persistAuthorization: true
+        });
+    </script>
+</body>
+</html>`, specURL)
+
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+	}
+}
@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Enum is implemented by string types with a fixed set of values
+// (domain.ResourceStatus, domain.Role, ...).
+type Enum interface {
+	EnumValues() []string
+}
+
+var (
+	enumType      = reflect.TypeOf((*Enum)(nil)).Elem()
+	timeType      = reflect.TypeOf(time.Time{})
+	uuidType      = reflect.TypeOf(uuid.UUID{})
+	jsonType      = reflect.TypeOf(datatypes.JSON{})
+	deletedAtType = reflect.TypeOf(gorm.DeletedAt{})
+)
+
+// schemaOf returns the schema for t, registering named structs as components
+// and returning a $ref to them.
+func (b *Builder) schemaOf(t reflect.Type) *Schema {
+	nullable := false
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		nullable = true
+	}
+
+	s := b.inlineSchema(t)
+	if nullable && s.Ref == "" {
+		s.Nullable = true
+	}
+	return s
+}
+
+func (b *Builder) inlineSchema(t reflect.Type) *Schema {
+	if t.Implements(enumType) {
+		return &Schema{Type: "string", Enum: reflect.Zero(t).Interface().(Enum).EnumValues()}
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case uuidType:
+		return &Schema{Type: "string", Format: "uuid"}
+	case jsonType:
+		return &Schema{Description: "Arbitrary JSON"}
+	case deletedAtType:
+		return &Schema{Type: "string", Format: "date-time", Nullable: true}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: b.schemaOf(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: b.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return b.structSchema(t)
+		}
+		return b.ref(t)
+	}
+	return &Schema{}
+}
+
+// ref registers t under components/schemas (once) and points at it.
+func (b *Builder) ref(t reflect.Type) *Schema {
+	name := componentName(t)
+	if _, ok := b.schemas[name]; !ok {
+		// Reserve the name first so self-referencing structs terminate.
+		b.schemas[name] = &Schema{}
+		*b.schemas[name] = *b.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// structSchema describes t's JSON form, flattening embedded structs the way
+// encoding/json does. Fields with binding:"required" are marked required.
+func (b *Builder) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := strings.Split(f.Tag.Get("json"), ",")
+			name := tag[0]
+
+			if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+				walk(f.Type)
+				continue
+			}
+			if !f.IsExported() || name == "-" {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+
+			s.Properties[name] = b.schemaOf(f.Type)
+			if strings.Contains(f.Tag.Get("binding"), "required") {
+				s.Required = append(s.Required, name)
+			}
+		}
+	}
+	walk(t)
+
+	sort.Strings(s.Required)
+	return s
+}
+
+// properties returns the JSON property names of a component, for
+// documenting which list filters apply.
+func (b *Builder) properties(t reflect.Type) map[string]*Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	b.ref(t)
+	return b.schemas[componentName(t)].Properties
+}
+
+// componentName turns generic instantiations such as
+// "listOf[campusassistant-api/internal/domain.AuditLog]" into "ListOfAuditLog".
+func componentName(t reflect.Type) string {
+	name := t.Name()
+	i := strings.Index(name, "[")
+	if i < 0 {
+		return name
+	}
+	arg := strings.TrimSuffix(name[i+1:], "]")
+	arg = arg[strings.LastIndex(arg, ".")+1:]
+	return strings.ToUpper(name[:1]) + name[1:i] + arg
+}
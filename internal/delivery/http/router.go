@@ -4,19 +4,32 @@ import (
 	"campusassistant-api/internal/config"
 	"campusassistant-api/internal/delivery/http/handler"
 	"campusassistant-api/internal/delivery/http/middleware"
+	"campusassistant-api/internal/delivery/http/openapi"
 	"campusassistant-api/internal/domain"
 	"campusassistant-api/internal/repository/postgres"
 	"campusassistant-api/internal/usecase"
 	"campusassistant-api/pkg/auth"
+	"campusassistant-api/pkg/eventbus"
+	"campusassistant-api/pkg/logger"
+	"campusassistant-api/pkg/ratelimit"
 	"campusassistant-api/pkg/storage"
+	"reflect"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
-func NewRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
+func NewRouter(cfg *config.Config, db *gorm.DB) (*gin.Engine, *Workers) {
 	r := gin.Default()
+	workers := &Workers{}
+
+	// Client IPs (used by the rate limits) only come from X-Forwarded-For
+	// when the request passed through one of our proxies
+	if err := r.SetTrustedProxies(cfg.TrustedProxyList()); err != nil {
+		logger.Errorf("Invalid TRUSTED_PROXIES, trusting no proxy: %v", err)
+		_ = r.SetTrustedProxies(nil)
+	}
 
 	// Middlewares
 	r.Use(gin.Logger())
@@ -48,8 +61,25 @@ func NewRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 	// API V1 Group
 	v1 := r.Group("/api/v1")
 
+	// OpenAPI spec, built from the routes registered below
+	docs := openapi.NewBuilder(openapi.Info{
+		Title:       "Campus Assistant API",
+		Description: "Academic data, resources and campus services for universities.",
+		Version:     "1.0",
+	}, v1.BasePath())
+	docs.ListFilters("string", "uuid", handler.ListUUIDFilters...)
+	docs.ListFilters("string", "", handler.ListStringFilters...)
+	docs.ListFilters("integer", "int32", handler.ListIntFilters...)
+	docs.Describe("GET", "/health", openapi.Doc{Summary: "Health check"})
+
+	// Domain events: published on the in-process bus and, for reliable side
+	// effects, stored in the outbox with the write that raised them
+	events := eventbus.New(4, 1024)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	eventSink := handler.EventSink{Outbox: outboxRepo, Bus: events}
+
 	// Public Auth Routes (No API Key or JWT required)
-	authHandler := handler.NewAuthHandler(db, jwtManager)
+	authHandler := handler.NewAuthHandler(db, jwtManager, events)
 	authGroup := v1.Group("/auth")
 	{
 		authGroup.POST("/register", authHandler.Register)
@@ -58,53 +88,297 @@ func NewRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		// Protected route - requires JWT
 		authGroup.GET("/me", middleware.JWTMiddleware(jwtManager), authHandler.GetMe)
 	}
+	docs.Describe("POST", "/api/v1/auth/register", openapi.Doc{Request: handler.RegisterRequest{}, Response: handler.AuthResponse{}, Status: 201, Public: true})
+	docs.Describe("POST", "/api/v1/auth/login", openapi.Doc{Request: handler.LoginRequest{}, Response: handler.AuthResponse{}, Public: true})
+	docs.Describe("POST", "/api/v1/auth/refresh", openapi.Doc{Request: handler.RefreshTokenRequest{}, Public: true})
+	docs.Describe("GET", "/api/v1/auth/me", openapi.Doc{Response: domain.User{}, Security: []string{openapi.SecurityBearer}})
 
 	// Protected Routes (Require API Key for now, can add JWT later)
 	v1.Use(middleware.APIKeyMiddleware(cfg.APIKey))
+	v1.Use(middleware.OptionalJWTMiddleware(jwtManager))
+	v1.Use(middleware.ActorMiddleware())
+
+	// Audit trail: every generic Create/Update/Delete is recorded asynchronously
+	auditRepo := postgres.NewAuditLogRepository(db)
+	auditor := usecase.NewAuditRecorder(auditRepo)
+	workers.add(auditor.Start)
+	workers.onStop(auditor.Close)
+	// The bus's async handlers write audited records, so it stops first
+	workers.add(events.Start)
+	workers.onStop(events.Close)
+	revisionRepo := postgres.NewRevisionRepository(db)
+	slugAliasRepo := postgres.NewSlugAliasRepository(db)
+	claimCodes := usecase.NewClaimCodes(cfg.JWTSecret, time.Duration(cfg.ClaimCodeTTL)*time.Hour)
+	ucOpts := []usecase.Option{
+		usecase.WithAuditor(auditor),
+		usecase.WithRevisions(revisionRepo),
+		usecase.WithSlugAliases(slugAliasRepo),
+		usecase.WithEvents(events),
+		usecase.WithOutbox(outboxRepo),
+		usecase.WithClaimCodes(claimCodes),
+	}
+
+	// Idempotency-Key support for POST endpoints that mobile clients retry
+	idempotencyRepo := postgres.NewIdempotencyRepository(db)
+	idempotent := middleware.IdempotencyMiddleware(idempotencyRepo, time.Duration(cfg.IdempotencyTTL)*time.Hour)
+	workers.add(func() { middleware.StartIdempotencyCleanup(idempotencyRepo, time.Hour) })
+
+	// Entries carry full before/after diffs of every university's records,
+	// so only super admins read them
+	auditHandler := handler.NewAuditLogHandler(auditRepo)
+	v1.GET("/audit-logs",
+		middleware.JWTMiddleware(jwtManager),
+		middleware.RoleMiddleware(string(domain.RoleSuperAdmin)),
+		auditHandler.GetAll,
+	)
+	docs.Describe("GET", "/api/v1/audit-logs", openapi.Doc{
+		Summary:  "List audit logs",
+		Response: listOf[domain.AuditLog]{},
+		Security: []string{openapi.SecurityAPIKey, openapi.SecurityBearer},
+		Query:    []string{"user_id", "entity_id", "entity_name", "action", "ip_address", "from", "to", "search", "limit", "offset"},
+	})
+
+	// Outbound webhooks: signed deliveries of domain events to university portals
+	webhookRepo := postgres.NewWebhookRepository(db)
+	webhookDispatcher := usecase.NewWebhookDispatcher(webhookRepo, nil)
+	workers.add(func() { webhookDispatcher.StartRetries(30 * time.Second) })
+
+	// The relay hands committed outbox events to webhooks and notifications
+	relay := usecase.NewOutboxRelay(outboxRepo,
+		webhookDispatcher,
+		usecase.NewNotificationDispatcher(postgres.NewNotificationRepository(db), cfg.Location()),
+	)
+	workers.add(func() { relay.Start(2 * time.Second) })
+	webhookHandler := handler.NewWebhookHandler(usecase.NewGenericUsecase[domain.WebhookSubscription](webhookRepo, ucOpts...), webhookRepo, webhookDispatcher)
+	whg := v1.Group("/webhooks",
+		middleware.JWTMiddleware(jwtManager),
+		middleware.RoleMiddleware(string(domain.RoleSuperAdmin), string(domain.RoleUniversityAdmin)),
+	)
+	{
+		whg.POST("", webhookHandler.Create)
+		whg.GET("", webhookHandler.GetAll)
+		whg.GET("/:id", webhookHandler.GetByID)
+		whg.PUT("/:id", webhookHandler.Update)
+		whg.DELETE("/:id", webhookHandler.Delete)
+		whg.POST("/:id/rotate-secret", webhookHandler.RotateSecret)
+		whg.GET("/:id/deliveries", webhookHandler.ListDeliveries)
+		whg.GET("/:id/deliveries/:delivery_id", webhookHandler.GetDelivery)
+		whg.POST("/:id/deliveries/:delivery_id/redeliver", webhookHandler.Redeliver)
+	}
+	docs.Collection(whg.BasePath(), domain.WebhookSubscription{})
+	adminAuth := []string{openapi.SecurityAPIKey, openapi.SecurityBearer}
+	docs.Describe("POST", "/api/v1/webhooks", openapi.Doc{Response: handler.WebhookSecretResponse{}, Status: 201, Security: adminAuth})
+	for _, m := range []string{"GET", "PUT", "DELETE"} {
+		docs.Describe(m, "/api/v1/webhooks/:id", openapi.Doc{Security: adminAuth})
+	}
+	docs.Describe("GET", "/api/v1/webhooks", openapi.Doc{Security: adminAuth})
+	docs.Describe("POST", "/api/v1/webhooks/:id/rotate-secret", openapi.Doc{Summary: "Rotate webhook signing secret", Response: handler.WebhookSecretResponse{}, Security: adminAuth})
+	docs.Describe("GET", "/api/v1/webhooks/:id/deliveries", openapi.Doc{
+		Summary:  "List webhook deliveries",
+		Response: listOf[domain.WebhookDelivery]{},
+		Security: adminAuth,
+		Query:    []string{"status", "event_name", "event_id", "limit", "offset"},
+	})
+	docs.Describe("GET", "/api/v1/webhooks/:id/deliveries/:delivery_id", openapi.Doc{Summary: "Get webhook delivery", Response: domain.WebhookDelivery{}, Security: adminAuth})
+	docs.Describe("POST", "/api/v1/webhooks/:id/deliveries/:delivery_id/redeliver", openapi.Doc{Summary: "Redeliver webhook delivery", Response: domain.WebhookDelivery{}, Security: adminAuth})
 
 	// Helper to register generic routes
-	registerRoutes[domain.University](v1, db, "universities")
-	registerRoutes[domain.Department](v1, db, "departments")
-	registerRoutes[domain.Session](v1, db, "sessions")
-	registerRoutes[domain.Batch](v1, db, "batches")
-	registerRoutes[domain.User](v1, db, "users")
+	universityGroup, universityHandler := registerRoutes[domain.University](v1, db, docs, "universities", ucOpts...)
+	departmentGroup, departmentHandler := registerRoutes[domain.Department](v1, db, docs, "departments", ucOpts...)
+	_, sessionHandler := registerRoutes[domain.Session](v1, db, docs, "sessions", ucOpts...)
+	batchGroup, batchHandler := registerRoutes[domain.Batch](v1, db, docs, "batches", ucOpts...)
+	registerRoutes[domain.User](v1, db, docs, "users", ucOpts...)
+
+	// Department home screen in one call; sub-queries share an 800ms budget
+	overviewUsecase := usecase.NewDepartmentOverviewUsecase(postgres.NewDepartmentOverviewRepository(db), 800*time.Millisecond)
+	departmentGroup.GET("/:id/overview", handler.NewDepartmentOverviewHandler(overviewUsecase).Get)
+	docs.Describe("GET", "/api/v1/departments/:id/overview", openapi.Doc{
+		Summary:  "Get department overview",
+		Response: usecase.DepartmentOverview{},
+	})
+
+	// Resolves front-end URLs like /du/psychology/batch-10 in one call
+	slugResolver := usecase.NewSlugResolver(
+		postgres.NewGormRepository[domain.University](db),
+		postgres.NewGormRepository[domain.Department](db),
+		postgres.NewGormRepository[domain.Batch](db),
+		slugAliasRepo,
+	)
+	v1.GET("/resolve/*path", handler.NewSlugResolverHandler(slugResolver).Resolve)
+	docs.Describe("GET", "/api/v1/resolve/*path", openapi.Doc{
+		Summary:  "Resolve a university/department/batch slug path",
+		Response: usecase.ResolvedPath{},
+	})
+
+	// Profile claiming: codes can be guessed, so attempts are limited per IP
+	// (verify and claim together) and per signed-in user, and wrong codes
+	// from all clients together are capped so spreading guesses over many
+	// addresses does not help either
+	claimIPLimiter := ratelimit.New(20, 15*time.Minute)
+	claimUserLimiter := ratelimit.New(5, 15*time.Minute)
+	claimFailureLimit := middleware.FailureLimitMiddleware(ratelimit.New(200, 15*time.Minute), middleware.GlobalKey("claim-code"), 404)
+	academicAdmin := []gin.HandlerFunc{
+		middleware.JWTMiddleware(jwtManager),
+		middleware.RoleMiddleware(string(domain.RoleSuperAdmin), string(domain.RoleUniversityAdmin), string(domain.RoleDepartmentAdmin)),
+	}
+	claimGuard := claimGuards{
+		verify: []gin.HandlerFunc{
+			middleware.RateLimitMiddleware(claimIPLimiter, middleware.ClientIPKey),
+			claimFailureLimit,
+		},
+		claim: []gin.HandlerFunc{
+			middleware.RateLimitMiddleware(claimIPLimiter, middleware.ClientIPKey),
+			middleware.JWTMiddleware(jwtManager),
+			middleware.RateLimitMiddleware(claimUserLimiter, middleware.UserKey),
+			claimFailureLimit,
+		},
+		admin: academicAdmin,
+	}
 
 	// Specialized Student Routes
 	studentRepo := postgres.NewGormRepository[domain.Student](db)
-	studentUsecase := usecase.NewGenericUsecase(studentRepo)
-	studentHandler := handler.NewStudentHandler(studentUsecase)
+	studentUsecase := usecase.NewGenericUsecase(studentRepo, ucOpts...)
+	userUsecase := usecase.NewGenericUsecase(postgres.NewGormRepository[domain.User](db), ucOpts...)
+	studentHandler := handler.NewStudentHandler(studentUsecase, postgres.NewClaimRepository[domain.Student](db), claimCodes, userUsecase, eventSink)
+	studentImporter := usecase.NewRosterImporter(studentUsecase, usecase.NewStudentRosterMapper(
+		studentRepo,
+		postgres.NewGormRepository[domain.Batch](db),
+		postgres.NewGormRepository[domain.Session](db),
+		postgres.NewGormRepository[domain.Hall](db),
+	), outboxRepo)
 	studentGroup := v1.Group("/students")
 	{
-		studentGroup.POST("", studentHandler.Create)
-		studentGroup.POST("/verify-code", studentHandler.VerifyCode)
-		studentGroup.POST("/claim-profile", studentHandler.ClaimProfile)
+		studentGroup.POST("", idempotent, studentHandler.Create)
 		studentGroup.GET("", studentHandler.GetAll)
 		studentGroup.GET("/:id", studentHandler.GetByID)
 		studentGroup.PUT("/:id", studentHandler.Update)
 		studentGroup.DELETE("/:id", studentHandler.Delete)
 	}
+	docs.Collection(studentGroup.BasePath(), domain.Student{})
+	registerImportRoute(studentGroup, docs, academicAdmin, studentImporter)
+	registerClaimRoutes(studentGroup, docs, claimGuard, studentHandler.ClaimHandler, handler.StudentClaimRequest{})
+	batchGroup.POST("/:id/claim-codes", append(claimGuard.admin, handler.NewClaimCodeSheetHandler(batchHandler.Usecase, studentUsecase).Generate)...)
+	docs.Describe("POST", batchGroup.BasePath()+"/:id/claim-codes", openapi.Doc{
+		Summary:  "Issue claim codes for all unclaimed students of a batch (JSON, CSV, XLSX or printable PDF)",
+		Response: handler.BatchClaimCodes{},
+		Security: adminAuth,
+		Query:    []string{"format", "only_missing"},
+	})
+
+	teacherGroup, teacherHandler := registerRoutes[domain.Teacher](v1, db, docs, "teachers", ucOpts...)
+	teacherImporter := usecase.NewRosterImporter(teacherHandler.Usecase, usecase.NewTeacherRosterMapper(postgres.NewGormRepository[domain.Teacher](db)), outboxRepo)
+	registerImportRoute(teacherGroup, docs, academicAdmin, teacherImporter)
+	registerClaimRoutes(teacherGroup, docs, claimGuard, handler.NewClaimHandler(teacherHandler.Usecase, postgres.NewClaimRepository[domain.Teacher](db), claimCodes, userUsecase, eventSink), handler.ClaimRequest{})
+
+	staffGroup, staffHandler := registerRoutes[domain.Staff](v1, db, docs, "staffs", ucOpts...)
+	staffImporter := usecase.NewRosterImporter(staffHandler.Usecase, usecase.NewStaffRosterMapper(postgres.NewGormRepository[domain.Staff](db)), outboxRepo)
+	registerImportRoute(staffGroup, docs, academicAdmin, staffImporter)
+	registerClaimRoutes(staffGroup, docs, claimGuard, handler.NewClaimHandler(staffHandler.Usecase, postgres.NewClaimRepository[domain.Staff](db), claimCodes, userUsecase, eventSink), handler.ClaimRequest{})
+
+	// Office hours: teachers publish weekly slots, students book them and the
+	// teacher approves or declines; both sides are notified with a calendar
+	// file
+	officeHourUsecase := usecase.NewGenericUsecase[domain.OfficeHour](postgres.NewGormRepository[domain.OfficeHour](db), ucOpts...)
+	appointmentRepo := postgres.NewAppointmentRepository(db)
+	officeHours := usecase.NewOfficeHours(
+		officeHourUsecase,
+		usecase.NewGenericUsecase[domain.Appointment](appointmentRepo, ucOpts...),
+		appointmentRepo,
+		teacherHandler.Usecase,
+		studentUsecase,
+		outboxRepo,
+		events,
+		cfg.Location(),
+	)
+	officeHourHandler := handler.NewOfficeHourHandler(officeHourUsecase, officeHours)
+	ohg := v1.Group("/office-hours")
+	{
+		ohg.POST("", middleware.JWTMiddleware(jwtManager), officeHourHandler.Create)
+		ohg.GET("", officeHourHandler.GetAll)
+		ohg.GET("/:id", officeHourHandler.GetByID)
+		ohg.GET("/:id/slots", officeHourHandler.Slots)
+		ohg.PUT("/:id", middleware.JWTMiddleware(jwtManager), officeHourHandler.Update)
+		ohg.DELETE("/:id", middleware.JWTMiddleware(jwtManager), officeHourHandler.Delete)
+	}
+	userAuth := []string{openapi.SecurityAPIKey, openapi.SecurityBearer}
+	docs.Collection(ohg.BasePath(), domain.OfficeHour{})
+	for _, m := range []string{"POST", "PUT", "DELETE"} {
+		path := ohg.BasePath()
+		if m != "POST" {
+			path += "/:id"
+		}
+		docs.Describe(m, path, openapi.Doc{Security: userAuth})
+	}
+	docs.Describe("GET", ohg.BasePath()+"/:id/slots", openapi.Doc{
+		Summary: "List bookable slots of office hours",
+		Response: struct {
+			Data  []usecase.Slot `json:"data"`
+			Count int            `json:"count"`
+		}{},
+		Query: []string{"from", "days"},
+	})
+
+	appointmentHandler := handler.NewAppointmentHandler(officeHours)
+	apg := v1.Group("/appointments", middleware.JWTMiddleware(jwtManager))
+	{
+		apg.POST("", appointmentHandler.Create)
+		apg.GET("", appointmentHandler.GetAll)
+		apg.GET("/:id", appointmentHandler.GetByID)
+		apg.GET("/:id/ics", appointmentHandler.Calendar)
+		apg.POST("/:id/approve", appointmentHandler.Approve)
+		apg.POST("/:id/decline", appointmentHandler.Decline)
+		apg.POST("/:id/cancel", appointmentHandler.Cancel)
+	}
+	docs.Describe("POST", apg.BasePath(), openapi.Doc{Summary: "Book an office-hour slot", Request: handler.BookAppointmentRequest{}, Response: domain.Appointment{}, Status: 201, Security: userAuth})
+	docs.Describe("GET", apg.BasePath(), openapi.Doc{Summary: "List my appointments as a student or teacher", Response: listOf[domain.Appointment]{}, Security: userAuth, Query: []string{"status", "limit", "offset"}})
+	docs.Describe("GET", apg.BasePath()+"/:id", openapi.Doc{Summary: "Get an appointment", Response: domain.Appointment{}, Security: userAuth})
+	docs.Describe("GET", apg.BasePath()+"/:id/ics", openapi.Doc{Summary: "Download an appointment as an iCalendar file", Security: userAuth})
+	docs.Describe("POST", apg.BasePath()+"/:id/approve", openapi.Doc{Summary: "Approve an appointment (teacher)", Request: handler.AppointmentResponseRequest{}, Response: domain.Appointment{}, Security: userAuth})
+	docs.Describe("POST", apg.BasePath()+"/:id/decline", openapi.Doc{Summary: "Decline an appointment (teacher)", Request: handler.AppointmentResponseRequest{}, Response: domain.Appointment{}, Security: userAuth})
+	docs.Describe("POST", apg.BasePath()+"/:id/cancel", openapi.Doc{Summary: "Cancel my appointment (student)", Response: domain.Appointment{}, Security: userAuth})
 
-	registerRoutes[domain.Teacher](v1, db, "teachers")
-	registerRoutes[domain.Staff](v1, db, "staffs")
-	crRepo := postgres.NewGormRepository[domain.CR](db)
-	crUsecase := usecase.NewGenericUsecase(crRepo)
-	crHandler := handler.NewCrHandler(crUsecase)
+	// CR terms: appointments, handovers and expiry keep Student.IsCR and the
+	// user's cr role in sync, so only academic admins may change them
+	crRepo := postgres.NewCRRepository(db)
+	crUsecase := usecase.NewGenericUsecase[domain.CR](crRepo, ucOpts...)
+	crLifecycle := usecase.NewCRLifecycle(crRepo, crUsecase, studentUsecase, userUsecase, outboxRepo, events, cfg.CRMaxPerBatch)
+	workers.add(func() { crLifecycle.StartExpiry(10 * time.Minute) })
+	crHandler := handler.NewCrHandler(crUsecase, crLifecycle)
 	crGroup := v1.Group("/crs")
 	{
-		crGroup.POST("", crHandler.Create)
+		crGroup.POST("", append(academicAdmin, crHandler.Create)...)
 		crGroup.GET("", crHandler.GetAll)
 		crGroup.GET("/:id", crHandler.GetByID)
-		crGroup.PUT("/:id", crHandler.Update)
-		crGroup.DELETE("/:id", crHandler.Delete)
+		crGroup.PUT("/:id", append(academicAdmin, crHandler.Update)...)
+		crGroup.DELETE("/:id", append(academicAdmin, crHandler.Delete)...)
+		crGroup.POST("/:id/handover", append(academicAdmin, crHandler.Handover)...)
+		crGroup.POST("/:id/end", append(academicAdmin, crHandler.End)...)
 	}
-	registerRoutes[domain.Verification](v1, db, "verifications")
+	batchGroup.GET("/:id/crs/history", crHandler.History)
+	docs.Collection(crGroup.BasePath(), domain.CR{})
+	docs.Describe("POST", crGroup.BasePath(), openapi.Doc{Summary: "Appoint a CR", Request: handler.AppointCRRequest{}, Response: domain.CR{}, Status: 201, Security: adminAuth})
+	docs.Describe("PUT", crGroup.BasePath()+"/:id", openapi.Doc{Security: adminAuth})
+	docs.Describe("DELETE", crGroup.BasePath()+"/:id", openapi.Doc{Security: adminAuth})
+	docs.Describe("POST", crGroup.BasePath()+"/:id/handover", openapi.Doc{
+		Summary:  "End a CR term and appoint the successor",
+		Request:  handler.AppointCRRequest{},
+		Response: domain.CR{},
+		Status:   201,
+		Security: adminAuth,
+	})
+	docs.Describe("POST", crGroup.BasePath()+"/:id/end", openapi.Doc{Summary: "End a CR term early", Response: domain.CR{}, Security: adminAuth})
+	docs.Describe("GET", batchGroup.BasePath()+"/:id/crs/history", openapi.Doc{Summary: "List current and past CRs of a batch", Response: struct {
+		Data  []domain.CR `json:"data"`
+		Count int         `json:"count"`
+	}{}})
+	registerRoutes[domain.Verification](v1, db, docs, "verifications", ucOpts...)
 
 	resourceRepo := postgres.NewResourceRepository(db)
-	resourceUsecase := usecase.NewGenericUsecase(resourceRepo)
-	resourceHandler := handler.NewResourceHandler(resourceUsecase)
+	resourceUsecase := usecase.NewGenericUsecase(resourceRepo, ucOpts...)
+	resourceHandler := handler.NewResourceHandler(resourceUsecase, eventSink)
 	rg := v1.Group("/resources")
 	{
-		rg.POST("", resourceHandler.Create)
+		rg.POST("", idempotent, resourceHandler.Create)
 		rg.GET("", resourceHandler.GetAll)
 		rg.GET("/:id", resourceHandler.GetByID)
 		rg.PUT("/:id", resourceHandler.Update)
@@ -115,12 +389,14 @@ func NewRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		// Engagement
 		rg.POST("/:id/download", resourceHandler.IncrementDownload)
 	}
+	docs.Collection(rg.BasePath(), domain.Resource{})
+	registerRevisionRoutes(rg, docs, resourceHandler.GenericHandler)
 
-	registerRoutes[domain.Transport](v1, db, "transports")
-	registerRoutes[domain.Attachment](v1, db, "attachments")
+	registerRoutes[domain.Transport](v1, db, docs, "transports", ucOpts...)
+	registerRoutes[domain.Attachment](v1, db, docs, "attachments", ucOpts...)
 
 	semesterRepo := postgres.NewSemesterRepository(db)
-	semesterUsecase := usecase.NewGenericUsecase[domain.Semester](semesterRepo)
+	semesterUsecase := usecase.NewGenericUsecase[domain.Semester](semesterRepo, ucOpts...)
 	semesterHandler := handler.NewGenericHandler[domain.Semester](semesterUsecase)
 	sg := v1.Group("/semesters")
 	{
@@ -130,13 +406,111 @@ func NewRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		sg.PUT("/:id", semesterHandler.Update)
 		sg.DELETE("/:id", semesterHandler.Delete)
 	}
+	docs.Collection(sg.BasePath(), domain.Semester{})
+
+	registerRoutes[domain.Hall](v1, db, docs, "halls", ucOpts...)
+
+	// Alumni directory: graduates claim their record like other profiles
+	// (without a role change) and then keep it up to date themselves
+	alumniRepo := postgres.NewAlumniRepository(db)
+	alumniHandler := handler.NewAlumniHandler(usecase.NewGenericUsecase[domain.Alumni](alumniRepo, ucOpts...), alumniRepo, postgres.NewClaimRepository[domain.Alumni](db), claimCodes, userUsecase, eventSink)
+	alumniGroup := v1.Group("/alumni")
+	{
+		alumniGroup.POST("", alumniHandler.Create)
+		alumniGroup.GET("", alumniHandler.GetAll)
+		alumniGroup.GET("/stats", alumniHandler.Stats)
+		alumniGroup.GET("/me", middleware.JWTMiddleware(jwtManager), alumniHandler.GetMe)
+		alumniGroup.PUT("/me", middleware.JWTMiddleware(jwtManager), alumniHandler.UpdateMe)
+		alumniGroup.GET("/:id", alumniHandler.GetByID)
+		alumniGroup.PUT("/:id", alumniHandler.Update)
+		alumniGroup.DELETE("/:id", alumniHandler.Delete)
+	}
+	docs.Collection(alumniGroup.BasePath(), domain.Alumni{})
+	registerClaimRoutes(alumniGroup, docs, claimGuard, alumniHandler.ClaimHandler, handler.ClaimRequest{})
+	docs.Describe("GET", alumniGroup.BasePath()+"/stats", openapi.Doc{
+		Summary:  "Top employers and locations of alumni, overall and per batch",
+		Response: domain.AlumniStats{},
+		Query:    []string{"university_id", "department_id", "batch", "passing_year", "search", "top"},
+	})
+	docs.Describe("GET", alumniGroup.BasePath()+"/me", openapi.Doc{Summary: "Get my alumni record", Response: domain.Alumni{}, Security: []string{openapi.SecurityAPIKey, openapi.SecurityBearer}})
+	docs.Describe("PUT", alumniGroup.BasePath()+"/me", openapi.Doc{
+		Summary:  "Update my alumni record",
+		Request:  handler.AlumniProfileRequest{},
+		Response: domain.Alumni{},
+		Security: []string{openapi.SecurityAPIKey, openapi.SecurityBearer},
+	})
+
+	// Year-end wizard: promote a batch to its next semester or graduate it
+	// into the alumni directory, each with a dry-run preview
+	progression := usecase.NewBatchProgression(postgres.NewBatchRepository(db), batchHandler.Usecase, semesterUsecase, studentUsecase, alumniHandler.Usecase, crLifecycle, outboxRepo, events)
+	progressionHandler := handler.NewBatchProgressionHandler(progression)
+	batchGroup.POST("/:id/promote", append(academicAdmin, progressionHandler.Promote)...)
+	batchGroup.POST("/:id/graduate", append(academicAdmin, progressionHandler.Graduate)...)
+	docs.Describe("POST", batchGroup.BasePath()+"/:id/promote", openapi.Doc{
+		Summary:  "Move a batch to its next semester",
+		Response: usecase.BatchPromotion{},
+		Security: adminAuth,
+		Query:    []string{"dry_run"},
+	})
+	docs.Describe("POST", batchGroup.BasePath()+"/:id/graduate", openapi.Doc{
+		Summary:  "Graduate a batch and add its students to the alumni directory",
+		Request:  handler.GraduateRequest{},
+		Response: usecase.BatchGraduation{},
+		Security: adminAuth,
+		Query:    []string{"dry_run"},
+	})
+	// Blood donors opt in themselves and are listed without contact details;
+	// requests notify matching donors, and a donor's phone number is shown
+	// to the requester only once they accept. Requests are rate limited so
+	// the registry cannot be used to harvest numbers.
+	bloodDonors := usecase.NewBloodDonors(
+		postgres.NewBloodDonorRepository(db),
+		postgres.NewGormRepository[domain.BloodRequest](db),
+		postgres.NewGormRepository[domain.BloodRequestMatch](db),
+		studentUsecase,
+		postgres.NewGormRepository[domain.Notification](db),
+		outboxRepo,
+		time.Duration(cfg.BloodDonationCooldown)*24*time.Hour,
+		20,
+	)
+	donorHandler := handler.NewBloodDonorHandler(bloodDonors)
+	donorRequestLimiter := ratelimit.New(5, time.Hour)
+	donorGroup := v1.Group("/donors")
+	{
+		donorGroup.GET("", donorHandler.GetAll)
+		donorGroup.GET("/me", middleware.JWTMiddleware(jwtManager), donorHandler.GetMe)
+		donorGroup.PUT("/me", middleware.JWTMiddleware(jwtManager), donorHandler.UpdateMe)
+		donorGroup.DELETE("/me", middleware.JWTMiddleware(jwtManager), donorHandler.DeleteMe)
+		donorGroup.POST("/requests", middleware.JWTMiddleware(jwtManager), middleware.RateLimitMiddleware(donorRequestLimiter, middleware.UserKey), donorHandler.CreateRequest)
+		donorGroup.GET("/requests", middleware.JWTMiddleware(jwtManager), donorHandler.ListRequests)
+		donorGroup.GET("/requests/:id", middleware.JWTMiddleware(jwtManager), donorHandler.GetRequest)
+		donorGroup.POST("/requests/:id/accept", middleware.JWTMiddleware(jwtManager), donorHandler.Accept)
+		donorGroup.POST("/requests/:id/decline", middleware.JWTMiddleware(jwtManager), donorHandler.Decline)
+	}
+	docs.Describe("GET", donorGroup.BasePath(), openapi.Doc{
+		Summary:  "List available blood donors past their donation cooldown",
+		Response: listOf[domain.BloodDonor]{},
+		Query:    []string{"blood_group", "university_id", "department_id", "limit", "offset"},
+	})
+	docs.Describe("GET", donorGroup.BasePath()+"/me", openapi.Doc{Summary: "Get my blood donor entry", Response: domain.BloodDonor{}, Security: userAuth})
+	docs.Describe("PUT", donorGroup.BasePath()+"/me", openapi.Doc{Summary: "Opt in as a blood donor or update my entry", Request: handler.DonorProfileRequest{}, Response: domain.BloodDonor{}, Security: userAuth})
+	docs.Describe("DELETE", donorGroup.BasePath()+"/me", openapi.Doc{Summary: "Opt out as a blood donor", Security: userAuth})
+	docs.Describe("POST", donorGroup.BasePath()+"/requests", openapi.Doc{
+		Summary:  "Ask matching donors for blood",
+		Request:  handler.BloodRequestRequest{},
+		Response: domain.BloodRequest{},
+		Status:   201,
+		Security: userAuth,
+	})
+	docs.Describe("GET", donorGroup.BasePath()+"/requests", openapi.Doc{Summary: "List my blood requests", Response: listOf[domain.BloodRequest]{}, Security: userAuth, Query: []string{"limit", "offset"}})
+	docs.Describe("GET", donorGroup.BasePath()+"/requests/:id", openapi.Doc{Summary: "Get a blood request I made or was asked for", Response: domain.BloodRequest{}, Security: userAuth})
+	docs.Describe("POST", donorGroup.BasePath()+"/requests/:id/accept", openapi.Doc{Summary: "Accept a blood request and share my phone number", Response: domain.BloodRequestMatch{}, Security: userAuth})
+	docs.Describe("POST", donorGroup.BasePath()+"/requests/:id/decline", openapi.Doc{Summary: "Decline a blood request", Response: domain.BloodRequestMatch{}, Security: userAuth})
 
-	registerRoutes[domain.Hall](v1, db, "halls")
-	registerRoutes[domain.Alumni](v1, db, "alumni")
-	registerRoutes[domain.Bookmark](v1, db, "bookmarks")
+	registerRoutes[domain.Bookmark](v1, db, docs, "bookmarks", ucOpts...)
 
 	courseRepo := postgres.NewCourseRepository(db)
-	courseUsecase := usecase.NewGenericUsecase[domain.Course](courseRepo)
+	courseUsecase := usecase.NewGenericUsecase[domain.Course](courseRepo, ucOpts...)
 	courseHandler := handler.NewGenericHandler[domain.Course](courseUsecase)
 	cg := v1.Group("/courses")
 	{
@@ -146,11 +520,203 @@ func NewRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		cg.PUT("/:id", courseHandler.Update)
 		cg.DELETE("/:id", courseHandler.Delete)
 	}
+	docs.Collection(cg.BasePath(), domain.Course{})
+	registerRevisionRoutes(cg, docs, courseHandler)
+
+	// Course offerings: a course taught to a batch in a semester, with its
+	// assigned teachers. Academic admins manage them; teachers and students
+	// see their own courses.
+	offeringRepo := postgres.NewCourseOfferingRepository(db)
+	offeringUsecase := usecase.NewGenericUsecase[domain.CourseOffering](offeringRepo, ucOpts...)
+	courseOfferings := usecase.NewCourseOfferings(
+		offeringUsecase,
+		offeringRepo,
+		courseUsecase,
+		batchHandler.Usecase,
+		semesterUsecase,
+		sessionHandler.Usecase,
+		teacherHandler.Usecase,
+		studentUsecase,
+		outboxRepo,
+		events,
+	)
+	offeringHandler := handler.NewCourseOfferingHandler(offeringUsecase, courseOfferings)
+	cog := v1.Group("/course-offerings")
+	{
+		cog.POST("", append(academicAdmin, offeringHandler.Create)...)
+		cog.GET("", offeringHandler.GetAll)
+		cog.GET("/:id", offeringHandler.GetByID)
+		cog.PUT("/:id", append(academicAdmin, offeringHandler.Update)...)
+		cog.DELETE("/:id", append(academicAdmin, offeringHandler.Delete)...)
+		cog.PUT("/:id/teachers", append(academicAdmin, offeringHandler.SetTeachers)...)
+		cog.POST("/:id/teachers/:teacher_id", append(academicAdmin, offeringHandler.AddTeacher)...)
+		cog.DELETE("/:id/teachers/:teacher_id", append(academicAdmin, offeringHandler.RemoveTeacher)...)
+	}
+	teacherGroup.GET("/me/courses", middleware.JWTMiddleware(jwtManager), offeringHandler.TeacherCourses)
+	studentGroup.GET("/me/courses", middleware.JWTMiddleware(jwtManager), offeringHandler.StudentCourses)
+	docs.Collection(cog.BasePath(), domain.CourseOffering{})
+	docs.Describe("POST", cog.BasePath(), openapi.Doc{Security: adminAuth})
+	docs.Describe("PUT", cog.BasePath()+"/:id", openapi.Doc{Security: adminAuth})
+	docs.Describe("DELETE", cog.BasePath()+"/:id", openapi.Doc{Security: adminAuth})
+	docs.Describe("PUT", cog.BasePath()+"/:id/teachers", openapi.Doc{
+		Summary:  "Replace the teachers of a course offering",
+		Request:  handler.CourseTeachersRequest{},
+		Response: domain.CourseOffering{},
+		Security: adminAuth,
+	})
+	docs.Describe("POST", cog.BasePath()+"/:id/teachers/:teacher_id", openapi.Doc{Summary: "Assign a teacher to a course offering", Response: domain.CourseOffering{}, Security: adminAuth})
+	docs.Describe("DELETE", cog.BasePath()+"/:id/teachers/:teacher_id", openapi.Doc{Summary: "Unassign a teacher from a course offering", Response: domain.CourseOffering{}, Security: adminAuth})
+	docs.Describe("GET", teacherGroup.BasePath()+"/me/courses", openapi.Doc{
+		Summary:  "List the course offerings I teach",
+		Response: listOf[domain.CourseOffering]{},
+		Security: userAuth,
+		Query:    []string{"batch_id", "semester_id", "session_id", "course_id", "section", "search", "limit", "offset"},
+	})
+	docs.Describe("GET", studentGroup.BasePath()+"/me/courses", openapi.Doc{Summary: "My batch's current semester and its course offerings", Response: usecase.StudentCourses{}, Security: userAuth})
 
-	registerRoutes[domain.CourseCategory](v1, db, "course-categories")
-	registerRoutes[domain.CoursePrefix](v1, db, "course-prefixes")
+	// Attendance: one roll per course offering and date, taken by academic
+	// admins, the batch's CRs or the course's teachers. Students below
+	// ATTENDANCE_THRESHOLD percent are flagged
+	attendanceRepo := postgres.NewAttendanceRepository(db)
+	attendance := usecase.NewAttendance(
+		usecase.NewGenericUsecase[domain.AttendanceSession](attendanceRepo, ucOpts...),
+		attendanceRepo,
+		offeringUsecase,
+		studentUsecase,
+		teacherHandler.Usecase,
+		outboxRepo,
+		cfg.AttendanceThreshold,
+		cfg.Location(),
+	)
+	attendanceHandler := handler.NewAttendanceHandler(attendance)
+	atg := cog.Group("/:id/attendance", middleware.JWTMiddleware(jwtManager))
+	{
+		atg.GET("", attendanceHandler.Sessions)
+		atg.GET("/summary", attendanceHandler.Summary)
+		atg.GET("/:date", attendanceHandler.Roll)
+		atg.PUT("/:date", attendanceHandler.Take)
+	}
+	studentGroup.GET("/me/attendance", middleware.JWTMiddleware(jwtManager), attendanceHandler.Mine)
+	docs.Describe("GET", atg.BasePath(), openapi.Doc{
+		Summary:  "Dates a course offering's attendance was taken",
+		Response: listOf[domain.AttendanceSession]{},
+		Security: userAuth,
+		Query:    []string{"limit", "offset"},
+	})
+	docs.Describe("GET", atg.BasePath()+"/summary", openapi.Doc{
+		Summary:  "Attendance per student of a course offering (CSV or XLSX with ?format=)",
+		Response: usecase.AttendanceSummary{},
+		Security: userAuth,
+		Query:    []string{"threshold", "format"},
+	})
+	docs.Describe("GET", atg.BasePath()+"/:date", openapi.Doc{Summary: "Attendance of a course offering on a date", Response: domain.AttendanceSession{}, Security: userAuth})
+	docs.Describe("PUT", atg.BasePath()+"/:date", openapi.Doc{
+		Summary:  "Take or correct attendance of a course offering on a date",
+		Request:  handler.AttendanceRequest{},
+		Response: domain.AttendanceSession{},
+		Security: userAuth,
+	})
+	docs.Describe("GET", studentGroup.BasePath()+"/me/attendance", openapi.Doc{
+		Summary: "My attendance per course",
+		Response: struct {
+			Data  []usecase.CourseAttendance `json:"data"`
+			Count int                        `json:"count"`
+		}{},
+		Security: userAuth,
+	})
+
+	// Academic calendar: holidays, semester dates, deadlines and other events
+	// of a university or one of its departments, kept by academic admins.
+	// The routine leaves out its days off; the .ics feeds need no API key
+	calendarRepo := postgres.NewCalendarEventRepository(db)
+	calendarUsecase := usecase.NewGenericUsecase[domain.CalendarEvent](calendarRepo, ucOpts...)
+	academicCalendar := usecase.NewAcademicCalendar(calendarUsecase, calendarRepo, universityHandler.Usecase, departmentHandler.Usecase, cfg.Location())
+	calendarHandler := handler.NewAcademicCalendarHandler(calendarUsecase, academicCalendar)
+	ceg := v1.Group("/calendar-events")
+	{
+		ceg.POST("", append(academicAdmin, calendarHandler.Create)...)
+		ceg.GET("", calendarHandler.GetAll)
+		ceg.GET("/:id", calendarHandler.GetByID)
+		ceg.PUT("/:id", append(academicAdmin, calendarHandler.Update)...)
+		ceg.DELETE("/:id", append(academicAdmin, calendarHandler.Delete)...)
+	}
+	universityGroup.GET("/:id/calendar", calendarHandler.UniversityRange)
+	universityGroup.GET("/:id/working-day", calendarHandler.UniversityWorkingDay)
+	departmentGroup.GET("/:id/calendar", calendarHandler.DepartmentRange)
+	departmentGroup.GET("/:id/working-day", calendarHandler.DepartmentWorkingDay)
+	r.Group(v1.BasePath()).GET("/universities/:id/calendar.ics", calendarHandler.UniversityFeed)
+	r.Group(v1.BasePath()).GET("/departments/:id/calendar.ics", calendarHandler.DepartmentFeed)
+	docs.Collection(ceg.BasePath(), domain.CalendarEvent{})
+	docs.Describe("POST", ceg.BasePath(), openapi.Doc{Security: adminAuth})
+	docs.Describe("PUT", ceg.BasePath()+"/:id", openapi.Doc{Security: adminAuth})
+	docs.Describe("DELETE", ceg.BasePath()+"/:id", openapi.Doc{Security: adminAuth})
+	for _, scope := range []struct{ group, name string }{
+		{universityGroup.BasePath(), "university"},
+		{departmentGroup.BasePath(), "department"},
+	} {
+		docs.Describe("GET", scope.group+"/:id/calendar", openapi.Doc{
+			Summary: "Academic calendar of a " + scope.name + " for a month or date range",
+			Response: struct {
+				Data  []usecase.Occurrence `json:"data"`
+				Count int                  `json:"count"`
+			}{},
+			Query: []string{"month", "from", "to", "category"},
+		})
+		docs.Describe("GET", scope.group+"/:id/working-day", openapi.Doc{
+			Summary:  "Whether a date is a working day at a " + scope.name,
+			Response: usecase.WorkingDay{},
+			Query:    []string{"date"},
+		})
+		docs.Describe("GET", scope.group+"/:id/calendar.ics", openapi.Doc{Summary: "Academic calendar of a " + scope.name + " as an iCalendar feed", Public: true})
+	}
+
+	// Class routine: weekly classes of course offerings, kept by academic
+	// admins and the batch's CRs. Saving refuses room and teacher clashes.
+	// The .ics feed is registered outside v1 so calendar apps can subscribe
+	// without an API key.
+	routineRepo := postgres.NewRoutineRepository(db)
+	routineUsecase := usecase.NewGenericUsecase[domain.RoutineEntry](routineRepo, ucOpts...)
+	routines := usecase.NewRoutines(routineUsecase, routineRepo, offeringUsecase, batchHandler.Usecase, studentUsecase, academicCalendar, outboxRepo, cfg.Location())
+	routineHandler := handler.NewRoutineHandler(routineUsecase, routines)
+	rtg := v1.Group("/routine-entries")
+	{
+		rtg.POST("", middleware.JWTMiddleware(jwtManager), routineHandler.Create)
+		rtg.GET("", routineHandler.GetAll)
+		rtg.GET("/:id", routineHandler.GetByID)
+		rtg.PUT("/:id", middleware.JWTMiddleware(jwtManager), routineHandler.Update)
+		rtg.DELETE("/:id", middleware.JWTMiddleware(jwtManager), routineHandler.Delete)
+	}
+	batchGroup.GET("/:id/routine", routineHandler.Week)
+	r.Group(v1.BasePath()).GET("/batches/:id/routine.ics", routineHandler.Calendar)
+	studentGroup.GET("/me/classes", middleware.JWTMiddleware(jwtManager), routineHandler.Today)
+	docs.Collection(rtg.BasePath(), domain.RoutineEntry{})
+	for _, m := range []string{"POST", "PUT", "DELETE"} {
+		path := rtg.BasePath()
+		if m != "POST" {
+			path += "/:id"
+		}
+		docs.Describe(m, path, openapi.Doc{Security: userAuth})
+	}
+	docs.Describe("GET", batchGroup.BasePath()+"/:id/routine", openapi.Doc{
+		Summary:  "Weekly class routine of a batch",
+		Response: listOf[domain.RoutineEntry]{},
+		Query:    []string{"date"},
+	})
+	docs.Describe("GET", batchGroup.BasePath()+"/:id/routine.ics", openapi.Doc{Summary: "Class routine of a batch as an iCalendar feed", Public: true})
+	docs.Describe("GET", studentGroup.BasePath()+"/me/classes", openapi.Doc{
+		Summary: "My classes today (or on ?date=)",
+		Response: struct {
+			Data  []usecase.Class `json:"data"`
+			Count int             `json:"count"`
+		}{},
+		Security: userAuth,
+		Query:    []string{"date"},
+	})
+
+	registerRoutes[domain.CourseCategory](v1, db, docs, "course-categories", ucOpts...)
+	registerRoutes[domain.CoursePrefix](v1, db, docs, "course-prefixes", ucOpts...)
 	chapterRepo := postgres.NewChapterRepository(db)
-	chapterUsecase := usecase.NewGenericUsecase(chapterRepo)
+	chapterUsecase := usecase.NewGenericUsecase(chapterRepo, ucOpts...)
 	chapterHandler := handler.NewGenericHandler(chapterUsecase)
 	chg := v1.Group("/chapters")
 	{
@@ -160,10 +726,56 @@ func NewRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		chg.PUT("/:id", chapterHandler.Update)
 		chg.DELETE("/:id", chapterHandler.Delete)
 	}
+	docs.Collection(chg.BasePath(), domain.Chapter{})
+	registerRevisionRoutes(chg, docs, chapterHandler)
+
+	// Exam schedule: academic admins and the batch's CRs keep it; students
+	// are reminded EXAM_REMINDER_HOURS ahead of each exam, and the .ics feed
+	// needs no API key, like the routine feed
+	examRepo := postgres.NewExamRepository(db)
+	examUsecase := usecase.NewGenericUsecase[domain.Exam](examRepo, ucOpts...)
+	exams := usecase.NewExams(
+		examUsecase,
+		examRepo,
+		courseUsecase,
+		batchHandler.Usecase,
+		chapterUsecase,
+		studentUsecase,
+		postgres.NewGormRepository[domain.Notification](db),
+		outboxRepo,
+		cfg.ExamReminderLeads(),
+		cfg.Location(),
+	)
+	workers.add(func() { exams.StartReminders(10 * time.Minute) })
+	examHandler := handler.NewExamHandler(examUsecase, exams)
+	eg := v1.Group("/exams")
+	{
+		eg.POST("", middleware.JWTMiddleware(jwtManager), examHandler.Create)
+		eg.GET("", examHandler.GetAll)
+		eg.GET("/:id", examHandler.GetByID)
+		eg.PUT("/:id", middleware.JWTMiddleware(jwtManager), examHandler.Update)
+		eg.DELETE("/:id", middleware.JWTMiddleware(jwtManager), examHandler.Delete)
+	}
+	batchGroup.GET("/:id/exams", examHandler.Upcoming)
+	r.Group(v1.BasePath()).GET("/batches/:id/exams.ics", examHandler.Calendar)
+	docs.Collection(eg.BasePath(), domain.Exam{})
+	for _, m := range []string{"POST", "PUT", "DELETE"} {
+		path := eg.BasePath()
+		if m != "POST" {
+			path += "/:id"
+		}
+		docs.Describe(m, path, openapi.Doc{Security: userAuth})
+	}
+	docs.Describe("GET", batchGroup.BasePath()+"/:id/exams", openapi.Doc{
+		Summary:  "Upcoming exams of a batch",
+		Response: listOf[domain.Exam]{},
+		Query:    []string{"type", "limit", "offset"},
+	})
+	docs.Describe("GET", batchGroup.BasePath()+"/:id/exams.ics", openapi.Doc{Summary: "Exams of a batch as an iCalendar feed", Public: true})
 
 	// specialized Banner Routes
 	bannerRepo := postgres.NewBannerRepository(db)
-	bannerUsecase := usecase.NewGenericUsecase[domain.Banner](bannerRepo)
+	bannerUsecase := usecase.NewGenericUsecase[domain.Banner](bannerRepo, ucOpts...)
 	bannerHandler := handler.NewGenericHandler[domain.Banner](bannerUsecase)
 	bannerGroup := v1.Group("/banners")
 	{
@@ -173,14 +785,15 @@ func NewRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		bannerGroup.PUT("/:id", bannerHandler.Update)
 		bannerGroup.DELETE("/:id", bannerHandler.Delete)
 	}
+	docs.Collection(bannerGroup.BasePath(), domain.Banner{})
 
-	registerRoutes[domain.EmergencyContact](v1, db, "emergency-contacts")
+	registerRoutes[domain.EmergencyContact](v1, db, docs, "emergency-contacts", ucOpts...)
 
 	// R2 Upload Routes
 	storage, err := storage.NewR2Storage(cfg)
 	if err == nil {
 		uploadHandler := handler.NewUploadHandler(db, storage)
-		v1.POST("/upload", uploadHandler.UploadImage)
+		v1.POST("/upload", idempotent, uploadHandler.UploadImage)
 		r.GET("/upload", uploadHandler.ShowUploadPage) // Serving the demo page at root /upload
 	}
 
@@ -194,12 +807,60 @@ func NewRouter(cfg *config.Config, db *gorm.DB) *gin.Engine {
 		subGroup.GET("/user/:uid", subHandler.GetUserSubscription)
 	}
 
-	return r
+	// Everything registered above is documented; the docs routes themselves are not.
+	spec := docs.Build(r.Routes())
+	r.GET("/openapi.json", openapi.SpecHandler(spec))
+	r.GET("/docs", openapi.DocsHandler("/openapi.json"))
+
+	return r, workers
+}
+
+// Workers are the background jobs behind the API: the audit writer, event
+// bus, outbox relay, webhook retries, idempotency cleanup, CR term expiry and exam
+// reminders. NewRouter wires them but leaves starting them to the caller,
+// so building the router, e.g. for the OpenAPI spec, has no side effects.
+type Workers struct {
+	starts []func()
+	stops  []func()
+}
+
+func (w *Workers) add(start func()) {
+	w.starts = append(w.starts, start)
+}
+
+// onStop registers work to finish on shutdown, e.g. flushing a queue.
+func (w *Workers) onStop(stop func()) {
+	w.stops = append(w.stops, stop)
 }
 
-func registerRoutes[T any](group *gin.RouterGroup, db *gorm.DB, path string) {
+// Start launches every worker in the background.
+func (w *Workers) Start() {
+	for _, start := range w.starts {
+		start()
+	}
+}
+
+// Stop finishes the workers' pending work, in reverse order of
+// registration. Call it after the server stopped taking requests.
+func (w *Workers) Stop() {
+	for i := len(w.stops) - 1; i >= 0; i-- {
+		w.stops[i]()
+	}
+}
+
+// listOf documents the {data, count, limit, offset} list envelope.
+type listOf[T any] struct {
+	Data   []T   `json:"data"`
+	Count  int64 `json:"count"`
+	Limit  int   `json:"limit"`
+	Offset int   `json:"offset"`
+}
+
+// registerRoutes wires standard CRUD for T and returns the group and handler
+// so callers can hang extra routes off them.
+func registerRoutes[T any](group *gin.RouterGroup, db *gorm.DB, docs *openapi.Builder, path string, opts ...usecase.Option) (*gin.RouterGroup, *handler.GenericHandler[T]) {
 	repo := postgres.NewGormRepository[T](db)
-	uc := usecase.NewGenericUsecase(repo)
+	uc := usecase.NewGenericUsecase(repo, opts...)
 	h := handler.NewGenericHandler(uc)
 
 	g := group.Group("/" + path)
@@ -210,4 +871,98 @@ func registerRoutes[T any](group *gin.RouterGroup, db *gorm.DB, path string) {
 		g.PUT("/:id", h.Update)
 		g.DELETE("/:id", h.Delete)
 	}
+	docs.Collection(g.BasePath(), *new(T))
+	registerSlugRoutes(g, docs, h)
+	registerRevisionRoutes(g, docs, h)
+
+	return g, h
+}
+
+// registerSlugRoutes adds GET /by-slug/:slug for models implementing
+// domain.Slugged.
+func registerSlugRoutes[T any](g *gin.RouterGroup, docs *openapi.Builder, h *handler.GenericHandler[T]) {
+	s, ok := any(new(T)).(domain.Slugged)
+	if !ok {
+		return
+	}
+
+	g.GET("/by-slug/:slug", h.GetBySlug)
+
+	d := openapi.Doc{Summary: "Get " + reflect.TypeOf(new(T)).Elem().Name() + " by slug", Response: *new(T)}
+	if s.SlugScope() != "" {
+		d.Query = []string{s.SlugScope()}
+	}
+	docs.Describe("GET", g.BasePath()+"/by-slug/:slug", d)
+}
+
+// claimGuards are the middleware in front of the profile claim routes.
+type claimGuards struct {
+	verify []gin.HandlerFunc
+	claim  []gin.HandlerFunc
+	admin  []gin.HandlerFunc
+}
+
+// registerClaimRoutes adds verify-code, claim-profile and code reissue for
+// profiles implementing domain.Claimable.
+func registerClaimRoutes[T any](g *gin.RouterGroup, docs *openapi.Builder, guard claimGuards, h *handler.ClaimHandler[T], claimRequest any) {
+	g.POST("/verify-code", append(guard.verify, h.VerifyCode)...)
+	g.POST("/claim-profile", append(guard.claim, h.ClaimProfile)...)
+	g.POST("/:id/claim-code", append(guard.admin, h.ReissueCode)...)
+
+	name := reflect.TypeOf(new(T)).Elem().Name()
+	docs.Describe("POST", g.BasePath()+"/verify-code", openapi.Doc{
+		Summary:  "Look up an unclaimed " + name + " profile by verification code",
+		Request:  handler.ClaimRequest{},
+		Response: *new(T),
+	})
+	docs.Describe("POST", g.BasePath()+"/claim-profile", openapi.Doc{
+		Summary:  "Claim a " + name + " profile for the signed-in user",
+		Request:  claimRequest,
+		Response: *new(T),
+		Security: []string{openapi.SecurityAPIKey, openapi.SecurityBearer},
+	})
+	docs.Describe("POST", g.BasePath()+"/:id/claim-code", openapi.Doc{
+		Summary:  "Issue a new verification code for a " + name + " profile",
+		Response: *new(T),
+		Security: []string{openapi.SecurityAPIKey, openapi.SecurityBearer},
+	})
+}
+
+// registerImportRoute adds the CSV/XLSX roster import for admins of the
+// target university or department.
+func registerImportRoute[T any](g *gin.RouterGroup, docs *openapi.Builder, admin []gin.HandlerFunc, importer *usecase.RosterImporter[T]) {
+	g.POST("/import", append(admin, handler.NewRosterImportHandler(importer).Import)...)
+
+	docs.Describe("POST", g.BasePath()+"/import", openapi.Doc{
+		Summary:  "Import " + reflect.TypeOf(new(T)).Elem().Name() + " profiles from a CSV or XLSX roster",
+		Response: usecase.ImportReport{},
+		Security: []string{openapi.SecurityAPIKey, openapi.SecurityBearer},
+		Query:    []string{"university_id", "department_id", "dry_run", "skip_duplicates"},
+	})
+}
+
+// registerRevisionRoutes adds history/diff/rollback routes for models
+// implementing domain.Versioned; other models are left untouched.
+func registerRevisionRoutes[T any](g *gin.RouterGroup, docs *openapi.Builder, h *handler.GenericHandler[T]) {
+	if v, ok := any(new(T)).(domain.Versioned); !ok || !v.KeepRevisions() {
+		return
+	}
+
+	g.GET("/:id/revisions", h.ListRevisions)
+	g.GET("/:id/revisions/diff", h.DiffRevisions)
+	g.GET("/:id/revisions/:rev", h.GetRevision)
+	g.POST("/:id/revisions/:rev/restore", h.RestoreRevision)
+
+	base := g.BasePath()
+	docs.Describe("GET", base+"/:id/revisions", openapi.Doc{Summary: "List revisions", Response: struct {
+		Data  []domain.Revision `json:"data"`
+		Count int               `json:"count"`
+	}{}})
+	docs.Describe("GET", base+"/:id/revisions/diff", openapi.Doc{Summary: "Diff two revisions", Query: []string{"from", "to"}, Response: struct {
+		From    int                            `json:"from"`
+		To      int                            `json:"to"`
+		Changes map[string]usecase.FieldChange `json:"changes"`
+	}{}})
+	docs.Describe("GET", base+"/:id/revisions/:rev", openapi.Doc{Summary: "Get revision", Response: domain.Revision{}})
+	docs.Describe("POST", base+"/:id/revisions/:rev/restore", openapi.Doc{Summary: "Restore revision", Response: *new(T)})
 }
@@ -0,0 +1,29 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Actor describes who is performing a request.
+// It travels on the request context so lower layers can attribute changes.
+type Actor struct {
+	UserID    uuid.UUID
+	Role      string
+	IPAddress string
+	UserAgent string
+}
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the given actor.
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the actor stored on ctx, if any.
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok
+}
@@ -1,6 +1,9 @@
 package domain
 
 import (
+	"context"
+	"time"
+
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
 )
@@ -8,20 +11,69 @@ import (
 // Alumni represents a graduate of the university.
 type Alumni struct {
 	Base
-	FullName      string         `gorm:"size:100;not null" json:"full_name"`
-	StudentID     string         `gorm:"size:50;not null" json:"student_id"`
-	Email         string         `gorm:"size:100" json:"email"`
-	Phone         string         `gorm:"size:20" json:"phone"`
-	Batch         string         `gorm:"size:50" json:"batch"`
-	PassingYear   string         `gorm:"size:20" json:"passing_year"`
-	CurrentStatus string         `gorm:"size:100" json:"current_status"`
-	Organization  string         `gorm:"size:100" json:"organization"`
-	Designation   string         `gorm:"size:100" json:"designation"`
-	Location      string         `gorm:"size:100" json:"location"`
-	Bio           string         `gorm:"type:text" json:"bio"`
-	ProfileImage  string         `json:"profile_image"`
-	SocialLinks   datatypes.JSON `gorm:"type:jsonb" json:"social_links"` // e.g. {"facebook": "...", "linkedin": "..."}
-	CreatedBy     uuid.UUID      `gorm:"type:uuid" json:"created_by"`
-	UniversityID  uuid.UUID      `gorm:"type:uuid;index" json:"university_id"`
-	DepartmentID  uuid.UUID      `gorm:"type:uuid;index" json:"department_id"`
+	UserID           *uuid.UUID     `gorm:"type:uuid;uniqueIndex" json:"user_id,omitempty"` // Set once the graduate claims the record
+	FullName         string         `gorm:"size:100;not null" json:"full_name"`
+	StudentID        string         `gorm:"size:50;not null" json:"student_id"`
+	Email            string         `gorm:"size:100" json:"email"`
+	Phone            string         `gorm:"size:20" json:"phone"`
+	Batch            string         `gorm:"size:50;index" json:"batch"`
+	PassingYear      string         `gorm:"size:20" json:"passing_year"`
+	CurrentStatus    string         `gorm:"size:100" json:"current_status"`
+	Organization     string         `gorm:"size:100" json:"organization"`
+	Designation      string         `gorm:"size:100" json:"designation"`
+	Location         string         `gorm:"size:100" json:"location"`
+	Bio              string         `gorm:"type:text" json:"bio"`
+	ProfileImage     string         `json:"profile_image"`
+	SocialLinks      datatypes.JSON `gorm:"type:jsonb" json:"social_links"` // e.g. {"facebook": "...", "linkedin": "..."}
+	CreatedBy        uuid.UUID      `gorm:"type:uuid" json:"created_by"`
+	UniversityID     uuid.UUID      `gorm:"type:uuid;index" json:"university_id"`
+	DepartmentID     uuid.UUID      `gorm:"type:uuid;index" json:"department_id"`
+	VerificationCode string         `gorm:"size:64;uniqueIndex:idx_alumni_claim_code,where:user_id IS NULL AND verification_code <> '' AND deleted_at IS NULL" json:"-"` // HMAC of the claim code, see usecase.ClaimCodes
+	CodeExpiresAt    *time.Time     `json:"code_expires_at,omitempty"`
+	IssuedCode       string         `gorm:"-" json:"-"` // Plain code, only set when issued
+	IsClaimed        bool           `gorm:"default:false" json:"is_claimed"`
+	ClaimedAt        *time.Time     `json:"claimed_at,omitempty"`
+}
+
+// AlumniCount is one row of an alumni aggregate, e.g. an employer and how
+// many graduates work there.
+type AlumniCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// AlumniBatchStats is where the graduates of one batch work.
+type AlumniBatchStats struct {
+	Batch     string        `json:"batch"`
+	Total     int64         `json:"total"`
+	Employed  int64         `json:"employed"` // With an organization on record
+	Employers []AlumniCount `json:"employers"`
+	Locations []AlumniCount `json:"locations"`
+}
+
+// AlumniStats aggregates the alumni directory. Organizations and locations
+// are grouped case-insensitively, ignoring surrounding spaces.
+type AlumniStats struct {
+	Total        int64              `json:"total"`
+	Employed     int64              `json:"employed"`
+	TopEmployers []AlumniCount      `json:"top_employers"`
+	TopLocations []AlumniCount      `json:"top_locations"`
+	Batches      []AlumniBatchStats `json:"batches"`
+}
+
+// AlumniRepository adds directory aggregates to the CRUD repository.
+type AlumniRepository interface {
+	Repository[Alumni]
+	// Stats aggregates the alumni matching filter (the list filters),
+	// keeping the top entries of each ranking.
+	Stats(ctx context.Context, filter map[string]interface{}, top int) (*AlumniStats, error)
+}
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Alumni) ExportColumns() []string {
+	return []string{
+		"id", "full_name", "student_id", "batch", "passing_year", "current_status",
+		"organization", "designation", "location", "university_id",
+		"department_id", "is_claimed", "created_at", "updated_at",
+	}
 }
@@ -0,0 +1,69 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// AttendanceSession is one day's attendance of a course offering, with a
+// record per student marked. Batch, university and department are copied
+// from the offering.
+type AttendanceSession struct {
+	Base
+	OfferingID   uuid.UUID          `gorm:"type:uuid;not null;index;uniqueIndex:idx_attendance_session,where:deleted_at IS NULL" json:"offering_id"`
+	Offering     *CourseOffering    `json:"offering,omitempty"`
+	BatchID      uuid.UUID          `gorm:"type:uuid;not null;index" json:"batch_id"`
+	UniversityID uuid.UUID          `gorm:"type:uuid;not null;index" json:"university_id"`
+	DepartmentID uuid.UUID          `gorm:"type:uuid;not null;index" json:"department_id"`
+	Date         string             `gorm:"size:10;not null;uniqueIndex:idx_attendance_session,where:deleted_at IS NULL" json:"date"` // YYYY-MM-DD
+	Note         string             `gorm:"type:text" json:"note"`
+	Records      []AttendanceRecord `gorm:"foreignKey:SessionID" json:"records,omitempty"`
+}
+
+// AttendanceRecord is one student's status in an attendance session.
+type AttendanceRecord struct {
+	Base
+	SessionID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_attendance_record" json:"session_id"`
+	StudentID uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_attendance_record" json:"student_id"`
+	Student   *Student  `json:"student,omitempty"`
+	Status    string    `gorm:"size:10;not null" json:"status"` // One of AttendanceStatuses
+}
+
+// Attendance statuses. Late students count as attended.
+const (
+	AttendancePresent = "present"
+	AttendanceAbsent  = "absent"
+	AttendanceLate    = "late"
+)
+
+// AttendanceStatuses lists the valid AttendanceRecord.Status values.
+var AttendanceStatuses = []string{AttendancePresent, AttendanceAbsent, AttendanceLate}
+
+// AttendanceTally counts a student's statuses in the sessions of a course
+// offering.
+type AttendanceTally struct {
+	OfferingID uuid.UUID `json:"offering_id"`
+	StudentID  uuid.UUID `json:"student_id"`
+	Present    int       `json:"present"`
+	Late       int       `json:"late"`
+	Absent     int       `json:"absent"`
+}
+
+// AttendanceRepository adds the roll and tally queries to the CRUD
+// repository.
+type AttendanceRepository interface {
+	Repository[AttendanceSession]
+	// Find returns the offering's session on date with its records and
+	// their students, or gorm.ErrRecordNotFound.
+	Find(ctx context.Context, offeringID uuid.UUID, date string) (*AttendanceSession, error)
+	// Sessions lists the offering's sessions, latest first, without
+	// records.
+	Sessions(ctx context.Context, offeringID uuid.UUID, limit, offset int) ([]AttendanceSession, int64, error)
+	// SaveRecords marks students in their sessions, replacing the status
+	// of students already marked.
+	SaveRecords(ctx context.Context, records []AttendanceRecord) error
+	// Tallies counts statuses per offering and student over the sessions
+	// of offeringID, or the records of studentID, when set.
+	Tallies(ctx context.Context, offeringID, studentID *uuid.UUID) ([]AttendanceTally, error)
+}
@@ -1,19 +1,37 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+const (
+	AuditActionCreate  = "CREATE"
+	AuditActionUpdate  = "UPDATE"
+	AuditActionDelete  = "DELETE"
+	AuditActionRestore = "RESTORE"
+	// AuditActionIssueCode records a new profile claim code being issued.
+	AuditActionIssueCode = "ISSUE_CODE"
 )
 
 type AuditLog struct {
 	Base
-	UserID      uuid.UUID `gorm:"type:uuid;index" json:"user_id"`
-	Action      string    `gorm:"size:50" json:"action"`      // e.g. "CREATE", "UPDATE", "DELETE", "LOGIN"
-	EntityName  string    `gorm:"size:50" json:"entity_name"` // e.g. "Student", "Book"
-	EntityID    uuid.UUID `gorm:"type:uuid" json:"entity_id"`
-	Description string    `gorm:"type:text" json:"description"`
-	IPAddress   string    `gorm:"size:45" json:"ip_address"`
-	UserAgent   string    `gorm:"type:text" json:"user_agent"`
-	Timestamp   time.Time `json:"timestamp"`
+	UserID      uuid.UUID       `gorm:"type:uuid;index" json:"user_id"`
+	Action      string          `gorm:"size:50;index" json:"action"`      // e.g. "CREATE", "UPDATE", "DELETE", "LOGIN"
+	EntityName  string          `gorm:"size:50;index" json:"entity_name"` // e.g. "Student", "Book"
+	EntityID    uuid.UUID       `gorm:"type:uuid;index" json:"entity_id"`
+	Description string          `gorm:"type:text" json:"description"`
+	Changes     *datatypes.JSON `gorm:"type:jsonb" json:"changes,omitempty"` // {"field": {"old": ..., "new": ...}}
+	IPAddress   string          `gorm:"size:45" json:"ip_address"`
+	UserAgent   string          `gorm:"type:text" json:"user_agent"`
+	Timestamp   time.Time       `gorm:"index" json:"timestamp"`
+}
+
+// AuditLogRepository stores audit entries; writes are batched by the caller.
+type AuditLogRepository interface {
+	Repository[AuditLog]
+	CreateBatch(ctx context.Context, logs []AuditLog) error
 }
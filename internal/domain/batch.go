@@ -1,17 +1,71 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 // Batch represents a specific batch of students (e.g., "CSE - 25th Batch").
 type Batch struct {
 	Base
-	Name         string      `gorm:"size:100;not null" json:"name"`       // e.g. "Batch 10"
-	Slug         string      `gorm:"size:100;not null;index" json:"slug"` // e.g. "batch-10"
-	IsStudying   bool        `gorm:"default:true" json:"is_studying"`     // "study" field in firestore
-	DepartmentID uuid.UUID   `gorm:"type:uuid;not null;index" json:"department_id"`
+	Name         string      `gorm:"size:100;not null" json:"name"`                                                                               // e.g. "Batch 10"
+	Slug         string      `gorm:"size:100;not null;index;uniqueIndex:idx_batches_scoped_slug,priority:2,where:deleted_at IS NULL" json:"slug"` // e.g. "batch-10"
+	IsStudying   bool        `gorm:"default:true" json:"is_studying"`                                                                             // "study" field in firestore
+	DepartmentID uuid.UUID   `gorm:"type:uuid;not null;index;uniqueIndex:idx_batches_scoped_slug,priority:1" json:"department_id"`
 	Department   *Department `json:"department,omitempty"`
 	UniversityID uuid.UUID   `gorm:"type:uuid;not null;index" json:"university_id"`
 	Sessions     []Session   `gorm:"many2many:batch_sessions;" json:"sessions,omitempty"`
 	Semesters    []Semester  `gorm:"many2many:semester_batches;" json:"semesters,omitempty"`
 	Students     []Student   `gorm:"foreignKey:BatchID" json:"students,omitempty"`
 }
+
+// SlugScope: slugs are unique within a department (see Slugged).
+func (Batch) SlugScope() string {
+	return "department_id"
+}
+
+func (b Batch) GetSlug() string {
+	return b.Slug
+}
+
+func (b *Batch) setSlug(slug string) {
+	b.Slug = slug
+}
+
+func (b Batch) slugSource() string {
+	return b.Name
+}
+
+func (b Batch) slugScopeID() uuid.UUID {
+	return b.DepartmentID
+}
+
+// BeforeCreate assigns the ID and a slug that is unique within its scope.
+func (b *Batch) BeforeCreate(tx *gorm.DB) error {
+	if err := b.Base.BeforeCreate(tx); err != nil {
+		return err
+	}
+	return assignSlug(tx, b)
+}
+
+// BeforeUpdate re-slugs renamed records and keeps the old slug as an alias.
+func (b *Batch) BeforeUpdate(tx *gorm.DB) error {
+	return trackSlug(tx, b)
+}
+
+// BatchRepository adds semester link management to the CRUD repository.
+type BatchRepository interface {
+	Repository[Batch]
+	// SetSemesters replaces the semesters linked to a batch.
+	SetSemesters(ctx context.Context, batchID uuid.UUID, semesterIDs []uuid.UUID) error
+}
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Batch) ExportColumns() []string {
+	return []string{
+		"id", "name", "slug", "is_studying", "university_id", "department_id",
+		"created_at", "updated_at",
+	}
+}
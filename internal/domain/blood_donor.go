@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BloodGroups are the accepted values of BloodDonor.BloodGroup.
+var BloodGroups = []string{"A+", "A-", "B+", "B-", "AB+", "AB-", "O+", "O-"}
+
+// NormalizeBloodGroup returns the canonical form of a blood group ("ab+"
+// becomes "AB+"), or "" when it is not one. A trailing space is read as
+// "+", which is what an unescaped "A+" in a query string decodes to.
+func NormalizeBloodGroup(s string) string {
+	s = strings.ToUpper(strings.TrimLeft(s, " "))
+	if strings.HasSuffix(s, " ") {
+		s = strings.TrimRight(s, " ") + "+"
+	}
+	for _, g := range BloodGroups {
+		if s == g {
+			return g
+		}
+	}
+	return ""
+}
+
+// BloodDonor is a student who opted in to be asked for blood. Donors are
+// listed without contact details; a requester sees a donor's phone number
+// only after the donor accepts their BloodRequest.
+type BloodDonor struct {
+	Base
+	StudentID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"student_id"`
+	UserID        uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex" json:"-"`
+	UniversityID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"university_id"`
+	DepartmentID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"department_id"`
+	Name          string     `gorm:"size:100" json:"name"`
+	BloodGroup    string     `gorm:"size:5;not null;index" json:"blood_group"`
+	LastDonatedAt *time.Time `json:"last_donated_at,omitempty"`
+	IsAvailable   bool       `gorm:"not null;default:false" json:"is_available"`
+	// End of the cooldown after LastDonatedAt, only set while it lasts
+	EligibleAt *time.Time `gorm:"-" json:"eligible_at,omitempty"`
+}
+
+// BloodRequest asks the eligible donors of a blood group for help. Every
+// donor notified gets a BloodRequestMatch to accept or decline.
+type BloodRequest struct {
+	Base
+	RequesterID  uuid.UUID           `gorm:"type:uuid;not null;index" json:"requester_id"`
+	BloodGroup   string              `gorm:"size:5;not null" json:"blood_group"`
+	UniversityID *uuid.UUID          `gorm:"type:uuid" json:"university_id,omitempty"`
+	Hospital     string              `gorm:"size:255" json:"hospital"`
+	Message      string              `gorm:"type:text" json:"message"`
+	NeededBy     *time.Time          `json:"needed_by,omitempty"`
+	Matches      []BloodRequestMatch `gorm:"foreignKey:RequestID;constraint:OnDelete:CASCADE;" json:"matches,omitempty"`
+}
+
+// BloodRequestMatch is one donor asked by a BloodRequest. Name and Phone
+// are filled in for the requester once the donor accepts; the phone number
+// is read from the donor's student profile and never stored here.
+type BloodRequestMatch struct {
+	Base
+	RequestID   uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_blood_request_donor" json:"request_id"`
+	DonorID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_blood_request_donor;index" json:"donor_id"`
+	Status      string     `gorm:"size:20;not null" json:"status"`
+	RespondedAt *time.Time `json:"responded_at,omitempty"`
+	Name        string     `gorm:"-" json:"name,omitempty"`
+	Phone       string     `gorm:"-" json:"phone,omitempty"`
+}
+
+// BloodRequestMatch statuses.
+const (
+	BloodMatchPending  = "pending"
+	BloodMatchAccepted = "accepted"
+	BloodMatchDeclined = "declined"
+)
+
+// BloodDonorRepository adds the donor search to the CRUD repository.
+// Delete removes the donor for good, together with their matches, so
+// nothing about an opted-out donor's blood group is kept.
+type BloodDonorRepository interface {
+	Repository[BloodDonor]
+	// Eligible lists available donors matching filter (blood_group,
+	// university_id, department_id) who never donated or last donated
+	// before cutoff, longest rested first.
+	Eligible(ctx context.Context, filter map[string]interface{}, cutoff time.Time, limit, offset int) ([]BloodDonor, int64, error)
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "testing"
+
+func TestNormalizeBloodGroup(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"A+", "A+"},
+		{"ab-", "AB-"},
+		{" o+", "O+"},
+		{"B ", "B+"}, // "B+" in a query string without escaping
+		{"AB ", "AB+"},
+		{"O", ""},
+		{"A++", ""},
+		{"C+", ""},
+		{"A +", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := NormalizeBloodGroup(tt.in); got != tt.want {
+			t.Errorf("NormalizeBloodGroup(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
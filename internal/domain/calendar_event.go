@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CalendarEvent is an entry in the academic calendar of a university, or
+// of one of its departments when DepartmentID is set: a holiday, the start
+// or end of a semester, a registration deadline and so on. Dates are
+// YYYY-MM-DD in the campus time zone and EndDate is inclusive; events that
+// are not AllDay also have "15:04" StartTime and EndTime on those dates. A
+// recurring event repeats its dates every week, month or year, starting no
+// later than RepeatUntil (forever when empty).
+type CalendarEvent struct {
+	Base
+	UniversityID uuid.UUID  `gorm:"type:uuid;not null;index" json:"university_id"`
+	DepartmentID *uuid.UUID `gorm:"type:uuid;index" json:"department_id,omitempty"`
+	Title        string     `gorm:"size:255;not null" json:"title"`
+	Description  string     `gorm:"type:text" json:"description"`
+	Category     string     `gorm:"size:20;not null;index" json:"category"` // One of CalendarCategories
+	Location     string     `gorm:"size:255" json:"location"`
+	StartDate    string     `gorm:"size:10;not null;index" json:"start_date"`
+	EndDate      string     `gorm:"size:10;not null" json:"end_date"`
+	AllDay       bool       `gorm:"not null" json:"all_day"`
+	StartTime    string     `gorm:"size:5" json:"start_time,omitempty"`
+	EndTime      string     `gorm:"size:5" json:"end_time,omitempty"`
+	Recurrence   string     `gorm:"size:10" json:"recurrence,omitempty"` // One of CalendarRecurrences, or empty
+	RepeatUntil  string     `gorm:"size:10" json:"repeat_until,omitempty"`
+}
+
+// Calendar event categories. All-day holidays are the days off that
+// AcademicCalendar.IsWorkingDay reports.
+const (
+	CalendarCategoryHoliday      = "holiday"
+	CalendarCategorySemester     = "semester"
+	CalendarCategoryRegistration = "registration"
+	CalendarCategoryExam         = "exam"
+	CalendarCategoryEvent        = "event"
+)
+
+// CalendarCategories lists the valid CalendarEvent.Category values.
+var CalendarCategories = []string{
+	CalendarCategoryHoliday,
+	CalendarCategorySemester,
+	CalendarCategoryRegistration,
+	CalendarCategoryExam,
+	CalendarCategoryEvent,
+}
+
+// Calendar event recurrences.
+const (
+	RecurrenceWeekly  = "weekly"
+	RecurrenceMonthly = "monthly"
+	RecurrenceYearly  = "yearly"
+)
+
+// CalendarRecurrences lists the valid CalendarEvent.Recurrence values
+// besides empty, which means the event does not repeat.
+var CalendarRecurrences = []string{RecurrenceWeekly, RecurrenceMonthly, RecurrenceYearly}
+
+// CalendarEventRepository adds the calendar range query to the CRUD
+// repository.
+type CalendarEventRepository interface {
+	Repository[CalendarEvent]
+	// Range returns the university-wide events of universityID, and those
+	// of departmentID when it is set, that may fall on a date from from to
+	// until (YYYY-MM-DD, inclusive). Recurring events are returned once.
+	Range(ctx context.Context, universityID uuid.UUID, departmentID *uuid.UUID, from, until string) ([]CalendarEvent, error)
+}
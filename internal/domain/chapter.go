@@ -14,3 +14,8 @@ type Chapter struct {
 
 	Batches []Batch `gorm:"many2many:chapter_batches;save_associations:false" json:"batches,omitempty"`
 }
+
+// KeepRevisions opts Chapter in to revision history (see Versioned).
+func (Chapter) KeepRevisions() bool {
+	return true
+}
@@ -0,0 +1,169 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrAlreadyClaimed is returned when a profile was claimed, or its code
+// replaced, between looking it up and claiming it.
+var ErrAlreadyClaimed = errors.New("profile has already been claimed")
+
+// ErrClaimCodeTaken is returned by repositories when a write gives a profile
+// the claim code another unclaimed profile holds.
+var ErrClaimCodeTaken = errors.New("claim code is already in use")
+
+// Claimable marks roster profiles created ahead of sign-up. Each carries a
+// verification code its owner uses to link the profile to their account.
+// Only a hash of the code is stored; the plain code is returned once, when
+// it is issued.
+type Claimable interface {
+	Entity
+	// ProfileType names the profile in ProfileClaimed events, e.g. "teacher".
+	ProfileType() string
+	// ProfileRole is the user role that goes with the profile, or "" when
+	// claiming it leaves the role alone (alumni).
+	ProfileRole() Role
+	ClaimScope() (universityID, departmentID uuid.UUID)
+
+	// IssuedClaimCode is the plain code just issued. It is never stored or
+	// serialized, only added to the response of the request that issued it.
+	IssuedClaimCode() string
+	SetIssuedClaimCode(code string)
+	ClaimCode() (hash string, expiresAt *time.Time)
+	SetClaimCode(hash string, expiresAt *time.Time)
+
+	Claimed() bool
+	// Claim links the profile to userID and retires its code.
+	Claim(userID uuid.UUID, at time.Time)
+	// ClaimState and SetClaimState read and restore the account link, so
+	// updates can keep it out of the hands of request bodies.
+	ClaimState() ClaimState
+	SetClaimState(state ClaimState)
+}
+
+// ClaimState is the account a profile is linked to, and since when.
+type ClaimState struct {
+	UserID    *uuid.UUID
+	IsClaimed bool
+	ClaimedAt *time.Time
+}
+
+// ClaimRepository looks up and claims profiles of type T by code hash.
+type ClaimRepository[T any] interface {
+	// FindByClaimCode returns the unclaimed profile whose code hashes to
+	// hash and has not expired at now.
+	FindByClaimCode(ctx context.Context, hash string, now time.Time) (*T, error)
+	// Claim links profile id to userID if it still holds the code hash
+	// unclaimed, and returns ErrAlreadyClaimed otherwise.
+	Claim(ctx context.Context, id uuid.UUID, hash string, userID uuid.UUID, now time.Time) error
+}
+
+func (*Student) ProfileType() string                  { return "student" }
+func (*Student) ProfileRole() Role                    { return RoleStudent }
+func (s *Student) ClaimScope() (uuid.UUID, uuid.UUID) { return s.UniversityID, s.DepartmentID }
+func (s *Student) IssuedClaimCode() string            { return s.IssuedCode }
+func (s *Student) SetIssuedClaimCode(code string)     { s.IssuedCode = code }
+func (s *Student) ClaimCode() (string, *time.Time)    { return s.VerificationCode, s.CodeExpiresAt }
+func (s *Student) SetClaimCode(hash string, exp *time.Time) {
+	s.VerificationCode, s.CodeExpiresAt = hash, exp
+}
+func (s *Student) Claimed() bool { return s.IsClaimed }
+func (s *Student) ClaimState() ClaimState {
+	return ClaimState{UserID: s.UserID, IsClaimed: s.IsClaimed, ClaimedAt: s.ClaimedAt}
+}
+func (s *Student) SetClaimState(c ClaimState) {
+	s.UserID, s.IsClaimed, s.ClaimedAt = c.UserID, c.IsClaimed, c.ClaimedAt
+}
+
+func (s *Student) Claim(userID uuid.UUID, at time.Time) {
+	s.UserID = &userID
+	s.IsClaimed = true
+	s.ClaimedAt = &at
+	s.VerificationCode = ""
+	s.CodeExpiresAt = nil
+}
+
+func (*Teacher) ProfileType() string                  { return "teacher" }
+func (*Teacher) ProfileRole() Role                    { return RoleTeacher }
+func (t *Teacher) ClaimScope() (uuid.UUID, uuid.UUID) { return t.UniversityID, t.DepartmentID }
+func (t *Teacher) IssuedClaimCode() string            { return t.IssuedCode }
+func (t *Teacher) SetIssuedClaimCode(code string)     { t.IssuedCode = code }
+func (t *Teacher) ClaimCode() (string, *time.Time)    { return t.VerificationCode, t.CodeExpiresAt }
+func (t *Teacher) SetClaimCode(hash string, exp *time.Time) {
+	t.VerificationCode, t.CodeExpiresAt = hash, exp
+}
+func (t *Teacher) Claimed() bool { return t.IsClaimed }
+func (t *Teacher) ClaimState() ClaimState {
+	return ClaimState{UserID: t.UserID, IsClaimed: t.IsClaimed, ClaimedAt: t.ClaimedAt}
+}
+func (t *Teacher) SetClaimState(c ClaimState) {
+	t.UserID, t.IsClaimed, t.ClaimedAt = c.UserID, c.IsClaimed, c.ClaimedAt
+}
+
+func (t *Teacher) Claim(userID uuid.UUID, at time.Time) {
+	t.UserID = &userID
+	t.IsClaimed = true
+	t.ClaimedAt = &at
+	t.VerificationCode = ""
+	t.CodeExpiresAt = nil
+}
+
+func (*Staff) ProfileType() string                  { return "staff" }
+func (*Staff) ProfileRole() Role                    { return RoleStaff }
+func (s *Staff) ClaimScope() (uuid.UUID, uuid.UUID) { return s.UniversityID, s.DepartmentID }
+func (s *Staff) IssuedClaimCode() string            { return s.IssuedCode }
+func (s *Staff) SetIssuedClaimCode(code string)     { s.IssuedCode = code }
+func (s *Staff) ClaimCode() (string, *time.Time)    { return s.VerificationCode, s.CodeExpiresAt }
+func (s *Staff) SetClaimCode(hash string, exp *time.Time) {
+	s.VerificationCode, s.CodeExpiresAt = hash, exp
+}
+func (s *Staff) Claimed() bool { return s.IsClaimed }
+func (s *Staff) ClaimState() ClaimState {
+	return ClaimState{UserID: s.UserID, IsClaimed: s.IsClaimed, ClaimedAt: s.ClaimedAt}
+}
+func (s *Staff) SetClaimState(c ClaimState) {
+	s.UserID, s.IsClaimed, s.ClaimedAt = c.UserID, c.IsClaimed, c.ClaimedAt
+}
+
+func (s *Staff) Claim(userID uuid.UUID, at time.Time) {
+	s.UserID = &userID
+	s.IsClaimed = true
+	s.ClaimedAt = &at
+	s.VerificationCode = ""
+	s.CodeExpiresAt = nil
+}
+
+func (*Alumni) ProfileType() string                  { return "alumni" }
+func (*Alumni) ProfileRole() Role                    { return "" }
+func (a *Alumni) ClaimScope() (uuid.UUID, uuid.UUID) { return a.UniversityID, a.DepartmentID }
+func (a *Alumni) IssuedClaimCode() string            { return a.IssuedCode }
+func (a *Alumni) SetIssuedClaimCode(code string)     { a.IssuedCode = code }
+func (a *Alumni) ClaimCode() (string, *time.Time)    { return a.VerificationCode, a.CodeExpiresAt }
+func (a *Alumni) SetClaimCode(hash string, exp *time.Time) {
+	a.VerificationCode, a.CodeExpiresAt = hash, exp
+}
+func (a *Alumni) Claimed() bool { return a.IsClaimed }
+func (a *Alumni) ClaimState() ClaimState {
+	return ClaimState{UserID: a.UserID, IsClaimed: a.IsClaimed, ClaimedAt: a.ClaimedAt}
+}
+func (a *Alumni) SetClaimState(c ClaimState) {
+	a.UserID, a.IsClaimed, a.ClaimedAt = c.UserID, c.IsClaimed, c.ClaimedAt
+}
+
+func (a *Alumni) Claim(userID uuid.UUID, at time.Time) {
+	a.UserID = &userID
+	a.IsClaimed = true
+	a.ClaimedAt = &at
+	a.VerificationCode = ""
+	a.CodeExpiresAt = nil
+}
+
+// IsProfileRole reports whether r may be replaced by a claim. Admin accounts
+// keep their role when they claim a profile.
+func (r Role) IsProfileRole() bool {
+	return r == RoleStudent || r == RoleTeacher || r == RoleStaff || r == RoleCR
+}
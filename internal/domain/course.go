@@ -31,3 +31,17 @@ type Course struct {
 
 	Batches []Batch `gorm:"many2many:course_batches;save_associations:false" json:"batches,omitempty"`
 }
+
+// KeepRevisions opts Course in to revision history (see Versioned).
+func (Course) KeepRevisions() bool {
+	return true
+}
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Course) ExportColumns() []string {
+	return []string{
+		"id", "course_code", "course_title", "university_id", "department_id",
+		"semester_id", "course_category_id", "total_credits", "total_marks",
+		"created_at", "updated_at",
+	}
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CourseOffering is a course as taught to one batch in one semester (and
+// optionally one session and section), with the teachers assigned to it.
+// University and department are copied from the batch.
+type CourseOffering struct {
+	Base
+	CourseID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_course_offering,where:deleted_at IS NULL" json:"course_id"`
+	Course       *Course    `json:"course,omitempty"`
+	BatchID      uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_course_offering,where:deleted_at IS NULL" json:"batch_id"`
+	Batch        *Batch     `json:"batch,omitempty"`
+	SemesterID   uuid.UUID  `gorm:"type:uuid;not null;index;uniqueIndex:idx_course_offering,where:deleted_at IS NULL" json:"semester_id"`
+	Semester     *Semester  `json:"semester,omitempty"`
+	SessionID    *uuid.UUID `gorm:"type:uuid;index" json:"session_id,omitempty"`
+	Session      *Session   `json:"session,omitempty"`
+	Section      string     `gorm:"size:20;not null;default:'';uniqueIndex:idx_course_offering,where:deleted_at IS NULL" json:"section"` // e.g. "A"; empty for the whole batch
+	UniversityID uuid.UUID  `gorm:"type:uuid;not null;index" json:"university_id"`
+	DepartmentID uuid.UUID  `gorm:"type:uuid;not null;index" json:"department_id"`
+	Teachers     []Teacher  `gorm:"many2many:course_offering_teachers;save_associations:false" json:"teachers,omitempty"`
+	// TeacherIDs assigns teachers when the offering is created
+	TeacherIDs []uuid.UUID `gorm:"-" json:"teacher_ids,omitempty"`
+}
+
+// CourseOfferingRepository adds teacher assignment to the CRUD repository.
+// Its GetAll also accepts a teacher_id filter.
+type CourseOfferingRepository interface {
+	Repository[CourseOffering]
+	// SetTeachers replaces the teachers assigned to an offering.
+	SetTeachers(ctx context.Context, offeringID uuid.UUID, teacherIDs []uuid.UUID) error
+}
@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"context"
 	"time"
 
 	"github.com/google/uuid"
@@ -29,4 +30,31 @@ type CR struct {
 	Fb        string     `gorm:"size:255" json:"fb"`
 	ImageURL  string     `gorm:"size:500" json:"image_url"`
 	IsCurrent bool       `gorm:"default:false" json:"is_current"`
+	// Why a past term ended: CREndExpired, CREndHandover, CREndRemoved or
+	// CREndGraduated
+	EndReason string `gorm:"size:20" json:"end_reason,omitempty"`
+}
+
+// Reasons a CR term ends.
+const (
+	CREndExpired   = "expired"   // TermEnd passed
+	CREndHandover  = "handover"  // Replaced by a new appointment
+	CREndRemoved   = "removed"   // Ended early or deleted by an admin
+	CREndGraduated = "graduated" // The batch graduated
+)
+
+// CRRepository adds the queries of the CR lifecycle to the CRUD repository.
+type CRRepository interface {
+	Repository[CR]
+	// LockBatch locks the batch row for the rest of the transaction in ctx,
+	// so appointments and expiries in one batch run one at a time.
+	LockBatch(ctx context.Context, batchID uuid.UUID) error
+	// Current returns the current CRs of a batch.
+	Current(ctx context.Context, batchID uuid.UUID) ([]CR, error)
+	// History returns every term of a batch, current and past, newest first.
+	History(ctx context.Context, batchID uuid.UUID) ([]CR, error)
+	// DueExpiry returns current CRs whose TermEnd is before now.
+	DueExpiry(ctx context.Context, now time.Time) ([]CR, error)
+	// IsCurrent reports whether the student holds a current CR term.
+	IsCurrent(ctx context.Context, studentID uuid.UUID) (bool, error)
 }
@@ -3,20 +3,67 @@ package domain
 import (
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 // Department represents a department within a university.
 type Department struct {
 	Base
 	Name            string          `gorm:"size:255;not null" json:"name"`
-	Acronym         string          `gorm:"size:20;index" json:"acronym"`        // e.g., CSE, EEE
-	Slug            string          `gorm:"size:255;not null;index" json:"slug"` // e.g., psychology, cse
+	Acronym         string          `gorm:"size:20;index" json:"acronym"`                                                                                    // e.g., CSE, EEE
+	Slug            string          `gorm:"size:255;not null;index;uniqueIndex:idx_departments_scoped_slug,priority:2,where:deleted_at IS NULL" json:"slug"` // e.g., psychology, cse
 	EstablishedYear int             `json:"established_year"`
 	About           string          `gorm:"type:text" json:"about"`
 	WebsiteURL      string          `gorm:"size:255" json:"website_url"`
 	LogoURL         string          `json:"logo_url"`
 	Gallery         *datatypes.JSON `gorm:"type:jsonb" json:"gallery,omitempty"`
-	UniversityID    uuid.UUID       `gorm:"type:uuid;not null;index" json:"university_id"`
+	UniversityID    uuid.UUID       `gorm:"type:uuid;not null;index;uniqueIndex:idx_departments_scoped_slug,priority:1" json:"university_id"`
 	University      *University     `gorm:"foreignKey:UniversityID" json:"university,omitempty"`
 	Batches         []Batch         `gorm:"foreignKey:DepartmentID" json:"batches,omitempty"`
 }
+
+// SlugScope: slugs are unique within a university (see Slugged).
+func (Department) SlugScope() string {
+	return "university_id"
+}
+
+func (d Department) GetSlug() string {
+	return d.Slug
+}
+
+func (d *Department) setSlug(slug string) {
+	d.Slug = slug
+}
+
+// Departments are usually known by acronym (cse, eee), so it wins over Name.
+func (d Department) slugSource() string {
+	if d.Acronym != "" {
+		return d.Acronym
+	}
+	return d.Name
+}
+
+func (d Department) slugScopeID() uuid.UUID {
+	return d.UniversityID
+}
+
+// BeforeCreate assigns the ID and a slug that is unique within its scope.
+func (d *Department) BeforeCreate(tx *gorm.DB) error {
+	if err := d.Base.BeforeCreate(tx); err != nil {
+		return err
+	}
+	return assignSlug(tx, d)
+}
+
+// BeforeUpdate re-slugs renamed records and keeps the old slug as an alias.
+func (d *Department) BeforeUpdate(tx *gorm.DB) error {
+	return trackSlug(tx, d)
+}
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Department) ExportColumns() []string {
+	return []string{
+		"id", "name", "acronym", "slug", "established_year", "website_url",
+		"university_id", "created_at", "updated_at",
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// DepartmentOverviewRepository runs the narrow queries behind the
+// department home screen. Each method is independent so they can run in
+// parallel.
+type DepartmentOverviewRepository interface {
+	// Department returns the department with its university.
+	Department(ctx context.Context, id uuid.UUID) (*Department, error)
+	// ActiveBatches returns batches still studying, by name.
+	ActiveBatches(ctx context.Context, departmentID uuid.UUID) ([]Batch, error)
+	// StudentCounts returns the number of students per batch.
+	StudentCounts(ctx context.Context, batchIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	// Teachers returns present teachers and the chairman, by Weight.
+	Teachers(ctx context.Context, departmentID uuid.UUID) ([]Teacher, error)
+	// Staff returns staff by Serial.
+	Staff(ctx context.Context, departmentID uuid.UUID) ([]Staff, error)
+	// CurrentCRs returns CRs marked current, by batch.
+	CurrentCRs(ctx context.Context, departmentID uuid.UUID) ([]CR, error)
+	CountCourses(ctx context.Context, departmentID uuid.UUID) (int64, error)
+	// CountResources counts published resources.
+	CountResources(ctx context.Context, departmentID uuid.UUID) (int64, error)
+}
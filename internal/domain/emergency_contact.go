@@ -23,3 +23,12 @@ type EmergencyContact struct {
 func (EmergencyContact) TableName() string {
 	return "contacts"
 }
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (EmergencyContact) ExportColumns() []string {
+	return []string{
+		"id", "title", "designation", "category", "scope", "phone", "email",
+		"university_id", "department_id", "is_verified", "created_at",
+		"updated_at",
+	}
+}
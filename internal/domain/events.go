@@ -0,0 +1,174 @@
+package domain
+
+import (
+	"reflect"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// Domain events. They are published on the in-process bus (pkg/eventbus),
+// where subscribers read the acting user with ActorFromContext, and stored
+// in the transactional outbox for reliable dispatch. The json tags define the
+// outbox payload and the "data" object of outgoing webhooks.
+
+// Event is anything with a stable dotted name. It matches eventbus.Event.
+type Event interface {
+	EventName() string
+}
+
+// EntityCreated is published by the generic usecase after an insert.
+type EntityCreated[T any] struct {
+	Entity *T `json:"entity"`
+}
+
+func (EntityCreated[T]) EventName() string { return entityEventName[T]("created") }
+
+// EntityUpdated is published after an update. Before is the previous state
+// when the usecase loaded it (auditing enabled), otherwise nil.
+type EntityUpdated[T any] struct {
+	Entity *T `json:"entity"`
+	Before *T `json:"before,omitempty"`
+}
+
+func (EntityUpdated[T]) EventName() string { return entityEventName[T]("updated") }
+
+// EntityDeleted is published after a (soft) delete. Entity is the last
+// known state when it was loaded, otherwise nil.
+type EntityDeleted[T any] struct {
+	ID     uuid.UUID `json:"id"`
+	Entity *T        `json:"entity,omitempty"`
+}
+
+func (EntityDeleted[T]) EventName() string { return entityEventName[T]("deleted") }
+
+// ResourceApproved is published when a moderator publishes a submission.
+type ResourceApproved struct {
+	Resource *Resource `json:"resource"`
+}
+
+func (ResourceApproved) EventName() string { return "resource.approved" }
+
+// ResourceRejected is published when a moderator sends a submission back.
+type ResourceRejected struct {
+	Resource *Resource `json:"resource"`
+	Reason   string    `json:"reason"`
+}
+
+func (ResourceRejected) EventName() string { return "resource.rejected" }
+
+// ProfileClaimed is published when a user links themselves to a pre-created
+// roster profile with its verification code.
+type ProfileClaimed struct {
+	ProfileType  string    `json:"profile_type"` // "student"
+	ProfileID    uuid.UUID `json:"profile_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	UniversityID uuid.UUID `json:"university_id"`
+	DepartmentID uuid.UUID `json:"department_id"`
+}
+
+func (ProfileClaimed) EventName() string { return "profile.claimed" }
+
+// CRAppointed is published when a student starts a CR term. Replaces is
+// the term it took over on a handover.
+type CRAppointed struct {
+	CR       *CR        `json:"cr"`
+	Replaces *uuid.UUID `json:"replaces,omitempty"`
+}
+
+func (CRAppointed) EventName() string { return "cr.appointed" }
+
+// CRTermEnded is published when a CR term ends; CR.EndReason says why.
+type CRTermEnded struct {
+	CR *CR `json:"cr"`
+}
+
+func (CRTermEnded) EventName() string { return "cr.term_ended" }
+
+// BatchPromoted is published when a batch moves on to its next semester.
+// FromSemesterID is nil for a batch that had no semester yet.
+type BatchPromoted struct {
+	BatchID        uuid.UUID  `json:"batch_id"`
+	FromSemesterID *uuid.UUID `json:"from_semester_id,omitempty"`
+	ToSemesterID   uuid.UUID  `json:"to_semester_id"`
+	UniversityID   uuid.UUID  `json:"university_id"`
+	DepartmentID   uuid.UUID  `json:"department_id"`
+}
+
+func (BatchPromoted) EventName() string { return "batch.promoted" }
+
+// BatchGraduated is published when a batch stops studying and its students
+// are added to the alumni directory.
+type BatchGraduated struct {
+	BatchID       uuid.UUID `json:"batch_id"`
+	PassingYear   string    `json:"passing_year"`
+	AlumniCreated int       `json:"alumni_created"`
+	UniversityID  uuid.UUID `json:"university_id"`
+	DepartmentID  uuid.UUID `json:"department_id"`
+}
+
+func (BatchGraduated) EventName() string { return "batch.graduated" }
+
+// AppointmentRequested is published when a student books an office-hour
+// slot.
+type AppointmentRequested struct {
+	Appointment *Appointment `json:"appointment"`
+}
+
+func (AppointmentRequested) EventName() string { return "appointment.requested" }
+
+// AppointmentApproved is published when the teacher accepts a booking.
+type AppointmentApproved struct {
+	Appointment *Appointment `json:"appointment"`
+}
+
+func (AppointmentApproved) EventName() string { return "appointment.approved" }
+
+// AppointmentDeclined is published when the teacher turns a booking down.
+type AppointmentDeclined struct {
+	Appointment *Appointment `json:"appointment"`
+}
+
+func (AppointmentDeclined) EventName() string { return "appointment.declined" }
+
+// AppointmentCancelled is published when the student cancels a booking.
+type AppointmentCancelled struct {
+	Appointment *Appointment `json:"appointment"`
+}
+
+func (AppointmentCancelled) EventName() string { return "appointment.cancelled" }
+
+// CourseTeachersAssigned is published when the teachers of a course
+// offering change. Added and Removed are teacher IDs.
+type CourseTeachersAssigned struct {
+	Offering *CourseOffering `json:"offering"`
+	Added    []uuid.UUID     `json:"added"`
+	Removed  []uuid.UUID     `json:"removed"`
+}
+
+func (CourseTeachersAssigned) EventName() string { return "course_offering.teachers_assigned" }
+
+// UserRegistered is published after a new account signs up.
+type UserRegistered struct {
+	User *User `json:"user"`
+}
+
+func (UserRegistered) EventName() string { return "user.registered" }
+
+// entityEventName builds "course_category.created" from the model type.
+func entityEventName[T any](action string) string {
+	name := reflect.TypeOf(new(T)).Elem().Name()
+
+	var sb strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 && !unicode.IsUpper(rune(name[i-1])) {
+				sb.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+	return sb.String() + "." + action
+}
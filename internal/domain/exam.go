@@ -0,0 +1,67 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Exam is one scheduled exam of a course for a batch, with the chapters
+// it covers. University and department are copied from the batch.
+type Exam struct {
+	Base
+	CourseID     uuid.UUID `gorm:"type:uuid;not null;index" json:"course_id"`
+	Course       *Course   `json:"course,omitempty"`
+	BatchID      uuid.UUID `gorm:"type:uuid;not null;index:idx_exam_batch_time,priority:1" json:"batch_id"`
+	UniversityID uuid.UUID `gorm:"type:uuid;not null;index" json:"university_id"`
+	DepartmentID uuid.UUID `gorm:"type:uuid;not null;index" json:"department_id"`
+	Type         string    `gorm:"size:20;not null;index" json:"type"` // One of ExamTypes
+	Title        string    `gorm:"size:255" json:"title"`              // e.g. "Lab test 2"; optional
+	StartsAt     time.Time `gorm:"not null;index:idx_exam_batch_time,priority:2" json:"starts_at"`
+	EndsAt       time.Time `gorm:"not null" json:"ends_at"`
+	Room         string    `gorm:"size:100" json:"room"`
+	Note         string    `gorm:"type:text" json:"note"`
+	Chapters     []Chapter `gorm:"many2many:exam_chapters;save_associations:false" json:"chapters,omitempty"`
+	// ChapterIDs sets the syllabus on create and update
+	ChapterIDs []uuid.UUID `gorm:"-" json:"chapter_ids,omitempty"`
+}
+
+// Exam types.
+const (
+	ExamTypeMidterm = "midterm"
+	ExamTypeFinal   = "final"
+	ExamTypeLab     = "lab"
+	ExamTypeQuiz    = "quiz"
+)
+
+// ExamTypes lists the valid Exam.Type values.
+var ExamTypes = []string{ExamTypeMidterm, ExamTypeFinal, ExamTypeLab, ExamTypeQuiz}
+
+// ExamReminder records that the batch was reminded of an exam LeadHours
+// ahead, so each reminder goes out once. Rescheduling an exam clears them.
+type ExamReminder struct {
+	Base
+	ExamID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_exam_reminder" json:"exam_id"`
+	LeadHours int       `gorm:"not null;uniqueIndex:idx_exam_reminder" json:"lead_hours"`
+}
+
+// ExamRepository adds the syllabus, schedule and reminder queries to the
+// CRUD repository.
+type ExamRepository interface {
+	Repository[Exam]
+	// SetChapters replaces the chapters an exam covers.
+	SetChapters(ctx context.Context, examID uuid.UUID, chapterIDs []uuid.UUID) error
+	// Schedule lists the batch's exams ending after from, soonest first,
+	// optionally of one type, with their course and chapters.
+	Schedule(ctx context.Context, batchID uuid.UUID, from time.Time, examType string, limit, offset int) ([]Exam, int64, error)
+	// Starting returns the exams starting in (from, to], with their course.
+	Starting(ctx context.Context, from, to time.Time) ([]Exam, error)
+	// Reminded returns the lead hours already reminded of for an exam.
+	Reminded(ctx context.Context, examID uuid.UUID) ([]int, error)
+	// AddReminders records reminders and returns how many were new; ones
+	// already recorded (e.g. by another instance) are skipped.
+	AddReminders(ctx context.Context, reminders []ExamReminder) (int64, error)
+	// ClearReminders forgets the reminders of an exam.
+	ClearReminders(ctx context.Context, examID uuid.UUID) error
+}
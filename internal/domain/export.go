@@ -0,0 +1,9 @@
+package domain
+
+// Exportable marks models admins can download as CSV/XLSX from their list
+// endpoint. ExportColumns names the exported fields by json tag, in column
+// order; anything not listed, such as contact details and claim codes, is
+// never exported. Models without it cannot be exported.
+type Exportable interface {
+	ExportColumns() []string
+}
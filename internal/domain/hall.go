@@ -1,12 +1,56 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 // Hall represents a residential hall in a university.
 type Hall struct {
 	Base
 	Name         string      `gorm:"size:255;not null" json:"name"`
-	Slug         string      `gorm:"size:255;not null;index" json:"slug"`
-	UniversityID uuid.UUID   `gorm:"type:uuid;not null;index" json:"university_id"`
+	Slug         string      `gorm:"size:255;not null;index;uniqueIndex:idx_halls_scoped_slug,priority:2,where:deleted_at IS NULL" json:"slug"`
+	UniversityID uuid.UUID   `gorm:"type:uuid;not null;index;uniqueIndex:idx_halls_scoped_slug,priority:1" json:"university_id"`
 	University   *University `json:"university,omitempty"`
 }
+
+// SlugScope: slugs are unique within a university (see Slugged).
+func (Hall) SlugScope() string {
+	return "university_id"
+}
+
+func (h Hall) GetSlug() string {
+	return h.Slug
+}
+
+func (h *Hall) setSlug(slug string) {
+	h.Slug = slug
+}
+
+func (h Hall) slugSource() string {
+	return h.Name
+}
+
+func (h Hall) slugScopeID() uuid.UUID {
+	return h.UniversityID
+}
+
+// BeforeCreate assigns the ID and a slug that is unique within its scope.
+func (h *Hall) BeforeCreate(tx *gorm.DB) error {
+	if err := h.Base.BeforeCreate(tx); err != nil {
+		return err
+	}
+	return assignSlug(tx, h)
+}
+
+// BeforeUpdate re-slugs renamed records and keeps the old slug as an alias.
+func (h *Hall) BeforeUpdate(tx *gorm.DB) error {
+	return trackSlug(tx, h)
+}
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Hall) ExportColumns() []string {
+	return []string{
+		"id", "name", "slug", "university_id", "created_at", "updated_at",
+	}
+}
@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IdempotencyStatus string
+
+const (
+	IdempotencyProcessing IdempotencyStatus = "processing" // First request still running
+	IdempotencyCompleted  IdempotencyStatus = "completed"  // Response stored for replay
+)
+
+// IdempotencyKey stores the first response for an Idempotency-Key header so
+// client retries (flaky mobile networks) replay it instead of creating duplicates.
+type IdempotencyKey struct {
+	ID           uuid.UUID         `gorm:"type:uuid;primaryKey" json:"id"`
+	Key          string            `gorm:"size:255;not null;uniqueIndex:idx_idempotency_client_key" json:"key"`
+	ClientID     string            `gorm:"size:100;not null;uniqueIndex:idx_idempotency_client_key" json:"client_id"` // user ID or API key + install fingerprint
+	Method       string            `gorm:"size:10" json:"method"`
+	Path         string            `gorm:"size:255" json:"path"`
+	RequestHash  string            `gorm:"size:64" json:"request_hash"`
+	Status       IdempotencyStatus `gorm:"size:20;not null" json:"status"`
+	ResponseCode int               `json:"response_code"`
+	ContentType  string            `gorm:"size:100" json:"content_type"`
+	ResponseBody []byte            `gorm:"type:bytea" json:"-"`
+	CreatedAt    time.Time         `json:"created_at"`
+	ExpiresAt    time.Time         `gorm:"index" json:"expires_at"` // lease while processing, replay window once completed
+}
+
+// IdempotencyRepository persists idempotency records.
+type IdempotencyRepository interface {
+	// Reserve inserts rec in the processing state. If a live record already
+	// exists for the same client and key it is returned instead (reserved=false);
+	// expired ones, including processing records past their lease, are replaced.
+	Reserve(ctx context.Context, rec *IdempotencyKey) (existing *IdempotencyKey, reserved bool, err error)
+	// Complete stores the response for replay until expiresAt.
+	Complete(ctx context.Context, id uuid.UUID, code int, contentType string, body []byte, expiresAt time.Time) error
+	// Renew extends the lease of a record still processing to expiresAt.
+	Renew(ctx context.Context, id uuid.UUID, expiresAt time.Time) error
+	Release(ctx context.Context, id uuid.UUID) error
+	DeleteExpired(ctx context.Context) (int64, error)
+}
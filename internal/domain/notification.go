@@ -1,16 +1,29 @@
 package domain
 
 import (
+	"context"
+
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
 )
 
 type Notification struct {
 	Base
-	UserID uuid.UUID       `gorm:"type:uuid;index" json:"user_id"`
+	UserID uuid.UUID       `gorm:"type:uuid;index;uniqueIndex:idx_notification_event,priority:2" json:"user_id"`
 	Title  string          `gorm:"size:255" json:"title"`
 	Body   string          `gorm:"type:text" json:"body"`
 	Type   string          `gorm:"size:50" json:"type"` // e.g. "NOTE_UPLOADED", "BATCH_NOTICE"
 	IsRead bool            `gorm:"default:false" json:"is_read"`
 	Data   *datatypes.JSON `gorm:"type:jsonb" json:"data,omitempty"` // Extra data for navigation
+	// EventID is the outbox event a notification was written for, so a
+	// redelivered event notifies each user once. Empty for direct ones.
+	EventID *uuid.UUID `gorm:"type:uuid;uniqueIndex:idx_notification_event,priority:1" json:"event_id,omitempty"`
+}
+
+// NotificationRepository adds event-keyed inserts to the CRUD repository.
+type NotificationRepository interface {
+	Repository[Notification]
+	// CreateOnce stores n unless its user already has a notification for
+	// n.EventID (e.g. written before the relay crashed); then it is skipped.
+	CreateOnce(ctx context.Context, n *Notification) error
 }
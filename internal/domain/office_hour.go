@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OfficeHour is a weekly window in which a teacher takes appointments,
+// split into slots of SlotMinutes. StartTime and EndTime are "15:04" wall
+// clock times in the campus time zone (config TIMEZONE).
+type OfficeHour struct {
+	Base
+	TeacherID    uuid.UUID `gorm:"type:uuid;not null;index" json:"teacher_id"`
+	Teacher      *Teacher  `json:"teacher,omitempty"`
+	UniversityID uuid.UUID `gorm:"type:uuid;not null;index" json:"university_id"`
+	DepartmentID uuid.UUID `gorm:"type:uuid;not null;index" json:"department_id"`
+	Weekday      int       `gorm:"not null" json:"weekday"` // 0 = Sunday ... 6 = Saturday
+	StartTime    string    `gorm:"size:5;not null" json:"start_time"`
+	EndTime      string    `gorm:"size:5;not null" json:"end_time"`
+	SlotMinutes  int       `gorm:"not null;default:15" json:"slot_minutes"`
+	Location     string    `gorm:"size:255" json:"location"`
+	Note         string    `gorm:"type:text" json:"note"`
+	IsActive     bool      `gorm:"not null;default:false" json:"is_active"`
+}
+
+// Appointment is a student's booking of one office-hour slot. Names and
+// location are copied when it is booked so notifications and calendar
+// files do not need to load the profiles.
+type Appointment struct {
+	Base
+	OfficeHourID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"office_hour_id"`
+	TeacherID     uuid.UUID  `gorm:"type:uuid;not null;index:idx_appointment_teacher_time,priority:1" json:"teacher_id"`
+	TeacherUserID *uuid.UUID `gorm:"type:uuid;index" json:"teacher_user_id,omitempty"`
+	TeacherName   string     `gorm:"size:100" json:"teacher_name"`
+	StudentID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"student_id"`
+	StudentUserID uuid.UUID  `gorm:"type:uuid;not null;index" json:"student_user_id"`
+	StudentName   string     `gorm:"size:100" json:"student_name"`
+	UniversityID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"university_id"`
+	DepartmentID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"department_id"`
+	StartsAt      time.Time  `gorm:"not null;index:idx_appointment_teacher_time,priority:2" json:"starts_at"`
+	EndsAt        time.Time  `gorm:"not null" json:"ends_at"`
+	Location      string     `gorm:"size:255" json:"location"`
+	Purpose       string     `gorm:"type:text" json:"purpose"`
+	Status        string     `gorm:"size:20;not null;index" json:"status"`
+	Response      string     `gorm:"type:text" json:"response,omitempty"` // The teacher's note on approval or decline
+	DecidedAt     *time.Time `json:"decided_at,omitempty"`
+	CancelledAt   *time.Time `json:"cancelled_at,omitempty"`
+	// Bumped on every change; the SEQUENCE of the calendar event
+	Sequence int `gorm:"not null;default:0" json:"sequence"`
+}
+
+// Appointment statuses. Pending and approved appointments hold their slot.
+const (
+	AppointmentStatusPending   = "pending"
+	AppointmentStatusApproved  = "approved"
+	AppointmentStatusDeclined  = "declined"
+	AppointmentStatusCancelled = "cancelled"
+)
+
+// AppointmentRepository adds the booking queries to the CRUD repository.
+type AppointmentRepository interface {
+	Repository[Appointment]
+	// LockTeacher locks the teacher row for the rest of the transaction in
+	// ctx, so bookings with one teacher run one at a time.
+	LockTeacher(ctx context.Context, teacherID uuid.UUID) error
+	// Overlapping returns the pending and approved appointments of the
+	// teacher or the student that overlap [start, end).
+	Overlapping(ctx context.Context, teacherID, studentID uuid.UUID, start, end time.Time) ([]Appointment, error)
+	// Booked returns the teacher's pending and approved appointments that
+	// start in [from, to).
+	Booked(ctx context.Context, teacherID uuid.UUID, from, to time.Time) ([]Appointment, error)
+	// Involving lists the appointments where the user is the student or the
+	// teacher, soonest first, optionally with one status.
+	Involving(ctx context.Context, userID uuid.UUID, status string, limit, offset int) ([]Appointment, int64, error)
+}
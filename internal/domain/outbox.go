@@ -0,0 +1,151 @@
+package domain
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+)
+
+type OutboxStatus string
+
+const (
+	OutboxPending OutboxStatus = "pending" // Waiting for the relay (first run or retry)
+	OutboxDone    OutboxStatus = "done"    // Every dispatcher succeeded
+	OutboxFailed  OutboxStatus = "failed"  // Retries exhausted; needs a look
+)
+
+// OutboxEvent is a domain event stored in the same transaction as the change
+// it describes. The relay hands it to each dispatcher until all of them have
+// succeeded, so delivery is at-least-once. Dispatched lists the dispatchers
+// that are done and are skipped on retries.
+type OutboxEvent struct {
+	ID            uuid.UUID      `gorm:"type:uuid;primaryKey" json:"id"`
+	EventName     string         `gorm:"size:100;not null;index" json:"event_name"`
+	UniversityID  *uuid.UUID     `gorm:"type:uuid;index" json:"university_id,omitempty"`
+	DepartmentID  *uuid.UUID     `gorm:"type:uuid;index" json:"department_id,omitempty"`
+	Payload       datatypes.JSON `gorm:"type:jsonb;not null" json:"payload"`
+	Status        OutboxStatus   `gorm:"size:20;not null;index:idx_outbox_due,priority:1" json:"status"`
+	NextAttemptAt time.Time      `gorm:"not null;index:idx_outbox_due,priority:2" json:"next_attempt_at"`
+	Attempts      int            `gorm:"default:0" json:"attempts"`
+	Dispatched    pq.StringArray `gorm:"type:text[];default:'{}'" json:"dispatched"`
+	LastError     string         `gorm:"type:text" json:"last_error,omitempty"`
+	ProcessedAt   *time.Time     `json:"processed_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+}
+
+// NewOutboxEvent serializes e and tags it with the university/department it
+// is about, so scoped consumers can filter without decoding the payload.
+func NewOutboxEvent(e Event) (*OutboxEvent, error) {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	row := &OutboxEvent{
+		ID:            uuid.New(),
+		EventName:     e.EventName(),
+		Payload:       payload,
+		Status:        OutboxPending,
+		NextAttemptAt: now,
+		Dispatched:    pq.StringArray{},
+		CreatedAt:     now,
+	}
+	u, d := eventScope(e)
+	if u != uuid.Nil {
+		row.UniversityID = &u
+	}
+	if d != uuid.Nil {
+		row.DepartmentID = &d
+	}
+	return row, nil
+}
+
+// Outbox records events atomically with the writes around them.
+type Outbox interface {
+	// Transaction runs fn in a database transaction. Repositories called
+	// with the context passed to fn join it; nested calls run in a
+	// savepoint of it, so their failure leaves the outer one usable.
+	Transaction(ctx context.Context, fn func(ctx context.Context) error) error
+	// AfterCommit runs fn once the outermost transaction carried by ctx
+	// commits and drops it if that (or the savepoint fn was registered in)
+	// rolls back. Without a transaction fn runs right away. fn gets a
+	// context without the transaction.
+	AfterCommit(ctx context.Context, fn func(ctx context.Context))
+	// Add stores events in the transaction carried by ctx (or on its own
+	// when there is none).
+	Add(ctx context.Context, events ...Event) error
+}
+
+// OutboxRepository is the Outbox plus what the relay needs.
+type OutboxRepository interface {
+	Outbox
+	// Claim locks up to limit due pending events (FOR UPDATE SKIP LOCKED, so
+	// concurrent relays never share rows) and leases them until now+lease.
+	Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]OutboxEvent, error)
+	Save(ctx context.Context, e *OutboxEvent) error
+	DeleteProcessed(ctx context.Context, before time.Time) (int64, error)
+}
+
+// OutboxDispatcher consumes outbox events (webhooks, push, a search index).
+// Name must be stable: it is stored in OutboxEvent.Dispatched.
+// Dispatch should return within seconds: the relay leases a batch for two
+// minutes and stops starting its events after one.
+type OutboxDispatcher interface {
+	Name() string
+	Dispatch(ctx context.Context, e *OutboxEvent) error
+}
+
+// eventScope finds the university and department an event is about. It
+// reads UniversityID/DepartmentID from the event itself or from the entity
+// it carries (Entity, Resource, User, ...); a University or Department
+// entity is its own scope. Unknown parts are uuid.Nil.
+func eventScope(e Event) (universityID, departmentID uuid.UUID) {
+	v := reflect.Indirect(reflect.ValueOf(e))
+	if v.Kind() != reflect.Struct {
+		return uuid.Nil, uuid.Nil
+	}
+	if u, d := uuidField(v, "UniversityID"), uuidField(v, "DepartmentID"); u != uuid.Nil || d != uuid.Nil {
+		return u, d
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Field(i)
+		if f.Kind() != reflect.Ptr || f.IsNil() || !f.CanInterface() {
+			continue
+		}
+		switch ent := f.Interface().(type) {
+		case *University:
+			return ent.ID, uuid.Nil
+		case *Department:
+			return ent.UniversityID, ent.ID
+		}
+		if f.Elem().Kind() == reflect.Struct {
+			if u, d := uuidField(f.Elem(), "UniversityID"), uuidField(f.Elem(), "DepartmentID"); u != uuid.Nil || d != uuid.Nil {
+				return u, d
+			}
+		}
+	}
+	return uuid.Nil, uuid.Nil
+}
+
+func uuidField(v reflect.Value, name string) uuid.UUID {
+	f := v.FieldByName(name)
+	if !f.IsValid() || !f.CanInterface() {
+		return uuid.Nil
+	}
+	switch id := f.Interface().(type) {
+	case uuid.UUID:
+		return id
+	case *uuid.UUID:
+		if id != nil {
+			return *id
+		}
+	}
+	return uuid.Nil
+}
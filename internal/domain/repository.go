@@ -16,6 +16,12 @@ type Repository[T any] interface {
 	// Additional flexible query methods could be added here
 }
 
+// Streamer is implemented by repositories that can walk a whole filtered
+// collection with a DB cursor instead of loading one page into memory.
+type Streamer[T any] interface {
+	Stream(ctx context.Context, filter map[string]interface{}, fn func(*T) error) error
+}
+
 // Specific repositories can extend this if needed
 type UserRepository interface {
 	Repository[User]
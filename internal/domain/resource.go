@@ -18,6 +18,16 @@ const (
 	ResourceStatusDraft     ResourceStatus = "draft"     // Saved but not submitted yet
 )
 
+// EnumValues lists the allowed statuses (used by the OpenAPI spec).
+func (ResourceStatus) EnumValues() []string {
+	return []string{
+		string(ResourceStatusPublished),
+		string(ResourceStatusPending),
+		string(ResourceStatusRejected),
+		string(ResourceStatusDraft),
+	}
+}
+
 // ResourceAccessLevel controls who can access the content.
 type ResourceAccessLevel string
 
@@ -26,6 +36,11 @@ const (
 	AccessLevelPro   ResourceAccessLevel = "pro"   // Pro subscribers only
 )
 
+// EnumValues lists the allowed access levels.
+func (ResourceAccessLevel) EnumValues() []string {
+	return []string{string(AccessLevelBasic), string(AccessLevelPro)}
+}
+
 // Resource represents any academic material (Note, Question, Book, Syllabus).
 // Uses a unified model with a JSONB `metadata` field for type-specific data.
 type Resource struct {
@@ -84,3 +99,20 @@ type Resource struct {
 	// TODO: FCM notification fields — add when notification service is ready
 	// NotifyOnApproval bool  — flag to send push to uploader on status change
 }
+
+// KeepRevisions opts Resource in to revision history (see Versioned).
+func (Resource) KeepRevisions() bool {
+	return true
+}
+
+// ExportColumns lists the fields admins can export (see Exportable). The
+// metadata object is flattened into one column per key.
+func (Resource) ExportColumns() []string {
+	return []string{
+		"id", "type", "title", "description", "course_code", "lesson_no",
+		"status", "access_level", "file_url", "uploader_name",
+		"university_id", "department_id", "file_size_bytes", "page_count",
+		"download_count", "view_count", "rating_avg", "rating_count",
+		"is_verified", "tags", "is_public", "metadata", "created_at", "updated_at",
+	}
+}
@@ -9,3 +9,8 @@ const (
 	TypeSyllabus ResourceType = "syllabus"
 	TypeBook     ResourceType = "book"
 )
+
+// EnumValues lists the allowed resource types.
+func (ResourceType) EnumValues() []string {
+	return []string{string(TypeNote), string(TypeQuestion), string(TypeSyllabus), string(TypeBook)}
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Versioned marks entities whose edits are kept as revision snapshots.
+// Implement it on a model to opt in to revision history and rollback.
+type Versioned interface {
+	KeepRevisions() bool
+}
+
+// Revision is a full JSON snapshot of an entity after a change.
+type Revision struct {
+	Base
+	EntityName string         `gorm:"size:50;not null;uniqueIndex:idx_revision_entity_version" json:"entity_name"`
+	EntityID   uuid.UUID      `gorm:"type:uuid;not null;uniqueIndex:idx_revision_entity_version" json:"entity_id"`
+	Version    int            `gorm:"not null;uniqueIndex:idx_revision_entity_version" json:"version"`
+	Action     string         `gorm:"size:20" json:"action"` // CREATE, UPDATE, DELETE, RESTORE
+	Snapshot   datatypes.JSON `gorm:"type:jsonb;not null" json:"snapshot"`
+}
+
+// RevisionRepository stores and reads entity snapshots.
+type RevisionRepository interface {
+	// Create assigns the next version number for the entity and saves the revision.
+	Create(ctx context.Context, rev *Revision) error
+	ListByEntity(ctx context.Context, entityName string, entityID uuid.UUID) ([]Revision, error)
+	GetByVersion(ctx context.Context, entityName string, entityID uuid.UUID, version int) (*Revision, error)
+}
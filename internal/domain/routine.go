@@ -1,6 +1,10 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
 
 // Routine represents a class or exam routine.
 type Routine struct {
@@ -11,3 +15,43 @@ type Routine struct {
 	UniversityID uuid.UUID `gorm:"type:uuid;index" json:"university_id"`
 	DepartmentID uuid.UUID `gorm:"type:uuid;index" json:"department_id"`
 }
+
+// RoutineEntry is one weekly class of a course offering: its day, time and
+// room, and who teaches it, repeated every week from ValidFrom to
+// ValidUntil. StartTime and EndTime are "15:04" wall clock times and the
+// dates "2006-01-02" dates in the campus time zone (config TIMEZONE).
+// Batch, university and department are copied from the offering.
+type RoutineEntry struct {
+	Base
+	OfferingID   uuid.UUID       `gorm:"type:uuid;not null;index" json:"offering_id"`
+	Offering     *CourseOffering `json:"offering,omitempty"`
+	TeacherID    *uuid.UUID      `gorm:"type:uuid;index" json:"teacher_id,omitempty"` // Defaults to the offering's only teacher
+	Teacher      *Teacher        `json:"teacher,omitempty"`
+	BatchID      uuid.UUID       `gorm:"type:uuid;not null;index" json:"batch_id"`
+	UniversityID uuid.UUID       `gorm:"type:uuid;not null;index" json:"university_id"`
+	DepartmentID uuid.UUID       `gorm:"type:uuid;not null;index" json:"department_id"`
+	Weekday      int             `gorm:"not null;index" json:"weekday"` // 0 = Sunday ... 6 = Saturday
+	StartTime    string          `gorm:"size:5;not null" json:"start_time"`
+	EndTime      string          `gorm:"size:5;not null" json:"end_time"`
+	Room         string          `gorm:"size:100;not null" json:"room"`
+	ValidFrom    string          `gorm:"size:10;not null" json:"valid_from"`
+	ValidUntil   string          `gorm:"size:10;not null" json:"valid_until"`
+	Note         string          `gorm:"type:text" json:"note"`
+}
+
+// RoutineRepository adds the timetable queries to the CRUD repository.
+type RoutineRepository interface {
+	Repository[RoutineEntry]
+	// LockWeekday takes a lock on the university's weekday for the rest of
+	// the transaction in ctx, so clash checks and saves for it run one at a
+	// time.
+	LockWeekday(ctx context.Context, universityID uuid.UUID, weekday int) error
+	// Clashes returns the other entries on the same weekday whose times and
+	// validity overlap entry's and that use the same room (within the
+	// university, ignoring case) or the same teacher.
+	Clashes(ctx context.Context, entry *RoutineEntry) ([]RoutineEntry, error)
+	// Batch returns the batch's entries valid at some point in [from, until]
+	// (dates as "2006-01-02"), by weekday and start time, with their
+	// offering's course and teacher.
+	Batch(ctx context.Context, batchID uuid.UUID, from, until string) ([]RoutineEntry, error)
+}
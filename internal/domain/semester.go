@@ -36,3 +36,12 @@ func (s *Semester) BeforeSave(tx *gorm.DB) (err error) {
 	}
 	return nil
 }
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Semester) ExportColumns() []string {
+	return []string{
+		"id", "name", "order", "status", "university_id", "department_id",
+		"total_courses", "total_credits", "total_marks", "created_at",
+		"updated_at",
+	}
+}
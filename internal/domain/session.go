@@ -1,13 +1,58 @@
 package domain
 
-import "github.com/google/uuid"
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
 
 // Session represents an academic session (e.g., 2023-2024).
 type Session struct {
 	Base
-	Name         string    `gorm:"size:50;not null" json:"name"`       // e.g. "2019-20"
-	Slug         string    `gorm:"size:50;not null;index" json:"slug"` // e.g. "19-20"
-	UniversityID uuid.UUID `gorm:"type:uuid;not null;index" json:"university_id"`
+	Name         string    `gorm:"size:50;not null" json:"name"`                                                                                // e.g. "2019-20"
+	Slug         string    `gorm:"size:50;not null;index;uniqueIndex:idx_sessions_scoped_slug,priority:2,where:deleted_at IS NULL" json:"slug"` // e.g. "19-20"
+	UniversityID uuid.UUID `gorm:"type:uuid;not null;index;uniqueIndex:idx_sessions_scoped_slug,priority:1" json:"university_id"`
 	DepartmentID uuid.UUID `gorm:"type:uuid;index" json:"department_id,omitempty"` // Scoped if needed
 	IsActive     bool      `gorm:"default:true" json:"is_active"`
 }
+
+// SlugScope: slugs are unique within a university (see Slugged).
+func (Session) SlugScope() string {
+	return "university_id"
+}
+
+func (s Session) GetSlug() string {
+	return s.Slug
+}
+
+func (s *Session) setSlug(slug string) {
+	s.Slug = slug
+}
+
+func (s Session) slugSource() string {
+	return s.Name
+}
+
+func (s Session) slugScopeID() uuid.UUID {
+	return s.UniversityID
+}
+
+// BeforeCreate assigns the ID and a slug that is unique within its scope.
+func (s *Session) BeforeCreate(tx *gorm.DB) error {
+	if err := s.Base.BeforeCreate(tx); err != nil {
+		return err
+	}
+	return assignSlug(tx, s)
+}
+
+// BeforeUpdate re-slugs renamed records and keeps the old slug as an alias.
+func (s *Session) BeforeUpdate(tx *gorm.DB) error {
+	return trackSlug(tx, s)
+}
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Session) ExportColumns() []string {
+	return []string{
+		"id", "name", "slug", "university_id", "department_id", "is_active",
+		"created_at", "updated_at",
+	}
+}
@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"campusassistant-api/pkg/slug"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Slugged marks entities addressable by a URL slug. SlugScope names the
+// column the slug is unique within, or "" when it is unique globally.
+type Slugged interface {
+	SlugScope() string
+	GetSlug() string
+}
+
+// ErrEmptySlug is returned when neither the slug nor its source field
+// contains anything a slug can be built from.
+var ErrEmptySlug = errors.New("slug is empty: set a name or an explicit slug")
+
+// ErrSlugTaken is returned by repositories when a concurrent write took the
+// slug assignSlug picked. Writing again with the requested slug restored
+// (see RestoreSlug) picks the next free suffix.
+var ErrSlugTaken = errors.New("slug is already in use")
+
+// SlugAlias keeps a retired slug so old URLs keep resolving after a rename.
+// EntityName is the Go model name (University, Batch, ...).
+type SlugAlias struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey" json:"id"`
+	EntityName string    `gorm:"size:50;not null;uniqueIndex:idx_slug_alias" json:"entity_name"`
+	ScopeID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_slug_alias" json:"scope_id"` // uuid.Nil for global slugs
+	Slug       string    `gorm:"size:255;not null;uniqueIndex:idx_slug_alias" json:"slug"`
+	EntityID   uuid.UUID `gorm:"type:uuid;not null;index" json:"entity_id"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// SlugAliasRepository looks up retired slugs.
+type SlugAliasRepository interface {
+	// Find returns aliases for slug; scope uuid.Nil matches every scope.
+	Find(ctx context.Context, entityName, slug string, scope uuid.UUID) ([]SlugAlias, error)
+}
+
+// sluggable is implemented by the slugged models so they can share the hooks below.
+type sluggable interface {
+	Slugged
+	GetID() uuid.UUID
+	slugSource() string
+	slugScopeID() uuid.UUID
+	setSlug(s string)
+}
+
+// RestoreSlug sets the slug of a slugged entity back to the one requested
+// before assignSlug normalized and suffixed it. Other entities are left alone.
+func RestoreSlug(entity any, requested string) {
+	if m, ok := entity.(sluggable); ok {
+		m.setSlug(requested)
+	}
+}
+
+// assignSlug normalizes the entity's slug (deriving it from slugSource when
+// empty) and appends -2, -3, ... until it is free within the scope. Live,
+// soft-deleted and retired (alias) slugs all count as taken.
+func assignSlug(tx *gorm.DB, m sluggable) error {
+	base := slug.Make(m.GetSlug())
+	if base == "" {
+		base = slug.Make(m.slugSource())
+	}
+	if base == "" {
+		return ErrEmptySlug
+	}
+
+	db := tx.Session(&gorm.Session{NewDB: true, Context: tx.Statement.Context})
+	pattern := base + "-%"
+
+	var taken []string
+	q := db.Unscoped().Table(tx.Statement.Table).
+		Where("(slug = ? OR slug LIKE ?) AND id <> ?", base, pattern, m.GetID())
+	if col := m.SlugScope(); col != "" {
+		q = q.Where(col+" = ?", m.slugScopeID())
+	}
+	if err := q.Pluck("slug", &taken).Error; err != nil {
+		return err
+	}
+
+	var aliases []string
+	if err := db.Model(&SlugAlias{}).
+		Where("entity_name = ? AND scope_id = ? AND entity_id <> ? AND (slug = ? OR slug LIKE ?)",
+			tx.Statement.Schema.Name, m.slugScopeID(), m.GetID(), base, pattern).
+		Pluck("slug", &aliases).Error; err != nil {
+		return err
+	}
+
+	used := make(map[string]bool, len(taken)+len(aliases))
+	for _, s := range append(taken, aliases...) {
+		used[s] = true
+	}
+	for n := 1; ; n++ {
+		if candidate := slug.WithSuffix(base, n); !used[candidate] {
+			m.setSlug(candidate)
+			return nil
+		}
+	}
+}
+
+// trackSlug runs on update. A rename with an unchanged (or omitted) slug
+// regenerates it from the new source; an explicit new slug is normalized and
+// de-duplicated.
+// Either way the previous slug is kept as a SlugAlias.
+func trackSlug[T any, PT interface {
+	*T
+	sluggable
+}](tx *gorm.DB, m PT) error {
+	if m.GetID() == uuid.Nil || m.slugSource() == "" {
+		// Partial updates (Model(&x).Update(...)) carry no slug information.
+		return nil
+	}
+
+	db := tx.Session(&gorm.Session{NewDB: true, Context: tx.Statement.Context})
+
+	old := PT(new(T))
+	if err := db.Unscoped().Where("id = ?", m.GetID()).Take(old).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	renamed := m.slugSource() != old.slugSource()
+	switch {
+	case m.GetSlug() == "" && !renamed:
+		// Full-object PUTs that omit the slug keep the current one.
+		m.setSlug(old.GetSlug())
+		return nil
+	case m.GetSlug() == old.GetSlug() && !renamed:
+		return nil
+	case m.GetSlug() == old.GetSlug() || m.GetSlug() == "":
+		m.setSlug("")
+	}
+	if err := assignSlug(tx, m); err != nil {
+		return err
+	}
+	if old.GetSlug() == "" || (m.GetSlug() == old.GetSlug() && m.slugScopeID() == old.slugScopeID()) {
+		return nil
+	}
+
+	entityName := tx.Statement.Schema.Name
+
+	// Taking back a slug this entity used before retires its alias.
+	if err := db.Where("entity_name = ? AND scope_id = ? AND slug = ? AND entity_id = ?",
+		entityName, m.slugScopeID(), m.GetSlug(), m.GetID()).
+		Delete(&SlugAlias{}).Error; err != nil {
+		return err
+	}
+
+	alias := &SlugAlias{
+		ID:         uuid.New(),
+		EntityName: entityName,
+		ScopeID:    old.slugScopeID(),
+		Slug:       old.GetSlug(),
+		EntityID:   m.GetID(),
+	}
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(alias).Error
+}
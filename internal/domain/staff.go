@@ -9,14 +9,26 @@ import (
 // Staff represents a general staff member (office, lab, support).
 type Staff struct {
 	Base
+	UserID           *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"user_id,omitempty"`
+	User             *User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE;" json:"user,omitempty"`
 	DepartmentID     uuid.UUID  `gorm:"type:uuid;index" json:"department_id,omitempty"`
 	UniversityID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"university_id"`
 	Name             string     `gorm:"size:100" json:"name"`
 	Post             string     `gorm:"size:100" json:"post"` // e.g., Office Assistant, Lab Technician
 	Mobile           string     `gorm:"size:20" json:"mobile"`
 	ImageURL         string     `gorm:"size:500" json:"image_url"`
-	Serial           int        `gorm:"default:0" json:"serial"` // Display order
-	VerificationCode string     `gorm:"size:20;index" json:"verification_code"`
+	Serial           int        `gorm:"default:0" json:"serial"`                                                                                                     // Display order
+	VerificationCode string     `gorm:"size:64;uniqueIndex:idx_staffs_claim_code,where:user_id IS NULL AND verification_code <> '' AND deleted_at IS NULL" json:"-"` // HMAC of the claim code, see usecase.ClaimCodes
+	CodeExpiresAt    *time.Time `json:"code_expires_at,omitempty"`
+	IssuedCode       string     `gorm:"-" json:"-"` // Plain code, only set when issued
 	IsClaimed        bool       `gorm:"default:false" json:"is_claimed"`
 	ClaimedAt        *time.Time `json:"claimed_at,omitempty"`
 }
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Staff) ExportColumns() []string {
+	return []string{
+		"id", "name", "post", "university_id", "department_id", "serial",
+		"is_claimed", "created_at", "updated_at",
+	}
+}
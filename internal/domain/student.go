@@ -29,7 +29,18 @@ type Student struct {
 	BloodGroup       string      `gorm:"size:5" json:"blood_group"`
 	Weight           int         `gorm:"default:0" json:"weight"` // Firestore "orderBy"
 	IsCR             bool        `gorm:"default:false" json:"is_cr"`
-	VerificationCode string      `gorm:"size:20;index" json:"verification_code"` // For profile claiming
+	VerificationCode string      `gorm:"size:64;uniqueIndex:idx_students_claim_code,where:user_id IS NULL AND verification_code <> '' AND deleted_at IS NULL" json:"-"` // HMAC of the claim code, see usecase.ClaimCodes
+	CodeExpiresAt    *time.Time  `json:"code_expires_at,omitempty"`
+	IssuedCode       string      `gorm:"-" json:"-"` // Plain code, only set when issued
 	IsClaimed        bool        `gorm:"default:false" json:"is_claimed"`
 	ClaimedAt        *time.Time  `json:"claimed_at,omitempty"`
 }
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Student) ExportColumns() []string {
+	return []string{
+		"id", "student_id", "name", "university_id", "department_id", "batch_id",
+		"session_id", "hall_id", "is_regular", "is_cr", "is_claimed", "created_at",
+		"updated_at",
+	}
+}
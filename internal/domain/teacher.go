@@ -24,7 +24,17 @@ type Teacher struct {
 	IsChairman       bool       `gorm:"default:false" json:"is_chairman"`
 	IsPresent        bool       `gorm:"default:true" json:"is_present"`
 	Weight           int        `gorm:"default:0" json:"weight"`
-	VerificationCode string     `gorm:"size:20;index" json:"verification_code"`
+	VerificationCode string     `gorm:"size:64;uniqueIndex:idx_teachers_claim_code,where:user_id IS NULL AND verification_code <> '' AND deleted_at IS NULL" json:"-"` // HMAC of the claim code, see usecase.ClaimCodes
+	CodeExpiresAt    *time.Time `json:"code_expires_at,omitempty"`
+	IssuedCode       string     `gorm:"-" json:"-"` // Plain code, only set when issued
 	IsClaimed        bool       `gorm:"default:false" json:"is_claimed"`
 	ClaimedAt        *time.Time `json:"claimed_at,omitempty"`
 }
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Teacher) ExportColumns() []string {
+	return []string{
+		"id", "name", "designation", "university_id", "department_id",
+		"is_chairman", "is_present", "is_claimed", "created_at", "updated_at",
+	}
+}
@@ -11,3 +11,11 @@ type Transport struct {
 	Schedule      string    `gorm:"type:text" json:"schedule"` // e.g. JSON or text description
 	DriverContact string    `json:"driver_contact"`
 }
+
+// ExportColumns lists the fields admins can export (see Exportable).
+func (Transport) ExportColumns() []string {
+	return []string{
+		"id", "route_name", "bus_number", "schedule", "driver_contact",
+		"university_id", "created_at", "updated_at",
+	}
+}
@@ -1,7 +1,9 @@
 package domain
 
 import (
+	"github.com/google/uuid"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 // University represents a university entity.
@@ -25,3 +27,37 @@ type University struct {
 	Departments      []Department    `gorm:"foreignKey:UniversityID" json:"departments,omitempty"`
 	Sessions         []Session       `gorm:"foreignKey:UniversityID" json:"sessions,omitempty"`
 }
+
+// SlugScope: slugs are globally unique (see Slugged).
+func (University) SlugScope() string {
+	return ""
+}
+
+func (u University) GetSlug() string {
+	return u.Slug
+}
+
+func (u *University) setSlug(slug string) {
+	u.Slug = slug
+}
+
+func (u University) slugSource() string {
+	return u.Name
+}
+
+func (u University) slugScopeID() uuid.UUID {
+	return uuid.Nil
+}
+
+// BeforeCreate assigns the ID and a slug that is unique within its scope.
+func (u *University) BeforeCreate(tx *gorm.DB) error {
+	if err := u.Base.BeforeCreate(tx); err != nil {
+		return err
+	}
+	return assignSlug(tx, u)
+}
+
+// BeforeUpdate re-slugs renamed records and keeps the old slug as an alias.
+func (u *University) BeforeUpdate(tx *gorm.DB) error {
+	return trackSlug(tx, u)
+}
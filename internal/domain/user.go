@@ -13,8 +13,22 @@ const (
 	RoleTeacher         Role = "teacher"
 	RoleStudent         Role = "student"
 	RoleStaff           Role = "staff"
+	RoleCR              Role = "cr" // A student serving a current CR term
 )
 
+// EnumValues lists the allowed roles.
+func (Role) EnumValues() []string {
+	return []string{
+		string(RoleSuperAdmin),
+		string(RoleUniversityAdmin),
+		string(RoleDepartmentAdmin),
+		string(RoleTeacher),
+		string(RoleStudent),
+		string(RoleStaff),
+		string(RoleCR),
+	}
+}
+
 // User represents the authentication entity.
 // Supports both JWT (password-based) and Firebase authentication.
 type User struct {
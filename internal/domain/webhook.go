@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+)
+
+// WebhookSubscription sends matching domain events to an external URL.
+// UniversityID/DepartmentID narrow it to events about that scope; leaving
+// both empty receives events from every university.
+type WebhookSubscription struct {
+	Base
+	UniversityID *uuid.UUID     `gorm:"type:uuid;index" json:"university_id,omitempty"`
+	DepartmentID *uuid.UUID     `gorm:"type:uuid;index" json:"department_id,omitempty"`
+	URL          string         `gorm:"size:2048;not null" json:"url" binding:"required"`
+	Events       pq.StringArray `gorm:"type:text[];not null" json:"events" binding:"required"` // ["resource.approved", "student.*", "*"]
+	Description  string         `gorm:"type:text" json:"description"`
+	Active       bool           `gorm:"default:true" json:"active"`
+	Secret       string         `gorm:"size:100;not null" json:"-"` // HMAC key, shown once on create/rotate
+}
+
+// Matches reports whether the subscription wants an event with the given
+// name. Patterns are exact names, "entity.*" prefixes or "*".
+func (w *WebhookSubscription) Matches(event string) bool {
+	for _, p := range w.Events {
+		if p == "*" || p == event {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(p, "*"); ok && strings.HasPrefix(event, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// InScope reports whether an event about the given university/department
+// (uuid.Nil when unknown) falls within the subscription's scope.
+func (w *WebhookSubscription) InScope(universityID, departmentID uuid.UUID) bool {
+	if w.UniversityID != nil && *w.UniversityID != universityID {
+		return false
+	}
+	if w.DepartmentID != nil && *w.DepartmentID != departmentID {
+		return false
+	}
+	return true
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Waiting for its first or next attempt
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // Receiver answered 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Retries exhausted
+)
+
+// WebhookDelivery is one event sent to one subscription, with the outcome of
+// its latest attempt. Redeliveries are new rows pointing at the original.
+type WebhookDelivery struct {
+	ID             uuid.UUID             `gorm:"type:uuid;primaryKey" json:"id"`
+	SubscriptionID uuid.UUID             `gorm:"type:uuid;not null;index" json:"subscription_id"`
+	EventID        uuid.UUID             `gorm:"type:uuid;not null;index" json:"event_id"`
+	EventName      string                `gorm:"size:100;not null;index" json:"event_name"`
+	Payload        datatypes.JSON        `gorm:"type:jsonb;not null" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"size:20;not null;index" json:"status"`
+	Attempts       int                   `gorm:"default:0" json:"attempts"`
+	ResponseCode   int                   `json:"response_code,omitempty"`
+	ResponseBody   string                `gorm:"type:text" json:"response_body,omitempty"` // truncated
+	LastError      string                `gorm:"type:text" json:"last_error,omitempty"`
+	DurationMs     int64                 `json:"duration_ms"`
+	NextAttemptAt  *time.Time            `gorm:"index" json:"next_attempt_at,omitempty"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty"`
+	RedeliveryOf   *uuid.UUID            `gorm:"type:uuid" json:"redelivery_of,omitempty"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
+
+// WebhookRepository stores subscriptions and their delivery log.
+type WebhookRepository interface {
+	Repository[WebhookSubscription]
+	// Active returns enabled subscriptions whose scope covers the given
+	// university/department; event-name matching is left to the caller.
+	Active(ctx context.Context, universityID, departmentID uuid.UUID) ([]WebhookSubscription, error)
+	UpdateSecret(ctx context.Context, id uuid.UUID, secret string) error
+
+	CreateDelivery(ctx context.Context, d *WebhookDelivery) error
+	UpdateDelivery(ctx context.Context, d *WebhookDelivery) error
+	GetDelivery(ctx context.Context, subscriptionID, id uuid.UUID) (*WebhookDelivery, error)
+	// HasDelivery reports whether the event was already delivered (or is
+	// being retried) for the subscription, ignoring manual redeliveries.
+	HasDelivery(ctx context.Context, subscriptionID, eventID uuid.UUID) (bool, error)
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, filter map[string]interface{}, limit, offset int) ([]WebhookDelivery, int64, error)
+	// DueDeliveries returns pending deliveries whose next attempt is at or before now.
+	DueDeliveries(ctx context.Context, now time.Time, limit int) ([]WebhookDelivery, error)
+}
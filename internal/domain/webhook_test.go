@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func TestWebhookSubscriptionMatches(t *testing.T) {
+	tests := []struct {
+		events []string
+		event  string
+		want   bool
+	}{
+		{[]string{"*"}, "notice.created", true},
+		{[]string{"notice.created"}, "notice.created", true},
+		{[]string{"notice.created"}, "notice.updated", false},
+		{[]string{"notice.*"}, "notice.deleted", true},
+		{[]string{"notice.*"}, "student.created", false},
+		{[]string{"student.created", "notice.*"}, "notice.updated", true},
+		{nil, "notice.created", false},
+	}
+	for _, tt := range tests {
+		sub := &WebhookSubscription{Events: pq.StringArray(tt.events)}
+		if got := sub.Matches(tt.event); got != tt.want {
+			t.Errorf("Matches(%v, %q) = %v, want %v", tt.events, tt.event, got, tt.want)
+		}
+	}
+}
+
+func TestWebhookSubscriptionInScope(t *testing.T) {
+	uni, otherUni := uuid.New(), uuid.New()
+	dept, otherDept := uuid.New(), uuid.New()
+
+	tests := []struct {
+		name         string
+		sub          WebhookSubscription
+		universityID uuid.UUID
+		departmentID uuid.UUID
+		want         bool
+	}{
+		{"unscoped sees everything", WebhookSubscription{}, uni, dept, true},
+		{"unscoped sees unknown scope", WebhookSubscription{}, uuid.Nil, uuid.Nil, true},
+		{"university match", WebhookSubscription{UniversityID: &uni}, uni, dept, true},
+		{"other university", WebhookSubscription{UniversityID: &uni}, otherUni, dept, false},
+		{"university on unknown scope", WebhookSubscription{UniversityID: &uni}, uuid.Nil, uuid.Nil, false},
+		{"department match", WebhookSubscription{UniversityID: &uni, DepartmentID: &dept}, uni, dept, true},
+		{"other department", WebhookSubscription{UniversityID: &uni, DepartmentID: &dept}, uni, otherDept, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.sub.InScope(tt.universityID, tt.departmentID); got != tt.want {
+				t.Fatalf("InScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,187 @@
+package postgres
+
+import (
+	"context"
+	"sort"
+
+	"campusassistant-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// alumniPartialFilters match anywhere in the column, case-insensitively,
+// so "google" finds "Google Bangladesh".
+var alumniPartialFilters = map[string]bool{
+	"organization": true,
+	"designation":  true,
+	"location":     true,
+}
+
+type alumniRepository struct {
+	domain.Repository[domain.Alumni]
+	db *gorm.DB
+}
+
+func NewAlumniRepository(db *gorm.DB) domain.AlumniRepository {
+	return &alumniRepository{
+		Repository: NewGormRepository[domain.Alumni](db),
+		db:         db,
+	}
+}
+
+// query applies the directory filters shared by GetAll, Stream and Stats.
+func (r *alumniRepository) query(ctx context.Context, filter map[string]interface{}) *gorm.DB {
+	db := conn(ctx, r.db).Model(&domain.Alumni{})
+
+	for key, value := range filter {
+		switch {
+		case key == "search":
+			searchVal := "%" + value.(string) + "%"
+			db = db.Where("full_name ILIKE ? OR student_id ILIKE ? OR email ILIKE ? OR organization ILIKE ? OR designation ILIKE ? OR location ILIKE ?",
+				searchVal, searchVal, searchVal, searchVal, searchVal, searchVal)
+		case key == "preload":
+			// Alumni have no associations to load
+		case alumniPartialFilters[key]:
+			db = db.Where(key+" ILIKE ?", "%"+value.(string)+"%")
+		default:
+			db = db.Where(key+" = ?", value)
+		}
+	}
+
+	return db
+}
+
+func (r *alumniRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Alumni, int64, error) {
+	var entities []domain.Alumni
+	var count int64
+
+	db := r.query(ctx, filter)
+
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("passing_year DESC, full_name ASC").Limit(limit).Offset(offset).Find(&entities).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entities, count, nil
+}
+
+func (r *alumniRepository) Stream(ctx context.Context, filter map[string]interface{}, fn func(*domain.Alumni) error) error {
+	return streamRows(r.query(ctx, filter), fn)
+}
+
+func (r *alumniRepository) Stats(ctx context.Context, filter map[string]interface{}, top int) (*domain.AlumniStats, error) {
+	stats := &domain.AlumniStats{
+		TopEmployers: []domain.AlumniCount{},
+		TopLocations: []domain.AlumniCount{},
+		Batches:      []domain.AlumniBatchStats{},
+	}
+
+	var totals struct {
+		Total    int64
+		Employed int64
+	}
+	if err := r.query(ctx, filter).
+		Select("COUNT(*) AS total, " + employedSum).
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	stats.Total, stats.Employed = totals.Total, totals.Employed
+
+	var err error
+	if stats.TopEmployers, err = r.ranking(ctx, filter, "organization", top); err != nil {
+		return nil, err
+	}
+	if stats.TopLocations, err = r.ranking(ctx, filter, "location", top); err != nil {
+		return nil, err
+	}
+
+	var batches []struct {
+		Batch    string
+		Total    int64
+		Employed int64
+	}
+	if err := r.query(ctx, filter).
+		Select("batch, COUNT(*) AS total, " + employedSum).
+		Group("batch").
+		Order("batch").
+		Scan(&batches).Error; err != nil {
+		return nil, err
+	}
+
+	employers, err := r.batchRanking(ctx, filter, "organization", top)
+	if err != nil {
+		return nil, err
+	}
+	locations, err := r.batchRanking(ctx, filter, "location", top)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range batches {
+		stats.Batches = append(stats.Batches, domain.AlumniBatchStats{
+			Batch:     b.Batch,
+			Total:     b.Total,
+			Employed:  b.Employed,
+			Employers: nonNil(employers[b.Batch]),
+			Locations: nonNil(locations[b.Batch]),
+		})
+	}
+
+	return stats, nil
+}
+
+const employedSum = "COALESCE(SUM(CASE WHEN TRIM(organization) <> '' THEN 1 ELSE 0 END), 0) AS employed"
+
+// ranking counts alumni per distinct value of column, most common first.
+func (r *alumniRepository) ranking(ctx context.Context, filter map[string]interface{}, column string, top int) ([]domain.AlumniCount, error) {
+	var rows []domain.AlumniCount
+	err := r.query(ctx, filter).
+		Select("MIN(TRIM(" + column + ")) AS name, COUNT(*) AS count").
+		Where("TRIM(" + column + ") <> ''").
+		Group("LOWER(TRIM(" + column + "))").
+		Order("count DESC, name ASC").
+		Limit(top).
+		Scan(&rows).Error
+	return nonNil(rows), err
+}
+
+// batchRanking is ranking per batch, keeping the top entries of each.
+func (r *alumniRepository) batchRanking(ctx context.Context, filter map[string]interface{}, column string, top int) (map[string][]domain.AlumniCount, error) {
+	var rows []struct {
+		Batch string
+		Name  string
+		Count int64
+	}
+	err := r.query(ctx, filter).
+		Select("batch, MIN(TRIM(" + column + ")) AS name, COUNT(*) AS count").
+		Where("TRIM(" + column + ") <> ''").
+		Group("batch, LOWER(TRIM(" + column + "))").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		if rows[i].Count != rows[j].Count {
+			return rows[i].Count > rows[j].Count
+		}
+		return rows[i].Name < rows[j].Name
+	})
+	out := map[string][]domain.AlumniCount{}
+	for _, row := range rows {
+		if len(out[row.Batch]) < top {
+			out[row.Batch] = append(out[row.Batch], domain.AlumniCount{Name: row.Name, Count: row.Count})
+		}
+	}
+	return out, nil
+}
+
+func nonNil(rows []domain.AlumniCount) []domain.AlumniCount {
+	if rows == nil {
+		return []domain.AlumniCount{}
+	}
+	return rows
+}
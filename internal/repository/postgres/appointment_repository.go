@@ -0,0 +1,75 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type appointmentRepository struct {
+	domain.Repository[domain.Appointment]
+	db *gorm.DB
+}
+
+func NewAppointmentRepository(db *gorm.DB) domain.AppointmentRepository {
+	return &appointmentRepository{
+		Repository: NewGormRepository[domain.Appointment](db),
+		db:         db,
+	}
+}
+
+var activeAppointment = []string{domain.AppointmentStatusPending, domain.AppointmentStatusApproved}
+
+func (r *appointmentRepository) LockTeacher(ctx context.Context, teacherID uuid.UUID) error {
+	var teacher domain.Teacher
+	return conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Take(&teacher, "id = ?", teacherID).Error
+}
+
+func (r *appointmentRepository) Overlapping(ctx context.Context, teacherID, studentID uuid.UUID, start, end time.Time) ([]domain.Appointment, error) {
+	var appointments []domain.Appointment
+	err := conn(ctx, r.db).
+		Where("(teacher_id = ? OR student_id = ?) AND status IN ? AND starts_at < ? AND ends_at > ?", teacherID, studentID, activeAppointment, end, start).
+		Order("starts_at ASC").
+		Find(&appointments).Error
+	return appointments, err
+}
+
+func (r *appointmentRepository) Booked(ctx context.Context, teacherID uuid.UUID, from, to time.Time) ([]domain.Appointment, error) {
+	var appointments []domain.Appointment
+	err := conn(ctx, r.db).
+		Where("teacher_id = ? AND status IN ? AND starts_at >= ? AND starts_at < ?", teacherID, activeAppointment, from, to).
+		Order("starts_at ASC").
+		Find(&appointments).Error
+	return appointments, err
+}
+
+func (r *appointmentRepository) Involving(ctx context.Context, userID uuid.UUID, status string, limit, offset int) ([]domain.Appointment, int64, error) {
+	var appointments []domain.Appointment
+	var count int64
+
+	db := conn(ctx, r.db).Model(&domain.Appointment{}).
+		Where("student_user_id = ? OR teacher_id IN (?)", userID,
+			conn(ctx, r.db).Model(&domain.Teacher{}).Select("id").Where("user_id = ?", userID))
+	if status != "" {
+		db = db.Where("status = ?", status)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("starts_at ASC").Limit(limit).Offset(offset).Find(&appointments).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return appointments, count, nil
+}
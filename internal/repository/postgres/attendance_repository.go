@@ -0,0 +1,86 @@
+package postgres
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type attendanceRepository struct {
+	domain.Repository[domain.AttendanceSession]
+	db *gorm.DB
+}
+
+func NewAttendanceRepository(db *gorm.DB) domain.AttendanceRepository {
+	return &attendanceRepository{
+		Repository: NewGormRepository[domain.AttendanceSession](db),
+		db:         db,
+	}
+}
+
+func (r *attendanceRepository) Find(ctx context.Context, offeringID uuid.UUID, date string) (*domain.AttendanceSession, error) {
+	var session domain.AttendanceSession
+	err := conn(ctx, r.db).
+		Where("offering_id = ? AND date = ?", offeringID, date).
+		Preload("Offering.Course").
+		Preload("Records.Student").
+		First(&session).Error
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *attendanceRepository) Sessions(ctx context.Context, offeringID uuid.UUID, limit, offset int) ([]domain.AttendanceSession, int64, error) {
+	var sessions []domain.AttendanceSession
+	var count int64
+
+	db := conn(ctx, r.db).Model(&domain.AttendanceSession{}).Where("offering_id = ?", offeringID)
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("date DESC").Limit(limit).Offset(offset).Find(&sessions).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return sessions, count, nil
+}
+
+func (r *attendanceRepository) SaveRecords(ctx context.Context, records []domain.AttendanceRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "session_id"}, {Name: "student_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"status", "updated_at", "updated_by_id"}),
+	}).Create(&records).Error
+}
+
+func (r *attendanceRepository) Tallies(ctx context.Context, offeringID, studentID *uuid.UUID) ([]domain.AttendanceTally, error) {
+	var tallies []domain.AttendanceTally
+
+	db := conn(ctx, r.db).
+		Table("attendance_records AS r").
+		Select(`s.offering_id, r.student_id,
+			SUM(CASE WHEN r.status = ? THEN 1 ELSE 0 END) AS present,
+			SUM(CASE WHEN r.status = ? THEN 1 ELSE 0 END) AS late,
+			SUM(CASE WHEN r.status = ? THEN 1 ELSE 0 END) AS absent`,
+			domain.AttendancePresent, domain.AttendanceLate, domain.AttendanceAbsent).
+		Joins("JOIN attendance_sessions AS s ON s.id = r.session_id AND s.deleted_at IS NULL").
+		Where("r.deleted_at IS NULL")
+	if offeringID != nil {
+		db = db.Where("s.offering_id = ?", *offeringID)
+	}
+	if studentID != nil {
+		db = db.Where("r.student_id = ?", *studentID)
+	}
+
+	err := db.Group("s.offering_id, r.student_id").Scan(&tallies).Error
+	return tallies, err
+}
@@ -0,0 +1,60 @@
+package postgres
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type auditLogRepository struct {
+	domain.Repository[domain.AuditLog]
+	db *gorm.DB
+}
+
+func NewAuditLogRepository(db *gorm.DB) domain.AuditLogRepository {
+	return &auditLogRepository{
+		Repository: NewGormRepository[domain.AuditLog](db),
+		db:         db,
+	}
+}
+
+func (r *auditLogRepository) CreateBatch(ctx context.Context, logs []domain.AuditLog) error {
+	if len(logs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).CreateInBatches(logs, 100).Error
+}
+
+func (r *auditLogRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.AuditLog, int64, error) {
+	var entities []domain.AuditLog
+	var count int64
+
+	db := r.db.WithContext(ctx).Model(&domain.AuditLog{})
+
+	for key, value := range filter {
+		switch key {
+		case "from":
+			db = db.Where("timestamp >= ?", value)
+		case "to":
+			db = db.Where("timestamp <= ?", value)
+		case "search":
+			searchVal := "%" + value.(string) + "%"
+			db = db.Where("description ILIKE ?", searchVal)
+		default:
+			db = db.Where(key+" = ?", value)
+		}
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("timestamp DESC").Limit(limit).Offset(offset).Find(&entities).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entities, count, nil
+}
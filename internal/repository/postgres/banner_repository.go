@@ -21,11 +21,9 @@ func NewBannerRepository(db *gorm.DB) domain.Repository[domain.Banner] {
 	}
 }
 
-func (r *bannerRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Banner, int64, error) {
-	var entities []domain.Banner
-	var count int64
-
-	db := r.db.WithContext(ctx).Model(&domain.Banner{}).Preload("Targets")
+// query applies the targeting rules shared by GetAll and Stream.
+func (r *bannerRepository) query(ctx context.Context, filter map[string]interface{}) *gorm.DB {
+	db := r.db.WithContext(ctx).Model(&domain.Banner{})
 
 	// Default to targeting mode unless explicitly told otherwise
 	mode, hasMode := filter["mode"]
@@ -76,6 +74,15 @@ func (r *bannerRepository) GetAll(ctx context.Context, filter map[string]interfa
 		db = db.Where(filter)
 	}
 
+	return db
+}
+
+func (r *bannerRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Banner, int64, error) {
+	var entities []domain.Banner
+	var count int64
+
+	db := r.query(ctx, filter).Preload("Targets")
+
 	if err := db.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
@@ -87,3 +94,7 @@ func (r *bannerRepository) GetAll(ctx context.Context, filter map[string]interfa
 
 	return entities, count, nil
 }
+
+func (r *bannerRepository) Stream(ctx context.Context, filter map[string]interface{}, fn func(*domain.Banner) error) error {
+	return streamRows(r.query(ctx, filter).Order("priority desc"), fn)
+}
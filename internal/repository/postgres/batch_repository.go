@@ -0,0 +1,31 @@
+package postgres
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type batchRepository struct {
+	domain.Repository[domain.Batch]
+	db *gorm.DB
+}
+
+func NewBatchRepository(db *gorm.DB) domain.BatchRepository {
+	return &batchRepository{
+		Repository: NewGormRepository[domain.Batch](db),
+		db:         db,
+	}
+}
+
+func (r *batchRepository) SetSemesters(ctx context.Context, batchID uuid.UUID, semesterIDs []uuid.UUID) error {
+	semesters := make([]domain.Semester, len(semesterIDs))
+	for i, id := range semesterIDs {
+		semesters[i].ID = id
+	}
+	batch := domain.Batch{Base: domain.Base{ID: batchID}}
+	return conn(ctx, r.db).Model(&batch).Omit("Semesters.*").Association("Semesters").Replace(semesters)
+}
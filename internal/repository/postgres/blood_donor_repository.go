@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type bloodDonorRepository struct {
+	domain.Repository[domain.BloodDonor]
+	db *gorm.DB
+}
+
+func NewBloodDonorRepository(db *gorm.DB) domain.BloodDonorRepository {
+	return &bloodDonorRepository{
+		Repository: NewGormRepository[domain.BloodDonor](db),
+		db:         db,
+	}
+}
+
+func (r *bloodDonorRepository) Eligible(ctx context.Context, filter map[string]interface{}, cutoff time.Time, limit, offset int) ([]domain.BloodDonor, int64, error) {
+	var donors []domain.BloodDonor
+	var count int64
+
+	db := conn(ctx, r.db).Model(&domain.BloodDonor{}).
+		Where("is_available = ? AND (last_donated_at IS NULL OR last_donated_at <= ?)", true, cutoff)
+	for key, value := range filter {
+		db = db.Where(key+" = ?", value)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("last_donated_at ASC NULLS FIRST, created_at ASC").Limit(limit).Offset(offset).Find(&donors).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return donors, count, nil
+}
+
+func (r *bloodDonorRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return transaction(ctx, r.db, func(ctx context.Context) error {
+		if err := conn(ctx, r.db).Unscoped().Where("donor_id = ?", id).Delete(&domain.BloodRequestMatch{}).Error; err != nil {
+			return err
+		}
+		return conn(ctx, r.db).Unscoped().Delete(&domain.BloodDonor{}, "id = ?", id).Error
+	})
+}
@@ -0,0 +1,41 @@
+package postgres
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type calendarEventRepository struct {
+	domain.Repository[domain.CalendarEvent]
+	db *gorm.DB
+}
+
+func NewCalendarEventRepository(db *gorm.DB) domain.CalendarEventRepository {
+	return &calendarEventRepository{
+		Repository: NewGormRepository[domain.CalendarEvent](db),
+		db:         db,
+	}
+}
+
+func (r *calendarEventRepository) Range(ctx context.Context, universityID uuid.UUID, departmentID *uuid.UUID, from, until string) ([]domain.CalendarEvent, error) {
+	var events []domain.CalendarEvent
+
+	db := conn(ctx, r.db).Where("university_id = ?", universityID)
+	if departmentID != nil {
+		db = db.Where("(department_id IS NULL OR department_id = ?)", *departmentID)
+	} else {
+		db = db.Where("department_id IS NULL")
+	}
+
+	// Dates are YYYY-MM-DD, so they compare as strings
+	err := db.
+		Where("start_date <= ?", until).
+		Where("(end_date >= ? OR (recurrence <> '' AND (repeat_until = '' OR repeat_until IS NULL OR repeat_until >= ?)))", from, from).
+		Order("start_date ASC").
+		Find(&events).Error
+	return events, err
+}
@@ -19,10 +19,8 @@ func NewChapterRepository(db *gorm.DB) domain.Repository[domain.Chapter] {
 	}
 }
 
-func (r *chapterRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Chapter, int64, error) {
-	var entities []domain.Chapter
-	var count int64
-
+// query applies the filters shared by GetAll and Stream.
+func (r *chapterRepository) query(ctx context.Context, filter map[string]interface{}) *gorm.DB {
 	db := r.db.WithContext(ctx).Model(&domain.Chapter{})
 
 	// Handle Batch Filtering
@@ -56,6 +54,15 @@ func (r *chapterRepository) GetAll(ctx context.Context, filter map[string]interf
 		}
 	}
 
+	return db
+}
+
+func (r *chapterRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Chapter, int64, error) {
+	var entities []domain.Chapter
+	var count int64
+
+	db := r.query(ctx, filter)
+
 	if err := db.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
@@ -67,3 +74,7 @@ func (r *chapterRepository) GetAll(ctx context.Context, filter map[string]interf
 
 	return entities, count, nil
 }
+
+func (r *chapterRepository) Stream(ctx context.Context, filter map[string]interface{}, fn func(*domain.Chapter) error) error {
+	return streamRows(r.query(ctx, filter).Order("chapter_no asc"), fn)
+}
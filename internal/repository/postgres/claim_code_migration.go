@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/auth"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// migrateLegacyClaimCodes converts verification codes stored in plain text,
+// before codes were hashed, to the HMAC form usecase.ClaimCodes checks
+// (keyed with secret) and starts their expiry now, so profiles that have
+// not been claimed yet keep the code printed for them. It runs before
+// AutoMigrate creates the unique claim code index: plain codes two profiles
+// share, or whose hash a newer code already holds, are cleared instead and
+// must be reissued. Legacy rows are the ones with a code but no expiry.
+func migrateLegacyClaimCodes(db *gorm.DB, secret string, ttl time.Duration) error {
+	expires := time.Now().Add(ttl)
+	for _, model := range []interface{}{&domain.Student{}, &domain.Teacher{}, &domain.Staff{}, &domain.Alumni{}} {
+		m := db.Migrator()
+		if !m.HasTable(model) {
+			continue
+		}
+		if !m.HasColumn(model, "CodeExpiresAt") {
+			if err := m.AddColumn(model, "CodeExpiresAt"); err != nil {
+				return err
+			}
+		}
+		if err := widenVerificationCode(db, model); err != nil {
+			return err
+		}
+
+		legacy := db.Model(model).Unscoped().Where("verification_code <> '' AND code_expires_at IS NULL")
+		if err := legacy.Session(&gorm.Session{}).Where("is_claimed = ? OR user_id IS NOT NULL", true).
+			Update("verification_code", "").Error; err != nil {
+			return err
+		}
+
+		var rows []struct {
+			ID               uuid.UUID
+			VerificationCode string
+		}
+		if err := legacy.Session(&gorm.Session{}).Select("id", "verification_code").Find(&rows).Error; err != nil {
+			return err
+		}
+		hashes := make(map[string]int, len(rows))
+		for i := range rows {
+			rows[i].VerificationCode = auth.HashCode(secret, rows[i].VerificationCode)
+			hashes[rows[i].VerificationCode]++
+		}
+
+		hashed, cleared := 0, 0
+		for _, row := range rows {
+			var held int64
+			if err := db.Model(model).Unscoped().Where("verification_code = ?", row.VerificationCode).Count(&held).Error; err != nil {
+				return err
+			}
+			update := map[string]interface{}{"verification_code": row.VerificationCode, "code_expires_at": expires}
+			if hashes[row.VerificationCode] > 1 || held > 0 {
+				update = map[string]interface{}{"verification_code": ""}
+				cleared++
+			} else {
+				hashed++
+			}
+			if err := db.Model(model).Unscoped().Where("id = ?", row.ID).Updates(update).Error; err != nil {
+				return err
+			}
+		}
+		if len(rows) > 0 {
+			log.Printf("[MIGRATION] %T: hashed %d legacy claim codes, cleared %d duplicates to reissue", model, hashed, cleared)
+		}
+	}
+	return nil
+}
+
+// widenVerificationCode makes room for hashed codes in tables created when
+// verification_code held the plain code.
+func widenVerificationCode(db *gorm.DB, model interface{}) error {
+	columns, err := db.Migrator().ColumnTypes(model)
+	if err != nil {
+		return err
+	}
+	for _, col := range columns {
+		if col.Name() != "verification_code" {
+			continue
+		}
+		if size, ok := col.Length(); ok && size < 64 {
+			if err := db.Migrator().AlterColumn(model, "VerificationCode"); err != nil {
+				return fmt.Errorf("widen verification_code: %w", err)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type claimRepository[T any] struct {
+	db *gorm.DB
+}
+
+// NewClaimRepository serves code lookups and claims for a Claimable profile
+// type with verification_code, code_expires_at and is_claimed columns.
+func NewClaimRepository[T any](db *gorm.DB) domain.ClaimRepository[T] {
+	return &claimRepository[T]{db: db}
+}
+
+func (r *claimRepository[T]) FindByClaimCode(ctx context.Context, hash string, now time.Time) (*T, error) {
+	var profile T
+	err := conn(ctx, r.db).
+		Where("verification_code = ? AND is_claimed = ?", hash, false).
+		Where("code_expires_at > ?", now).
+		Take(&profile).Error
+	if err != nil {
+		return nil, err
+	}
+	return &profile, nil
+}
+
+// Claim is a single conditional UPDATE, so of two concurrent claims for the
+// same code exactly one matches the row.
+func (r *claimRepository[T]) Claim(ctx context.Context, id uuid.UUID, hash string, userID uuid.UUID, now time.Time) error {
+	res := conn(ctx, r.db).Model(new(T)).
+		Where("id = ? AND verification_code = ? AND is_claimed = ?", id, hash, false).
+		Where("code_expires_at > ?", now).
+		Updates(map[string]interface{}{
+			"user_id":           userID,
+			"is_claimed":        true,
+			"claimed_at":        now,
+			"verification_code": "",
+			"code_expires_at":   nil,
+		})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return domain.ErrAlreadyClaimed
+	}
+	return nil
+}
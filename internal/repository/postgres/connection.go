@@ -53,8 +53,18 @@ func NewConnection(cfg *config.Config) (*gorm.DB, error) {
 	return db, nil
 }
 
-func RunMigrations(db *gorm.DB) error {
+func RunMigrations(db *gorm.DB, cfg *config.Config) error {
 	log.Println("[MIGRATION] Starting database migrations...")
+
+	// Hash plain-text claim codes before the unique claim code index exists;
+	// the key is the one the router gives usecase.ClaimCodes
+	if err := migrateLegacyClaimCodes(db, cfg.JWTSecret, time.Duration(cfg.ClaimCodeTTL)*time.Hour); err != nil {
+		return fmt.Errorf("claim code migration failed: %w", err)
+	}
+	// Likewise de-duplicate slugs before the unique scoped slug indexes
+	if err := migrateDuplicateSlugs(db); err != nil {
+		return fmt.Errorf("slug migration failed: %w", err)
+	}
 	
 	// AutoMigrate all models
 	err := db.AutoMigrate(
@@ -88,6 +98,24 @@ func RunMigrations(db *gorm.DB) error {
 		&domain.BannerTarget{},
 		&domain.Chapter{},
 		&domain.EmergencyContact{},
+		&domain.Revision{},
+		&domain.IdempotencyKey{},
+		&domain.SlugAlias{},
+		&domain.WebhookSubscription{},
+		&domain.WebhookDelivery{},
+		&domain.OutboxEvent{},
+		&domain.BloodDonor{},
+		&domain.BloodRequest{},
+		&domain.BloodRequestMatch{},
+		&domain.OfficeHour{},
+		&domain.Appointment{},
+		&domain.CourseOffering{},
+		&domain.RoutineEntry{},
+		&domain.Exam{},
+		&domain.ExamReminder{},
+		&domain.CalendarEvent{},
+		&domain.AttendanceSession{},
+		&domain.AttendanceRecord{},
 	)
 	if err != nil {
 		return fmt.Errorf("AutoMigrate failed: %w", err)
@@ -0,0 +1,76 @@
+package postgres
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type courseOfferingRepository struct {
+	domain.Repository[domain.CourseOffering]
+	db *gorm.DB
+}
+
+func NewCourseOfferingRepository(db *gorm.DB) domain.CourseOfferingRepository {
+	return &courseOfferingRepository{
+		Repository: NewGormRepository[domain.CourseOffering](db),
+		db:         db,
+	}
+}
+
+// query applies the filters shared by GetAll and Stream.
+func (r *courseOfferingRepository) query(ctx context.Context, filter map[string]interface{}) *gorm.DB {
+	db := conn(ctx, r.db).Model(&domain.CourseOffering{})
+
+	for key, value := range filter {
+		switch key {
+		case "preload":
+		case "teacher_id":
+			db = db.Where("course_offerings.id IN (?)", r.db.Table("course_offering_teachers").
+				Select("course_offering_id").Where("teacher_id = ?", value))
+		case "search":
+			searchVal := "%" + value.(string) + "%"
+			db = db.Where("course_offerings.course_id IN (?)", r.db.Model(&domain.Course{}).
+				Select("id").Where("course_title ILIKE ? OR course_code ILIKE ?", searchVal, searchVal))
+		default:
+			db = db.Where("course_offerings."+key+" = ?", value)
+		}
+	}
+
+	return db
+}
+
+func (r *courseOfferingRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.CourseOffering, int64, error) {
+	var entities []domain.CourseOffering
+	var count int64
+
+	db := r.query(ctx, filter)
+
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Preload("Course").Preload("Semester").Preload("Session").Preload("Teachers").
+		Order("course_offerings.created_at DESC").Limit(limit).Offset(offset).Find(&entities).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return entities, count, nil
+}
+
+func (r *courseOfferingRepository) Stream(ctx context.Context, filter map[string]interface{}, fn func(*domain.CourseOffering) error) error {
+	return streamRows(r.query(ctx, filter), fn)
+}
+
+func (r *courseOfferingRepository) SetTeachers(ctx context.Context, offeringID uuid.UUID, teacherIDs []uuid.UUID) error {
+	teachers := make([]domain.Teacher, len(teacherIDs))
+	for i, id := range teacherIDs {
+		teachers[i].ID = id
+	}
+	offering := domain.CourseOffering{Base: domain.Base{ID: offeringID}}
+	return conn(ctx, r.db).Model(&offering).Omit("Teachers.*").Association("Teachers").Replace(teachers)
+}
@@ -19,10 +19,8 @@ func NewCourseRepository(db *gorm.DB) domain.Repository[domain.Course] {
 	}
 }
 
-func (r *courseRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Course, int64, error) {
-	var entities []domain.Course
-	var count int64
-
+// query applies the filters shared by GetAll and Stream.
+func (r *courseRepository) query(ctx context.Context, filter map[string]interface{}) *gorm.DB {
 	db := r.db.WithContext(ctx).Model(&domain.Course{})
 
 	// Handle Batch Filtering
@@ -58,6 +56,15 @@ func (r *courseRepository) GetAll(ctx context.Context, filter map[string]interfa
 		}
 	}
 
+	return db
+}
+
+func (r *courseRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Course, int64, error) {
+	var entities []domain.Course
+	var count int64
+
+	db := r.query(ctx, filter)
+
 	if err := db.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
@@ -69,3 +76,7 @@ func (r *courseRepository) GetAll(ctx context.Context, filter map[string]interfa
 
 	return entities, count, nil
 }
+
+func (r *courseRepository) Stream(ctx context.Context, filter map[string]interface{}, fn func(*domain.Course) error) error {
+	return streamRows(r.query(ctx, filter), fn)
+}
@@ -0,0 +1,67 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type crRepository struct {
+	domain.Repository[domain.CR]
+	db *gorm.DB
+}
+
+func NewCRRepository(db *gorm.DB) domain.CRRepository {
+	return &crRepository{
+		Repository: NewGormRepository[domain.CR](db),
+		db:         db,
+	}
+}
+
+func (r *crRepository) LockBatch(ctx context.Context, batchID uuid.UUID) error {
+	var batch domain.Batch
+	return conn(ctx, r.db).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Take(&batch, "id = ?", batchID).Error
+}
+
+func (r *crRepository) Current(ctx context.Context, batchID uuid.UUID) ([]domain.CR, error) {
+	var crs []domain.CR
+	err := conn(ctx, r.db).
+		Where("batch_id = ? AND is_current = ?", batchID, true).
+		Order("term_start ASC").
+		Find(&crs).Error
+	return crs, err
+}
+
+func (r *crRepository) History(ctx context.Context, batchID uuid.UUID) ([]domain.CR, error) {
+	var crs []domain.CR
+	err := conn(ctx, r.db).
+		Where("batch_id = ?", batchID).
+		Order("is_current DESC, term_start DESC NULLS LAST, created_at DESC").
+		Find(&crs).Error
+	return crs, err
+}
+
+func (r *crRepository) DueExpiry(ctx context.Context, now time.Time) ([]domain.CR, error) {
+	var crs []domain.CR
+	err := conn(ctx, r.db).
+		Where("is_current = ? AND term_end IS NOT NULL AND term_end <= ?", true, now).
+		Order("term_end ASC").
+		Find(&crs).Error
+	return crs, err
+}
+
+func (r *crRepository) IsCurrent(ctx context.Context, studentID uuid.UUID) (bool, error) {
+	var count int64
+	err := conn(ctx, r.db).Model(&domain.CR{}).
+		Where("target_student_id = ? AND is_current = ?", studentID, true).
+		Count(&count).Error
+	return count > 0, err
+}
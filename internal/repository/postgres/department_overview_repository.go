@@ -0,0 +1,104 @@
+package postgres
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type departmentOverviewRepository struct {
+	db *gorm.DB
+}
+
+func NewDepartmentOverviewRepository(db *gorm.DB) domain.DepartmentOverviewRepository {
+	return &departmentOverviewRepository{db: db}
+}
+
+func (r *departmentOverviewRepository) Department(ctx context.Context, id uuid.UUID) (*domain.Department, error) {
+	var dept domain.Department
+	if err := r.db.WithContext(ctx).Preload("University").First(&dept, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &dept, nil
+}
+
+func (r *departmentOverviewRepository) ActiveBatches(ctx context.Context, departmentID uuid.UUID) ([]domain.Batch, error) {
+	var batches []domain.Batch
+	err := r.db.WithContext(ctx).
+		Where("department_id = ? AND is_studying = ?", departmentID, true).
+		Order("name").
+		Find(&batches).Error
+	return batches, err
+}
+
+func (r *departmentOverviewRepository) StudentCounts(ctx context.Context, batchIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64, len(batchIDs))
+	if len(batchIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		BatchID uuid.UUID
+		Count   int64
+	}
+	if err := r.db.WithContext(ctx).Model(&domain.Student{}).
+		Select("batch_id, COUNT(*) AS count").
+		Where("batch_id IN ?", batchIDs).
+		Group("batch_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.BatchID] = row.Count
+	}
+	return counts, nil
+}
+
+// Claim codes are left out: the overview is shown to everyone.
+func (r *departmentOverviewRepository) Teachers(ctx context.Context, departmentID uuid.UUID) ([]domain.Teacher, error) {
+	var teachers []domain.Teacher
+	err := r.db.WithContext(ctx).
+		Omit("verification_code").
+		Where("department_id = ? AND (is_present = ? OR is_chairman = ?)", departmentID, true, true).
+		Order("weight, name").
+		Find(&teachers).Error
+	return teachers, err
+}
+
+func (r *departmentOverviewRepository) Staff(ctx context.Context, departmentID uuid.UUID) ([]domain.Staff, error) {
+	var staff []domain.Staff
+	err := r.db.WithContext(ctx).
+		Omit("verification_code").
+		Where("department_id = ?", departmentID).
+		Order("serial, name").
+		Find(&staff).Error
+	return staff, err
+}
+
+func (r *departmentOverviewRepository) CurrentCRs(ctx context.Context, departmentID uuid.UUID) ([]domain.CR, error) {
+	var crs []domain.CR
+	err := r.db.WithContext(ctx).
+		Where("department_id = ? AND is_current = ?", departmentID, true).
+		Order("batch, name").
+		Find(&crs).Error
+	return crs, err
+}
+
+func (r *departmentOverviewRepository) CountCourses(ctx context.Context, departmentID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Course{}).
+		Where("department_id = ?", departmentID).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *departmentOverviewRepository) CountResources(ctx context.Context, departmentID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.Resource{}).
+		Where("department_id = ? AND status = ?", departmentID, domain.ResourceStatusPublished).
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"errors"
+	"strings"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// uniqueViolation is the SQLSTATE of unique index violations.
+const uniqueViolation = "23505"
+
+// translate maps unique violations of the indexes the usecases retry on to
+// their domain errors and returns other errors unchanged.
+func translate(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) || pgErr.Code != uniqueViolation {
+		return err
+	}
+	switch {
+	case strings.HasSuffix(pgErr.ConstraintName, "_claim_code"):
+		return domain.ErrClaimCodeTaken
+	case strings.HasSuffix(pgErr.ConstraintName, "_slug"):
+		return domain.ErrSlugTaken
+	}
+	return err
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type examRepository struct {
+	domain.Repository[domain.Exam]
+	db *gorm.DB
+}
+
+func NewExamRepository(db *gorm.DB) domain.ExamRepository {
+	return &examRepository{
+		Repository: NewGormRepository[domain.Exam](db),
+		db:         db,
+	}
+}
+
+func (r *examRepository) SetChapters(ctx context.Context, examID uuid.UUID, chapterIDs []uuid.UUID) error {
+	chapters := make([]domain.Chapter, len(chapterIDs))
+	for i, id := range chapterIDs {
+		chapters[i].ID = id
+	}
+	exam := domain.Exam{Base: domain.Base{ID: examID}}
+	return conn(ctx, r.db).Model(&exam).Omit("Chapters.*").Association("Chapters").Replace(chapters)
+}
+
+func (r *examRepository) Schedule(ctx context.Context, batchID uuid.UUID, from time.Time, examType string, limit, offset int) ([]domain.Exam, int64, error) {
+	var exams []domain.Exam
+	var count int64
+
+	db := conn(ctx, r.db).Model(&domain.Exam{}).Where("batch_id = ? AND ends_at > ?", batchID, from)
+	if examType != "" {
+		db = db.Where("type = ?", examType)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Preload("Course").Preload("Chapters").Order("starts_at ASC").Limit(limit).Offset(offset).Find(&exams).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return exams, count, nil
+}
+
+func (r *examRepository) Starting(ctx context.Context, from, to time.Time) ([]domain.Exam, error) {
+	var exams []domain.Exam
+	err := conn(ctx, r.db).
+		Where("starts_at > ? AND starts_at <= ?", from, to).
+		Preload("Course").
+		Order("starts_at ASC").
+		Find(&exams).Error
+	return exams, err
+}
+
+func (r *examRepository) Reminded(ctx context.Context, examID uuid.UUID) ([]int, error) {
+	var leads []int
+	err := conn(ctx, r.db).Model(&domain.ExamReminder{}).Where("exam_id = ?", examID).Pluck("lead_hours", &leads).Error
+	return leads, err
+}
+
+func (r *examRepository) AddReminders(ctx context.Context, reminders []domain.ExamReminder) (int64, error) {
+	if len(reminders) == 0 {
+		return 0, nil
+	}
+	result := conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&reminders)
+	return result.RowsAffected, result.Error
+}
+
+func (r *examRepository) ClearReminders(ctx context.Context, examID uuid.UUID) error {
+	return conn(ctx, r.db).Unscoped().Where("exam_id = ?", examID).Delete(&domain.ExamReminder{}).Error
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type idempotencyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyRepository(db *gorm.DB) domain.IdempotencyRepository {
+	return &idempotencyRepository{db: db}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, rec *domain.IdempotencyKey) (*domain.IdempotencyKey, bool, error) {
+	if rec.ID == uuid.Nil {
+		rec.ID = uuid.New()
+	}
+
+	db := r.db.WithContext(ctx)
+
+	// Clear an expired record for this key so it can be reused; for a
+	// processing record this means its lease ran out.
+	if err := db.Where("client_id = ? AND key = ? AND expires_at < ?", rec.ClientID, rec.Key, time.Now()).
+		Delete(&domain.IdempotencyKey{}).Error; err != nil {
+		return nil, false, err
+	}
+
+	// The unique (client_id, key) index makes concurrent reservations race-safe.
+	res := db.Clauses(clause.OnConflict{DoNothing: true}).Create(rec)
+	if res.Error != nil {
+		return nil, false, res.Error
+	}
+	if res.RowsAffected == 1 {
+		return nil, true, nil
+	}
+
+	var existing domain.IdempotencyKey
+	err := db.Where("client_id = ? AND key = ?", rec.ClientID, rec.Key).First(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// Released between our insert and read; let the caller retry.
+		return r.Reserve(ctx, rec)
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return &existing, false, nil
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, id uuid.UUID, code int, contentType string, body []byte, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.IdempotencyKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status":        domain.IdempotencyCompleted,
+			"response_code": code,
+			"content_type":  contentType,
+			"response_body": body,
+			"expires_at":    expiresAt,
+		}).Error
+}
+
+func (r *idempotencyRepository) Renew(ctx context.Context, id uuid.UUID, expiresAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&domain.IdempotencyKey{}).
+		Where("id = ? AND status = ?", id, domain.IdempotencyProcessing).
+		Update("expires_at", expiresAt).Error
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&domain.IdempotencyKey{}, "id = ?", id).Error
+}
+
+func (r *idempotencyRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	res := r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&domain.IdempotencyKey{})
+	return res.RowsAffected, res.Error
+}
@@ -0,0 +1,29 @@
+package postgres
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationRepository struct {
+	domain.Repository[domain.Notification]
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) domain.NotificationRepository {
+	return &notificationRepository{
+		Repository: NewGormRepository[domain.Notification](db),
+		db:         db,
+	}
+}
+
+func (r *notificationRepository) CreateOnce(ctx context.Context, n *domain.Notification) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "event_id"}, {Name: "user_id"}},
+		DoNothing: true,
+	}).Create(n).Error
+}
@@ -0,0 +1,83 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) domain.OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+func (r *outboxRepository) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return transaction(ctx, r.db, fn)
+}
+
+func (r *outboxRepository) AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	afterCommit(ctx, fn)
+}
+
+func (r *outboxRepository) Add(ctx context.Context, events ...domain.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	rows := make([]*domain.OutboxEvent, 0, len(events))
+	for _, e := range events {
+		row, err := domain.NewOutboxEvent(e)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+	return conn(ctx, r.db).Create(rows).Error
+}
+
+func (r *outboxRepository) Claim(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error) {
+	var events []domain.OutboxEvent
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.OutboxPending, now).
+			Order("next_attempt_at").
+			Limit(limit).
+			Find(&events).Error; err != nil {
+			return err
+		}
+		if len(events) == 0 {
+			return nil
+		}
+
+		// Push the claimed rows out of reach until the lease ends; if this
+		// relay dies mid-dispatch another one picks them up then.
+		until := now.Add(lease)
+		ids := make([]uuid.UUID, len(events))
+		for i := range events {
+			ids[i] = events[i].ID
+			events[i].NextAttemptAt = until
+		}
+		return tx.Model(&domain.OutboxEvent{}).Where("id IN ?", ids).Update("next_attempt_at", until).Error
+	})
+	return events, err
+}
+
+func (r *outboxRepository) Save(ctx context.Context, e *domain.OutboxEvent) error {
+	return r.db.WithContext(ctx).Save(e).Error
+}
+
+func (r *outboxRepository) DeleteProcessed(ctx context.Context, before time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Where("status = ? AND processed_at < ?", domain.OutboxDone, before).
+		Delete(&domain.OutboxEvent{})
+	return res.RowsAffected, res.Error
+}
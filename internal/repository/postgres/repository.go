@@ -19,25 +19,23 @@ func NewGormRepository[T any](db *gorm.DB) domain.Repository[T] {
 }
 
 func (r *GormRepository[T]) Create(ctx context.Context, entity *T) error {
-	return r.DB.WithContext(ctx).Create(entity).Error
+	return translate(conn(ctx, r.DB).Create(entity).Error)
 }
 
 func (r *GormRepository[T]) GetByID(ctx context.Context, id uuid.UUID) (*T, error) {
 	var entity T
 	// Assumes the entity struct has a field named "ID" or similar mapping.
 	// GORM handles this well if the ID is the primary key.
-	if err := r.DB.WithContext(ctx).Preload(clause.Associations).First(&entity, "id = ?", id).Error; err != nil {
+	if err := conn(ctx, r.DB).Preload(clause.Associations).First(&entity, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &entity, nil
 }
 
-func (r *GormRepository[T]) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]T, int64, error) {
-	var entities []T
-	var count int64
-
+// query applies the filter map and reports whether associations were requested.
+func (r *GormRepository[T]) query(ctx context.Context, filter map[string]interface{}) (*gorm.DB, bool) {
 	// Use a session to avoid polluting the main DB instance
-	db := r.DB.WithContext(ctx).Model(new(T))
+	db := conn(ctx, r.DB).Model(new(T))
 
 	// Apply filters
 	shouldPreload := false
@@ -54,6 +52,15 @@ func (r *GormRepository[T]) GetAll(ctx context.Context, filter map[string]interf
 		}
 	}
 
+	return db, shouldPreload
+}
+
+func (r *GormRepository[T]) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]T, int64, error) {
+	var entities []T
+	var count int64
+
+	db, shouldPreload := r.query(ctx, filter)
+
 	if err := db.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
@@ -70,12 +77,39 @@ func (r *GormRepository[T]) GetAll(ctx context.Context, filter map[string]interf
 	return entities, count, nil
 }
 
+// Stream walks every row matching filter with a DB cursor (no limit).
+func (r *GormRepository[T]) Stream(ctx context.Context, filter map[string]interface{}, fn func(*T) error) error {
+	db, _ := r.query(ctx, filter)
+	return streamRows(db, fn)
+}
+
 func (r *GormRepository[T]) Update(ctx context.Context, entity *T) error {
-	return r.DB.WithContext(ctx).Save(entity).Error
+	return translate(conn(ctx, r.DB).Save(entity).Error)
 }
 
 func (r *GormRepository[T]) Delete(ctx context.Context, id uuid.UUID) error {
 	// Hard delete or Soft delete? GORM defaults to soft delete if DeletedAt is present.
 	// We want soft delete as per our Base struct.
-	return r.DB.WithContext(ctx).Delete(new(T), "id = ?", id).Error
+	return conn(ctx, r.DB).Delete(new(T), "id = ?", id).Error
+}
+
+// streamRows scans a prepared query row by row so large exports never hold
+// the whole result set in memory. Associations are not preloaded.
+func streamRows[T any](db *gorm.DB, fn func(*T) error) error {
+	rows, err := db.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entity T
+		if err := db.ScanRows(rows, &entity); err != nil {
+			return err
+		}
+		if err := fn(&entity); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
 }
@@ -19,13 +19,10 @@ func NewResourceRepository(db *gorm.DB) domain.Repository[domain.Resource] {
 	}
 }
 
-func (r *resourceRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Resource, int64, error) {
-	var entities []domain.Resource
-	var count int64
-
+// query applies the resource-specific filters shared by GetAll and Stream.
+func (r *resourceRepository) query(ctx context.Context, filter map[string]interface{}) *gorm.DB {
 	db := r.db.WithContext(ctx).Model(&domain.Resource{})
 
-	// ── Batch filtering (join-based) ─────────────────────────────────────────
 	// ── Batch filtering (join-based) ─────────────────────────────────────────
 	batchID, hasBatchID := filter["batch_id"]
 	batchName, hasBatchName := filter["batch"]
@@ -85,6 +82,15 @@ func (r *resourceRepository) GetAll(ctx context.Context, filter map[string]inter
 		}
 	}
 
+	return db
+}
+
+func (r *resourceRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Resource, int64, error) {
+	var entities []domain.Resource
+	var count int64
+
+	db := r.query(ctx, filter)
+
 	if err := db.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
@@ -99,3 +105,7 @@ func (r *resourceRepository) GetAll(ctx context.Context, filter map[string]inter
 
 	return entities, count, nil
 }
+
+func (r *resourceRepository) Stream(ctx context.Context, filter map[string]interface{}, fn func(*domain.Resource) error) error {
+	return streamRows(r.query(ctx, filter).Order("resources.created_at DESC"), fn)
+}
@@ -0,0 +1,58 @@
+package postgres
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type revisionRepository struct {
+	db *gorm.DB
+}
+
+func NewRevisionRepository(db *gorm.DB) domain.RevisionRepository {
+	return &revisionRepository{db: db}
+}
+
+func (r *revisionRepository) Create(ctx context.Context, rev *domain.Revision) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Serialise version assignment per entity; the unique index is the backstop.
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", rev.EntityName+":"+rev.EntityID.String()).Error; err != nil {
+			return err
+		}
+
+		var last int
+		if err := tx.Model(&domain.Revision{}).
+			Where("entity_name = ? AND entity_id = ?", rev.EntityName, rev.EntityID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&last).Error; err != nil {
+			return err
+		}
+
+		rev.Version = last + 1
+		return tx.Create(rev).Error
+	})
+}
+
+func (r *revisionRepository) ListByEntity(ctx context.Context, entityName string, entityID uuid.UUID) ([]domain.Revision, error) {
+	var revisions []domain.Revision
+	err := r.db.WithContext(ctx).
+		Where("entity_name = ? AND entity_id = ?", entityName, entityID).
+		Order("version DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *revisionRepository) GetByVersion(ctx context.Context, entityName string, entityID uuid.UUID, version int) (*domain.Revision, error) {
+	var rev domain.Revision
+	err := r.db.WithContext(ctx).
+		Where("entity_name = ? AND entity_id = ? AND version = ?", entityName, entityID, version).
+		First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
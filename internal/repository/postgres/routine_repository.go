@@ -0,0 +1,54 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type routineRepository struct {
+	domain.Repository[domain.RoutineEntry]
+	db *gorm.DB
+}
+
+func NewRoutineRepository(db *gorm.DB) domain.RoutineRepository {
+	return &routineRepository{
+		Repository: NewGormRepository[domain.RoutineEntry](db),
+		db:         db,
+	}
+}
+
+func (r *routineRepository) LockWeekday(ctx context.Context, universityID uuid.UUID, weekday int) error {
+	return conn(ctx, r.db).Exec("SELECT pg_advisory_xact_lock(hashtext(?), ?)", "routine:"+universityID.String(), weekday).Error
+}
+
+func (r *routineRepository) Clashes(ctx context.Context, entry *domain.RoutineEntry) ([]domain.RoutineEntry, error) {
+	var entries []domain.RoutineEntry
+
+	db := conn(ctx, r.db).
+		Where("id <> ? AND weekday = ?", entry.ID, entry.Weekday).
+		Where("start_time < ? AND end_time > ?", entry.EndTime, entry.StartTime).
+		Where("valid_from <= ? AND valid_until >= ?", entry.ValidUntil, entry.ValidFrom)
+	if entry.TeacherID != nil {
+		db = db.Where("((university_id = ? AND LOWER(room) = ?) OR teacher_id = ?)", entry.UniversityID, strings.ToLower(entry.Room), *entry.TeacherID)
+	} else {
+		db = db.Where("university_id = ? AND LOWER(room) = ?", entry.UniversityID, strings.ToLower(entry.Room))
+	}
+
+	err := db.Preload("Offering.Course").Preload("Teacher").Order("start_time ASC").Find(&entries).Error
+	return entries, err
+}
+
+func (r *routineRepository) Batch(ctx context.Context, batchID uuid.UUID, from, until string) ([]domain.RoutineEntry, error) {
+	var entries []domain.RoutineEntry
+	err := conn(ctx, r.db).
+		Where("batch_id = ? AND valid_from <= ? AND valid_until >= ?", batchID, until, from).
+		Preload("Offering.Course").Preload("Teacher").
+		Order("weekday ASC, start_time ASC").
+		Find(&entries).Error
+	return entries, err
+}
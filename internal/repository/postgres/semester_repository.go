@@ -19,10 +19,8 @@ func NewSemesterRepository(db *gorm.DB) domain.Repository[domain.Semester] {
 	}
 }
 
-func (r *semesterRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Semester, int64, error) {
-	var entities []domain.Semester
-	var count int64
-
+// query applies the filters shared by GetAll and Stream.
+func (r *semesterRepository) query(ctx context.Context, filter map[string]interface{}) *gorm.DB {
 	db := r.db.WithContext(ctx).Model(&domain.Semester{})
 
 	// Handle Batch Filtering
@@ -56,6 +54,15 @@ func (r *semesterRepository) GetAll(ctx context.Context, filter map[string]inter
 		}
 	}
 
+	return db
+}
+
+func (r *semesterRepository) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.Semester, int64, error) {
+	var entities []domain.Semester
+	var count int64
+
+	db := r.query(ctx, filter)
+
 	if err := db.Count(&count).Error; err != nil {
 		return nil, 0, err
 	}
@@ -67,3 +74,7 @@ func (r *semesterRepository) GetAll(ctx context.Context, filter map[string]inter
 
 	return entities, count, nil
 }
+
+func (r *semesterRepository) Stream(ctx context.Context, filter map[string]interface{}, fn func(*domain.Semester) error) error {
+	return streamRows(r.query(ctx, filter), fn)
+}
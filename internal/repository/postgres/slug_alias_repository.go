@@ -0,0 +1,28 @@
+package postgres
+
+import (
+	"context"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type slugAliasRepository struct {
+	db *gorm.DB
+}
+
+func NewSlugAliasRepository(db *gorm.DB) domain.SlugAliasRepository {
+	return &slugAliasRepository{db: db}
+}
+
+func (r *slugAliasRepository) Find(ctx context.Context, entityName, slug string, scope uuid.UUID) ([]domain.SlugAlias, error) {
+	var aliases []domain.SlugAlias
+	db := r.db.WithContext(ctx).Where("entity_name = ? AND slug = ?", entityName, slug)
+	if scope != uuid.Nil {
+		db = db.Where("scope_id = ?", scope)
+	}
+	err := db.Limit(2).Find(&aliases).Error
+	return aliases, err
+}
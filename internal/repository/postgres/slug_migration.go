@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"log"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/slug"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// migrateDuplicateSlugs runs before AutoMigrate adds the unique scoped slug
+// indexes. Rows created before slugs were de-duplicated may share a slug
+// within their scope, or have none: the oldest row keeps a shared slug and
+// the others get the next free suffix, as assignSlug would have given them.
+func migrateDuplicateSlugs(db *gorm.DB) error {
+	for _, model := range []domain.Slugged{&domain.Department{}, &domain.Session{}, &domain.Batch{}, &domain.Hall{}} {
+		if !db.Migrator().HasTable(model) {
+			continue
+		}
+
+		var rows []struct {
+			ID        uuid.UUID
+			ScopeID   uuid.UUID
+			Slug      string
+			Name      string
+			DeletedAt gorm.DeletedAt
+		}
+		if err := db.Model(model).Unscoped().
+			Select("id", model.SlugScope()+" AS scope_id", "slug", "name", "deleted_at").
+			Order("created_at, id").
+			Find(&rows).Error; err != nil {
+			return err
+		}
+
+		used := map[uuid.UUID]map[string]bool{}
+		for _, row := range rows {
+			if used[row.ScopeID] == nil {
+				used[row.ScopeID] = map[string]bool{}
+			}
+			used[row.ScopeID][row.Slug] = true
+		}
+
+		live := map[uuid.UUID]map[string]bool{}
+		renamed := 0
+		for _, row := range rows {
+			if row.DeletedAt.Valid {
+				continue
+			}
+			if live[row.ScopeID] == nil {
+				live[row.ScopeID] = map[string]bool{}
+			}
+			if row.Slug != "" && !live[row.ScopeID][row.Slug] {
+				live[row.ScopeID][row.Slug] = true
+				continue
+			}
+
+			base := slug.Make(row.Slug)
+			if base == "" {
+				base = slug.Make(row.Name)
+			}
+			if base == "" {
+				base = row.ID.String()
+			}
+			candidate := base
+			for n := 2; used[row.ScopeID][candidate]; n++ {
+				candidate = slug.WithSuffix(base, n)
+			}
+			if err := db.Model(model).Unscoped().Where("id = ?", row.ID).UpdateColumn("slug", candidate).Error; err != nil {
+				return err
+			}
+			used[row.ScopeID][candidate] = true
+			live[row.ScopeID][candidate] = true
+			renamed++
+		}
+		if renamed > 0 {
+			log.Printf("[MIGRATION] %T: re-slugged %d rows sharing a slug in their scope", model, renamed)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package postgres
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+type txKey struct{}
+
+type hooksKey struct{}
+
+// commitHooks collects the work to run once a transaction commits.
+type commitHooks struct {
+	fns []func(ctx context.Context)
+}
+
+// conn returns the transaction carried by ctx, or db when there is none, so
+// repositories join a transaction opened with transaction().
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// transaction runs fn with a context carrying a new transaction. When ctx
+// already carries one, fn runs in a savepoint of it, so a failed statement
+// (e.g. a unique violation the caller retries) only rolls back fn's writes
+// instead of aborting the outer transaction.
+//
+// Hooks registered with afterCommit run once the outermost transaction
+// commits, with ctx; ones registered in a rolled back savepoint are dropped.
+func transaction(ctx context.Context, db *gorm.DB, fn func(ctx context.Context) error) error {
+	hooks := &commitHooks{}
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		err := tx.WithContext(ctx).Transaction(func(sp *gorm.DB) error {
+			return fn(context.WithValue(context.WithValue(ctx, txKey{}, sp), hooksKey{}, hooks))
+		})
+		if err == nil {
+			parent := ctx.Value(hooksKey{}).(*commitHooks)
+			parent.fns = append(parent.fns, hooks.fns...)
+		}
+		return err
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(context.WithValue(ctx, txKey{}, tx), hooksKey{}, hooks))
+	})
+	if err == nil {
+		for _, hook := range hooks.fns {
+			hook(ctx)
+		}
+	}
+	return err
+}
+
+// afterCommit defers fn until the transaction carried by ctx commits, or
+// runs it right away when there is none.
+func afterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if hooks, ok := ctx.Value(hooksKey{}).(*commitHooks); ok {
+		hooks.fns = append(hooks.fns, fn)
+		return
+	}
+	fn(ctx)
+}
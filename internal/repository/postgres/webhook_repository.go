@@ -0,0 +1,105 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type webhookRepository struct {
+	domain.Repository[domain.WebhookSubscription]
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) domain.WebhookRepository {
+	return &webhookRepository{
+		Repository: NewGormRepository[domain.WebhookSubscription](db),
+		db:         db,
+	}
+}
+
+func (r *webhookRepository) Active(ctx context.Context, universityID, departmentID uuid.UUID) ([]domain.WebhookSubscription, error) {
+	var subs []domain.WebhookSubscription
+	err := r.db.WithContext(ctx).
+		Where("active = ?", true).
+		Where("university_id IS NULL OR university_id = ?", universityID).
+		Where("department_id IS NULL OR department_id = ?", departmentID).
+		Find(&subs).Error
+	return subs, err
+}
+
+func (r *webhookRepository) UpdateSecret(ctx context.Context, id uuid.UUID, secret string) error {
+	res := r.db.WithContext(ctx).Model(&domain.WebhookSubscription{}).
+		Where("id = ?", id).
+		Update("secret", secret)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *webhookRepository) CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	if d.ID == uuid.Nil {
+		d.ID = uuid.New()
+	}
+	return r.db.WithContext(ctx).Create(d).Error
+}
+
+func (r *webhookRepository) UpdateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Save(d).Error
+}
+
+func (r *webhookRepository) GetDelivery(ctx context.Context, subscriptionID, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	if err := r.db.WithContext(ctx).
+		First(&d, "id = ? AND subscription_id = ?", id, subscriptionID).Error; err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *webhookRepository) HasDelivery(ctx context.Context, subscriptionID, eventID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).
+		Where("subscription_id = ? AND event_id = ? AND redelivery_of IS NULL", subscriptionID, eventID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *webhookRepository) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, filter map[string]interface{}, limit, offset int) ([]domain.WebhookDelivery, int64, error) {
+	var deliveries []domain.WebhookDelivery
+	var count int64
+
+	db := r.db.WithContext(ctx).Model(&domain.WebhookDelivery{}).Where("subscription_id = ?", subscriptionID)
+	for key, value := range filter {
+		db = db.Where(key+" = ?", value)
+	}
+
+	if err := db.Count(&count).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := db.Order("created_at DESC").Limit(limit).Offset(offset).Find(&deliveries).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, count, nil
+}
+
+func (r *webhookRepository) DueDeliveries(ctx context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.WithContext(ctx).
+		Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, now).
+		Order("next_attempt_at").
+		Limit(limit).
+		Find(&deliveries).Error
+	return deliveries, err
+}
@@ -0,0 +1,428 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/ical"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidCalendarEvent is returned for a calendar event with a bad
+	// category, dates, times or recurrence.
+	ErrInvalidCalendarEvent = errors.New("calendar events need a title, a category of holiday, semester, registration, exam or event, YYYY-MM-DD dates with start on or before end, HH:MM times unless all day, and a weekly, monthly or yearly recurrence (if any) longer than the event")
+	// ErrInvalidCalendarRange is returned for a month that is not YYYY-MM
+	// or a range that is reversed or longer than a year.
+	ErrInvalidCalendarRange = errors.New("give ?month=YYYY-MM, or ?from= and ?to= dates at most a year apart")
+)
+
+// maxCalendarRangeDays limits ranges asked of the calendar.
+const maxCalendarRangeDays = 366
+
+// CalendarScope is the academic calendar of a university, or of one of its
+// departments, which also has the university-wide events.
+type CalendarScope struct {
+	UniversityID uuid.UUID
+	DepartmentID *uuid.UUID
+	Name         string
+}
+
+// Occurrence is one appearance of a calendar event; a recurring event has
+// one per repeat. StartDate and EndDate are the (inclusive) days it covers,
+// and EndsAt is the midnight after EndDate for all-day events.
+type Occurrence struct {
+	StartDate string               `json:"start_date"`
+	EndDate   string               `json:"end_date"`
+	StartsAt  time.Time            `json:"starts_at"`
+	EndsAt    time.Time            `json:"ends_at"`
+	Event     domain.CalendarEvent `json:"event"`
+}
+
+// WorkingDay answers whether classes and offices run on a date, with the
+// holidays that close it.
+type WorkingDay struct {
+	Date     string       `json:"date"`
+	Working  bool         `json:"working"`
+	Holidays []Occurrence `json:"holidays"`
+}
+
+// AcademicCalendar keeps the holidays, semester dates, deadlines and other
+// events of universities and departments. Academic admins write it. Other
+// features ask it which days are off: a day is not a working day when an
+// all-day holiday of the university, or of the department, covers it.
+// Weekends are weekly recurring holidays.
+type AcademicCalendar struct {
+	events       Usecase[domain.CalendarEvent]
+	repo         domain.CalendarEventRepository
+	universities Usecase[domain.University]
+	departments  Usecase[domain.Department]
+	loc          *time.Location
+}
+
+// NewAcademicCalendar reads event dates and times in loc.
+func NewAcademicCalendar(events Usecase[domain.CalendarEvent], repo domain.CalendarEventRepository, universities Usecase[domain.University], departments Usecase[domain.Department], loc *time.Location) *AcademicCalendar {
+	return &AcademicCalendar{
+		events:       events,
+		repo:         repo,
+		universities: universities,
+		departments:  departments,
+		loc:          loc,
+	}
+}
+
+// Create adds an event to the calendar of event.UniversityID, or of
+// event.DepartmentID when set.
+func (a *AcademicCalendar) Create(ctx context.Context, event *domain.CalendarEvent) (*domain.CalendarEvent, error) {
+	if err := a.prepare(ctx, event); err != nil {
+		return nil, err
+	}
+	if err := a.events.Create(ctx, event); err != nil {
+		return nil, err
+	}
+	return a.events.GetByID(ctx, event.ID)
+}
+
+// Update replaces an event.
+func (a *AcademicCalendar) Update(ctx context.Context, changes *domain.CalendarEvent) (*domain.CalendarEvent, error) {
+	event, err := a.events.GetByID(ctx, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	event.UniversityID = changes.UniversityID
+	event.DepartmentID = changes.DepartmentID
+	event.Title = changes.Title
+	event.Description = changes.Description
+	event.Category = changes.Category
+	event.Location = changes.Location
+	event.StartDate = changes.StartDate
+	event.EndDate = changes.EndDate
+	event.AllDay = changes.AllDay
+	event.StartTime = changes.StartTime
+	event.EndTime = changes.EndTime
+	event.Recurrence = changes.Recurrence
+	event.RepeatUntil = changes.RepeatUntil
+	event.UpdatedByID = changes.UpdatedByID
+	if err := a.prepare(ctx, event); err != nil {
+		return nil, err
+	}
+
+	if err := a.events.Update(ctx, event); err != nil {
+		return nil, err
+	}
+	return a.events.GetByID(ctx, event.ID)
+}
+
+// UniversityScope is the calendar of a university's university-wide
+// events.
+func (a *AcademicCalendar) UniversityScope(ctx context.Context, id uuid.UUID) (CalendarScope, error) {
+	university, err := a.universities.GetByID(ctx, id)
+	if err != nil {
+		return CalendarScope{}, err
+	}
+	return CalendarScope{UniversityID: university.ID, Name: university.Name}, nil
+}
+
+// DepartmentScope is the calendar of a department: its own events and its
+// university's.
+func (a *AcademicCalendar) DepartmentScope(ctx context.Context, id uuid.UUID) (CalendarScope, error) {
+	department, err := a.departments.GetByID(ctx, id)
+	if err != nil {
+		return CalendarScope{}, err
+	}
+	return CalendarScope{UniversityID: department.UniversityID, DepartmentID: &department.ID, Name: department.Name}, nil
+}
+
+// Range lists the scope's event occurrences in a month (YYYY-MM) or from
+// one date to another (YYYY-MM-DD, inclusive), by start. With neither it
+// covers the current month. A category, when given, limits the events.
+func (a *AcademicCalendar) Range(ctx context.Context, scope CalendarScope, month, from, to, category string) ([]Occurrence, error) {
+	first, last, err := a.span(month, from, to)
+	if err != nil {
+		return nil, err
+	}
+	occurrences, err := a.Occurrences(ctx, scope, first, last)
+	if err != nil || category == "" {
+		return occurrences, err
+	}
+
+	category = strings.ToLower(category)
+	filtered := []Occurrence{}
+	for _, o := range occurrences {
+		if o.Event.Category == category {
+			filtered = append(filtered, o)
+		}
+	}
+	return filtered, nil
+}
+
+// Feed returns the scope's events from a month ago to a year ahead as a
+// calendar feed, one event per occurrence.
+func (a *AcademicCalendar) Feed(ctx context.Context, scope CalendarScope) (ical.Calendar, error) {
+	today, _ := parseDate("", a.loc)
+	occurrences, err := a.Occurrences(ctx, scope, today.AddDate(0, 0, -feedDaysBack), today.AddDate(1, 0, 0))
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+
+	cal := ical.Calendar{Name: scope.Name + " academic calendar", Events: []ical.Event{}}
+	for _, o := range occurrences {
+		cal.Events = append(cal.Events, occurrenceEvent(o))
+	}
+	return cal, nil
+}
+
+// WorkingDay says whether the date (YYYY-MM-DD, today when empty) is a
+// working day in the scope.
+func (a *AcademicCalendar) WorkingDay(ctx context.Context, scope CalendarScope, date string) (*WorkingDay, error) {
+	day, err := parseDate(date, a.loc)
+	if err != nil {
+		return nil, err
+	}
+	occurrences, err := a.Occurrences(ctx, scope, day, day)
+	if err != nil {
+		return nil, err
+	}
+
+	answer := &WorkingDay{Date: day.Format(dateLayout), Working: true, Holidays: []Occurrence{}}
+	for _, o := range occurrences {
+		if isDayOff(o) {
+			answer.Working = false
+			answer.Holidays = append(answer.Holidays, o)
+		}
+	}
+	return answer, nil
+}
+
+// IsWorkingDay reports whether no all-day holiday of the scope covers day.
+func (a *AcademicCalendar) IsWorkingDay(ctx context.Context, scope CalendarScope, day time.Time) (bool, error) {
+	off, err := a.DaysOff(ctx, scope, day, day)
+	if err != nil {
+		return false, err
+	}
+	return !off[day.In(a.loc).Format(dateLayout)], nil
+}
+
+// DaysOff returns the dates (YYYY-MM-DD) from first to last that all-day
+// holidays of the scope cover.
+func (a *AcademicCalendar) DaysOff(ctx context.Context, scope CalendarScope, first, last time.Time) (map[string]bool, error) {
+	occurrences, err := a.Occurrences(ctx, scope, first, last)
+	if err != nil {
+		return nil, err
+	}
+
+	from, until := first.In(a.loc).Format(dateLayout), last.In(a.loc).Format(dateLayout)
+	off := map[string]bool{}
+	for _, o := range occurrences {
+		if !isDayOff(o) {
+			continue
+		}
+		start, _ := time.Parse(dateLayout, o.StartDate)
+		end, _ := time.Parse(dateLayout, o.EndDate)
+		for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+			if d := day.Format(dateLayout); d >= from && d <= until {
+				off[d] = true
+			}
+		}
+	}
+	return off, nil
+}
+
+// Occurrences expands the scope's events into their occurrences that touch
+// the days from first to last, by start.
+func (a *AcademicCalendar) Occurrences(ctx context.Context, scope CalendarScope, first, last time.Time) ([]Occurrence, error) {
+	from, until := first.In(a.loc).Format(dateLayout), last.In(a.loc).Format(dateLayout)
+	events, err := a.repo.Range(ctx, scope.UniversityID, scope.DepartmentID, from, until)
+	if err != nil {
+		return nil, err
+	}
+
+	occurrences := []Occurrence{}
+	for _, e := range events {
+		occurrences = append(occurrences, a.expand(e, from, until)...)
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].StartsAt.Before(occurrences[j].StartsAt)
+	})
+	return occurrences, nil
+}
+
+// expand returns the occurrences of e that touch the dates from from to
+// until. Monthly and yearly repeats skip months and years without the
+// start's day (the 31st, or 29 February).
+func (a *AcademicCalendar) expand(e domain.CalendarEvent, from, until string) []Occurrence {
+	start, errStart := time.Parse(dateLayout, e.StartDate)
+	end, errEnd := time.Parse(dateLayout, e.EndDate)
+	if errStart != nil || errEnd != nil {
+		return nil
+	}
+	days := int(end.Sub(start).Hours() / 24)
+
+	var occurrences []Occurrence
+	for k := 0; ; k++ {
+		if k > 0 && !slices.Contains(domain.CalendarRecurrences, e.Recurrence) {
+			break
+		}
+		s := repeat(start, e.Recurrence, k)
+		first := s.Format(dateLayout)
+		if first > until || (e.RepeatUntil != "" && first > e.RepeatUntil) {
+			break
+		}
+		if e.Recurrence != domain.RecurrenceWeekly && s.Day() != start.Day() {
+			continue
+		}
+		last := s.AddDate(0, 0, days).Format(dateLayout)
+		if last < from {
+			continue
+		}
+		occurrences = append(occurrences, a.occurrence(e, first, last))
+	}
+	return occurrences
+}
+
+// occurrence places e on the dates from first to last in the campus time
+// zone.
+func (a *AcademicCalendar) occurrence(e domain.CalendarEvent, first, last string) Occurrence {
+	startDay, _ := time.ParseInLocation(dateLayout, first, a.loc)
+	endDay, _ := time.ParseInLocation(dateLayout, last, a.loc)
+	o := Occurrence{StartDate: first, EndDate: last, Event: e}
+	if e.AllDay {
+		o.StartsAt, o.EndsAt = startDay, endDay.AddDate(0, 0, 1)
+		return o
+	}
+	start, end := clockMinutes(e.StartTime), clockMinutes(e.EndTime)
+	o.StartsAt = time.Date(startDay.Year(), startDay.Month(), startDay.Day(), start/60, start%60, 0, 0, a.loc)
+	o.EndsAt = time.Date(endDay.Year(), endDay.Month(), endDay.Day(), end/60, end%60, 0, 0, a.loc)
+	return o
+}
+
+// prepare checks an event and fills in its university from the department
+// when it has one.
+func (a *AcademicCalendar) prepare(ctx context.Context, e *domain.CalendarEvent) error {
+	e.Title = strings.TrimSpace(e.Title)
+	e.Category = strings.ToLower(strings.TrimSpace(e.Category))
+	e.Recurrence = strings.ToLower(strings.TrimSpace(e.Recurrence))
+	if e.EndDate == "" {
+		e.EndDate = e.StartDate
+	}
+	if e.StartTime == "" && e.EndTime == "" {
+		e.AllDay = true
+	}
+	if e.AllDay {
+		e.StartTime, e.EndTime = "", ""
+	}
+	if e.Recurrence == "" {
+		e.RepeatUntil = ""
+	}
+	if err := a.validate(e); err != nil {
+		return err
+	}
+
+	if e.DepartmentID != nil {
+		department, err := a.departments.GetByID(ctx, *e.DepartmentID)
+		if err != nil {
+			return err
+		}
+		e.UniversityID = department.UniversityID
+		return nil
+	}
+	_, err := a.universities.GetByID(ctx, e.UniversityID)
+	return err
+}
+
+func (a *AcademicCalendar) validate(e *domain.CalendarEvent) error {
+	start, errStart := time.Parse(dateLayout, e.StartDate)
+	end, errEnd := time.Parse(dateLayout, e.EndDate)
+	if e.Title == "" || !slices.Contains(domain.CalendarCategories, e.Category) ||
+		errStart != nil || errEnd != nil || end.Before(start) {
+		return ErrInvalidCalendarEvent
+	}
+	if !e.AllDay {
+		from, until := clockMinutes(e.StartTime), clockMinutes(e.EndTime)
+		if from < 0 || until < 0 || (e.StartDate == e.EndDate && from >= until) {
+			return ErrInvalidCalendarEvent
+		}
+	}
+	if e.Recurrence != "" {
+		if !slices.Contains(domain.CalendarRecurrences, e.Recurrence) || !repeat(start, e.Recurrence, 1).After(end) {
+			return ErrInvalidCalendarEvent
+		}
+		if e.RepeatUntil != "" {
+			if until, err := time.Parse(dateLayout, e.RepeatUntil); err != nil || until.Before(start) {
+				return ErrInvalidCalendarEvent
+			}
+		}
+	}
+	return nil
+}
+
+// span turns a month or a from/to pair into the midnights of its first and
+// last days.
+func (a *AcademicCalendar) span(month, from, to string) (time.Time, time.Time, error) {
+	if from != "" || to != "" {
+		first, errFrom := parseDate(from, a.loc)
+		last, errTo := parseDate(to, a.loc)
+		if errFrom != nil || errTo != nil {
+			return time.Time{}, time.Time{}, ErrInvalidDate
+		}
+		if last.Before(first) || last.After(first.AddDate(0, 0, maxCalendarRangeDays)) {
+			return time.Time{}, time.Time{}, ErrInvalidCalendarRange
+		}
+		return first, last, nil
+	}
+
+	var first time.Time
+	if month == "" {
+		today, _ := parseDate("", a.loc)
+		first = today.AddDate(0, 0, 1-today.Day())
+	} else {
+		var err error
+		if first, err = time.ParseInLocation("2006-01", month, a.loc); err != nil {
+			return time.Time{}, time.Time{}, ErrInvalidCalendarRange
+		}
+	}
+	return first, first.AddDate(0, 1, -1), nil
+}
+
+// repeat returns the start of the k-th repeat of an event starting on
+// start.
+func repeat(start time.Time, recurrence string, k int) time.Time {
+	switch recurrence {
+	case domain.RecurrenceWeekly:
+		return start.AddDate(0, 0, 7*k)
+	case domain.RecurrenceMonthly:
+		return start.AddDate(0, k, 0)
+	case domain.RecurrenceYearly:
+		return start.AddDate(k, 0, 0)
+	}
+	return start
+}
+
+func isDayOff(o Occurrence) bool {
+	return o.Event.AllDay && o.Event.Category == domain.CalendarCategoryHoliday
+}
+
+// occurrenceEvent turns an occurrence into a feed event. Its UID is stable
+// for the event and date, like routine classes.
+func occurrenceEvent(o Occurrence) ical.Event {
+	e := o.Event
+	return ical.Event{
+		UID:         fmt.Sprintf("calendar-%s-%s@campusassistant", e.ID, strings.ReplaceAll(o.StartDate, "-", "")),
+		Summary:     e.Title,
+		Description: e.Description,
+		Location:    e.Location,
+		Start:       o.StartsAt,
+		End:         o.EndsAt,
+		AllDay:      e.AllDay,
+		Status:      ical.StatusConfirmed,
+		Updated:     e.UpdatedAt,
+	}
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+)
+
+func TestCalendarRecurrence(t *testing.T) {
+	tests := []struct {
+		name  string
+		event domain.CalendarEvent
+		from  string
+		until string
+		want  []string
+	}{
+		{
+			name:  "monthly on the 31st skips short months",
+			event: domain.CalendarEvent{StartDate: "2026-01-31", EndDate: "2026-01-31", Recurrence: domain.RecurrenceMonthly},
+			from:  "2026-01-01", until: "2026-07-31",
+			want: []string{"2026-01-31", "2026-03-31", "2026-05-31", "2026-07-31"},
+		},
+		{
+			name:  "monthly on the 30th skips February",
+			event: domain.CalendarEvent{StartDate: "2026-01-30", EndDate: "2026-01-30", Recurrence: domain.RecurrenceMonthly},
+			from:  "2026-01-01", until: "2026-03-31",
+			want: []string{"2026-01-30", "2026-03-30"},
+		},
+		{
+			name:  "yearly on 29 February only in leap years",
+			event: domain.CalendarEvent{StartDate: "2024-02-29", EndDate: "2024-02-29", Recurrence: domain.RecurrenceYearly},
+			from:  "2024-01-01", until: "2032-12-31",
+			want: []string{"2024-02-29", "2028-02-29", "2032-02-29"},
+		},
+		{
+			name:  "weekly two-day weekend touching the range",
+			event: domain.CalendarEvent{StartDate: "2026-01-02", EndDate: "2026-01-03", Recurrence: domain.RecurrenceWeekly},
+			from:  "2026-03-07", until: "2026-03-15",
+			want: []string{"2026-03-06/2026-03-07", "2026-03-13/2026-03-14"},
+		},
+		{
+			name:  "monthly until the last repeat",
+			event: domain.CalendarEvent{StartDate: "2026-01-15", EndDate: "2026-01-15", Recurrence: domain.RecurrenceMonthly, RepeatUntil: "2026-03-15"},
+			from:  "2026-01-01", until: "2026-12-31",
+			want: []string{"2026-01-15", "2026-02-15", "2026-03-15"},
+		},
+		{
+			name:  "one-off event in range",
+			event: domain.CalendarEvent{StartDate: "2026-02-21", EndDate: "2026-02-21"},
+			from:  "2026-02-01", until: "2026-02-28",
+			want: []string{"2026-02-21"},
+		},
+		{
+			name:  "one-off event outside the range",
+			event: domain.CalendarEvent{StartDate: "2026-02-21", EndDate: "2026-02-21"},
+			from:  "2026-03-01", until: "2026-03-31",
+		},
+	}
+	a := &AcademicCalendar{loc: time.UTC}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.event.AllDay = true
+			var got []string
+			for _, o := range a.expand(tt.event, tt.from, tt.until) {
+				if o.StartDate == o.EndDate {
+					got = append(got, o.StartDate)
+				} else {
+					got = append(got, o.StartDate+"/"+o.EndDate)
+				}
+				if want, _ := time.Parse(dateLayout, o.EndDate); !o.EndsAt.Equal(want.AddDate(0, 0, 1)) {
+					t.Errorf("occurrence on %s ends at %s, want the midnight after %s", o.StartDate, o.EndsAt, o.EndDate)
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("occurrences = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalendarRecurrenceValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		event   domain.CalendarEvent
+		wantErr bool
+	}{
+		{"weekly one-day holiday", domain.CalendarEvent{StartDate: "2026-01-02", EndDate: "2026-01-02", Recurrence: domain.RecurrenceWeekly}, false},
+		{"weekly event as long as its repeat", domain.CalendarEvent{StartDate: "2026-01-01", EndDate: "2026-01-08", Recurrence: domain.RecurrenceWeekly}, true},
+		{"monthly event within a month", domain.CalendarEvent{StartDate: "2026-01-31", EndDate: "2026-02-27", Recurrence: domain.RecurrenceMonthly}, false},
+		{"unknown recurrence", domain.CalendarEvent{StartDate: "2026-01-01", EndDate: "2026-01-01", Recurrence: "daily"}, true},
+		{"repeating until before the start", domain.CalendarEvent{StartDate: "2026-01-01", EndDate: "2026-01-01", Recurrence: domain.RecurrenceYearly, RepeatUntil: "2025-12-31"}, true},
+	}
+	a := &AcademicCalendar{loc: time.UTC}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := tt.event
+			e.Title, e.Category, e.AllDay = "Weekend", domain.CalendarCategoryHoliday, true
+			err := a.validate(&e)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidCalendarEvent) {
+				t.Errorf("validate() error = %v, want ErrInvalidCalendarEvent", err)
+			}
+		})
+	}
+}
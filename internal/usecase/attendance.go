@@ -0,0 +1,339 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotAttendanceTaker is returned when someone other than an
+	// academic admin, a CR of the batch or a teacher of the course takes
+	// or reads its attendance.
+	ErrNotAttendanceTaker = errors.New("only academic admins, the batch's CRs and the course's teachers can do this")
+	// ErrInvalidAttendance is returned for a date in the future, a status
+	// other than present, absent or late, or an empty roll.
+	ErrInvalidAttendance = errors.New("attendance needs a date that is not in the future and a status of present, absent or late for each student")
+	// ErrStudentNotInBatch is returned when a roll marks a student of
+	// another batch.
+	ErrStudentNotInBatch = errors.New("student is not in the course offering's batch")
+)
+
+// Mark is one student's status in a roll.
+type Mark struct {
+	StudentID uuid.UUID `json:"student_id" binding:"required"`
+	Status    string    `json:"status" binding:"required"`
+}
+
+// CourseAttendance is a student's attendance in one course offering.
+// Attended counts present and late; Percentage is of the sessions the
+// student was marked in.
+type CourseAttendance struct {
+	Offering   *domain.CourseOffering `json:"offering"`
+	Present    int                    `json:"present"`
+	Late       int                    `json:"late"`
+	Absent     int                    `json:"absent"`
+	Attended   int                    `json:"attended"`
+	Marked     int                    `json:"marked"`
+	Percentage float64                `json:"percentage"`
+	Below      bool                   `json:"below_threshold"`
+}
+
+// StudentAttendance is one student's line in a course summary.
+type StudentAttendance struct {
+	Student    domain.Student `json:"student"`
+	Present    int            `json:"present"`
+	Late       int            `json:"late"`
+	Absent     int            `json:"absent"`
+	Attended   int            `json:"attended"`
+	Marked     int            `json:"marked"`
+	Percentage float64        `json:"percentage"`
+	Below      bool           `json:"below_threshold"`
+}
+
+// AttendanceSummary is a course offering's attendance per student of the
+// batch, with the students below Threshold percent flagged.
+type AttendanceSummary struct {
+	Offering  *domain.CourseOffering `json:"offering"`
+	Sessions  int64                  `json:"sessions"`
+	Threshold float64                `json:"threshold"`
+	Below     int                    `json:"below_threshold"`
+	Students  []StudentAttendance    `json:"students"`
+}
+
+// Attendance keeps the class attendance of course offerings: one session
+// per offering and date, in which academic admins, the batch's CRs or the
+// course's teachers mark each student present, absent or late. Students
+// see their own percentages; summaries flag students below a threshold.
+type Attendance struct {
+	sessions  Usecase[domain.AttendanceSession]
+	repo      domain.AttendanceRepository
+	offerings Usecase[domain.CourseOffering]
+	students  Usecase[domain.Student]
+	teachers  Usecase[domain.Teacher]
+	outbox    domain.Outbox
+	threshold float64
+	loc       *time.Location
+}
+
+// NewAttendance flags students attending less than threshold percent of
+// their classes and reads dates in loc.
+func NewAttendance(sessions Usecase[domain.AttendanceSession], repo domain.AttendanceRepository, offerings Usecase[domain.CourseOffering], students Usecase[domain.Student], teachers Usecase[domain.Teacher], outbox domain.Outbox, threshold float64, loc *time.Location) *Attendance {
+	return &Attendance{
+		sessions:  sessions,
+		repo:      repo,
+		offerings: offerings,
+		students:  students,
+		teachers:  teachers,
+		outbox:    outbox,
+		threshold: threshold,
+		loc:       loc,
+	}
+}
+
+// Take records the offering's attendance on the date (YYYY-MM-DD, today
+// when empty). Taking it again for the same date updates the note and the
+// students marked, and keeps the rest.
+func (a *Attendance) Take(ctx context.Context, offeringID uuid.UUID, date, note string, marks []Mark) (*domain.AttendanceSession, error) {
+	day, err := parseDate(date, a.loc)
+	if err != nil {
+		return nil, err
+	}
+	if today, _ := parseDate("", a.loc); day.After(today) || len(marks) == 0 {
+		return nil, ErrInvalidAttendance
+	}
+	offering, err := a.offerings.GetByID(ctx, offeringID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.authorize(ctx, offering); err != nil {
+		return nil, err
+	}
+
+	batch, err := a.batchStudents(ctx, offering.BatchID)
+	if err != nil {
+		return nil, err
+	}
+	actor, _ := domain.ActorFromContext(ctx)
+	records := make([]domain.AttendanceRecord, 0, len(marks))
+	for _, m := range marks {
+		status := strings.ToLower(strings.TrimSpace(m.Status))
+		if !slices.Contains(domain.AttendanceStatuses, status) {
+			return nil, ErrInvalidAttendance
+		}
+		if _, ok := batch[m.StudentID]; !ok {
+			return nil, ErrStudentNotInBatch
+		}
+		record := domain.AttendanceRecord{StudentID: m.StudentID, Status: status}
+		record.SetCreatedBy(actor.UserID)
+		record.SetUpdatedBy(actor.UserID)
+		records = append(records, record)
+	}
+
+	d := day.Format(dateLayout)
+	err = a.outbox.Transaction(ctx, func(ctx context.Context) error {
+		session, err := a.repo.Find(ctx, offering.ID, d)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			session = &domain.AttendanceSession{
+				OfferingID:   offering.ID,
+				BatchID:      offering.BatchID,
+				UniversityID: offering.UniversityID,
+				DepartmentID: offering.DepartmentID,
+				Date:         d,
+				Note:         note,
+			}
+			session.SetCreatedBy(actor.UserID)
+			session.SetUpdatedBy(actor.UserID)
+			if err := a.sessions.Create(ctx, session); err != nil {
+				return err
+			}
+		case err != nil:
+			return err
+		default:
+			session.Note = note
+			session.SetUpdatedBy(actor.UserID)
+			session.Offering, session.Records = nil, nil
+			if err := a.sessions.Update(ctx, session); err != nil {
+				return err
+			}
+		}
+
+		for i := range records {
+			records[i].SessionID = session.ID
+		}
+		return a.repo.SaveRecords(ctx, records)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return a.repo.Find(ctx, offering.ID, d)
+}
+
+// Roll returns the offering's attendance on the date with each student's
+// status.
+func (a *Attendance) Roll(ctx context.Context, offeringID uuid.UUID, date string) (*domain.AttendanceSession, error) {
+	day, err := parseDate(date, a.loc)
+	if err != nil {
+		return nil, err
+	}
+	offering, err := a.offerings.GetByID(ctx, offeringID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.authorize(ctx, offering); err != nil {
+		return nil, err
+	}
+	return a.repo.Find(ctx, offering.ID, day.Format(dateLayout))
+}
+
+// Sessions lists the dates the offering's attendance was taken, latest
+// first.
+func (a *Attendance) Sessions(ctx context.Context, offeringID uuid.UUID, limit, offset int) ([]domain.AttendanceSession, int64, error) {
+	offering, err := a.offerings.GetByID(ctx, offeringID)
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := a.authorize(ctx, offering); err != nil {
+		return nil, 0, err
+	}
+	return a.repo.Sessions(ctx, offering.ID, limit, offset)
+}
+
+// Summary returns the attendance of every student of the offering's batch,
+// by name, flagging those below threshold percent (the configured one when
+// threshold is 0).
+func (a *Attendance) Summary(ctx context.Context, offeringID uuid.UUID, threshold float64) (*AttendanceSummary, error) {
+	if threshold <= 0 {
+		threshold = a.threshold
+	}
+	offering, err := a.offerings.GetByID(ctx, offeringID)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.authorize(ctx, offering); err != nil {
+		return nil, err
+	}
+
+	_, sessions, err := a.repo.Sessions(ctx, offering.ID, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	tallies, err := a.repo.Tallies(ctx, &offering.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+	byStudent := make(map[uuid.UUID]domain.AttendanceTally, len(tallies))
+	for _, t := range tallies {
+		byStudent[t.StudentID] = t
+	}
+	batch, err := a.batchStudents(ctx, offering.BatchID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &AttendanceSummary{Offering: offering, Sessions: sessions, Threshold: threshold, Students: []StudentAttendance{}}
+	for _, student := range batch {
+		t := byStudent[student.ID]
+		line := StudentAttendance{Student: student, Present: t.Present, Late: t.Late, Absent: t.Absent}
+		line.Attended, line.Marked, line.Percentage = attendanceRate(t)
+		line.Below = line.Marked > 0 && line.Percentage < threshold
+		if line.Below {
+			summary.Below++
+		}
+		summary.Students = append(summary.Students, line)
+	}
+	sort.Slice(summary.Students, func(i, j int) bool {
+		si, sj := summary.Students[i].Student, summary.Students[j].Student
+		if si.StudentID != sj.StudentID {
+			return si.StudentID < sj.StudentID
+		}
+		return si.Name < sj.Name
+	})
+	return summary, nil
+}
+
+// Mine returns the signed-in student's attendance in each course offering
+// they have been marked in.
+func (a *Attendance) Mine(ctx context.Context) ([]CourseAttendance, error) {
+	actor, _ := domain.ActorFromContext(ctx)
+	found, _, err := a.students.GetAll(ctx, map[string]interface{}{"user_id": actor.UserID}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID == uuid.Nil || len(found) == 0 {
+		return nil, ErrNoStudentProfile
+	}
+
+	tallies, err := a.repo.Tallies(ctx, nil, &found[0].ID)
+	if err != nil {
+		return nil, err
+	}
+	courses := []CourseAttendance{}
+	for _, t := range tallies {
+		offering, err := a.offerings.GetByID(ctx, t.OfferingID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		offering.Teachers = nil
+		c := CourseAttendance{Offering: offering, Present: t.Present, Late: t.Late, Absent: t.Absent}
+		c.Attended, c.Marked, c.Percentage = attendanceRate(t)
+		c.Below = c.Marked > 0 && c.Percentage < a.threshold
+		courses = append(courses, c)
+	}
+	sort.Slice(courses, func(i, j int) bool {
+		return courses[i].Offering.CreatedAt.After(courses[j].Offering.CreatedAt)
+	})
+	return courses, nil
+}
+
+// authorize allows academic admins, current CRs of the offering's batch
+// and the offering's teachers.
+func (a *Attendance) authorize(ctx context.Context, offering *domain.CourseOffering) error {
+	actor, _ := domain.ActorFromContext(ctx)
+	if domain.Role(actor.Role) != domain.RoleTeacher {
+		if err := authorizeBatchEditor(ctx, a.students, offering.BatchID); !errors.Is(err, ErrNotBatchEditor) {
+			return err
+		}
+		return ErrNotAttendanceTaker
+	}
+
+	for _, t := range offering.Teachers {
+		if t.UserID != nil && actor.UserID != uuid.Nil && *t.UserID == actor.UserID {
+			return nil
+		}
+	}
+	return ErrNotAttendanceTaker
+}
+
+// batchStudents returns the students of the batch by ID.
+func (a *Attendance) batchStudents(ctx context.Context, batchID uuid.UUID) (map[uuid.UUID]domain.Student, error) {
+	students := map[uuid.UUID]domain.Student{}
+	err := a.students.Stream(ctx, map[string]interface{}{"batch_id": batchID}, func(s *domain.Student) error {
+		students[s.ID] = *s
+		return nil
+	})
+	return students, err
+}
+
+// attendanceRate returns the classes attended (present or late), the
+// classes marked and the attended percentage, rounded to one decimal.
+func attendanceRate(t domain.AttendanceTally) (int, int, float64) {
+	attended, marked := t.Present+t.Late, t.Present+t.Late+t.Absent
+	if marked == 0 {
+		return 0, 0, 0
+	}
+	return attended, marked, math.Round(float64(attended)*1000/float64(marked)) / 10
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// memAttendanceRepo is a domain.AttendanceRepository with fixed session
+// counts and tallies.
+type memAttendanceRepo struct {
+	*memRepo[domain.AttendanceSession]
+	sessions int64
+	tallies  []domain.AttendanceTally
+}
+
+func (r *memAttendanceRepo) Find(context.Context, uuid.UUID, string) (*domain.AttendanceSession, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *memAttendanceRepo) Sessions(context.Context, uuid.UUID, int, int) ([]domain.AttendanceSession, int64, error) {
+	return nil, r.sessions, nil
+}
+
+func (r *memAttendanceRepo) SaveRecords(context.Context, []domain.AttendanceRecord) error {
+	return errors.New("not implemented")
+}
+
+func (r *memAttendanceRepo) Tallies(context.Context, *uuid.UUID, *uuid.UUID) ([]domain.AttendanceTally, error) {
+	return r.tallies, nil
+}
+
+func TestAttendanceRate(t *testing.T) {
+	tests := []struct {
+		name         string
+		tally        domain.AttendanceTally
+		wantAttended int
+		wantMarked   int
+		wantRate     float64
+	}{
+		{"never marked", domain.AttendanceTally{}, 0, 0, 0},
+		{"all present", domain.AttendanceTally{Present: 12}, 12, 12, 100},
+		{"late counts as attended", domain.AttendanceTally{Present: 2, Late: 1, Absent: 1}, 3, 4, 75},
+		{"rounded to one decimal", domain.AttendanceTally{Present: 2, Absent: 1}, 2, 3, 66.7},
+		{"rounded down", domain.AttendanceTally{Present: 1, Absent: 2}, 1, 3, 33.3},
+		{"all absent", domain.AttendanceTally{Absent: 5}, 0, 5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			attended, marked, rate := attendanceRate(tt.tally)
+			if attended != tt.wantAttended || marked != tt.wantMarked || rate != tt.wantRate {
+				t.Errorf("attendanceRate(%+v) = %d, %d, %v, want %d, %d, %v",
+					tt.tally, attended, marked, rate, tt.wantAttended, tt.wantMarked, tt.wantRate)
+			}
+		})
+	}
+}
+
+func TestAttendanceSummaryThreshold(t *testing.T) {
+	batch := uuid.New()
+	offering := domain.CourseOffering{Base: domain.Base{ID: uuid.New()}, BatchID: batch}
+	student := func(id, name string) domain.Student {
+		return domain.Student{Base: domain.Base{ID: uuid.New()}, StudentID: id, Name: name, BatchID: batch}
+	}
+	students := []domain.Student{
+		student("1904", "Dipa"),
+		student("1901", "Arif"),
+		student("1903", "Chaity"),
+		student("1902", "Bashir"),
+	}
+	// Arif 90%, Bashir 60%, Chaity exactly 75%, Dipa never marked
+	tallies := []domain.AttendanceTally{
+		{StudentID: students[1].ID, Present: 8, Late: 1, Absent: 1},
+		{StudentID: students[3].ID, Present: 6, Absent: 4},
+		{StudentID: students[2].ID, Present: 2, Late: 1, Absent: 1},
+	}
+
+	tests := []struct {
+		name          string
+		threshold     float64
+		wantThreshold float64
+		wantBelow     []string
+	}{
+		{name: "configured threshold", wantThreshold: 75, wantBelow: []string{"Bashir"}},
+		{name: "stricter threshold", threshold: 95, wantThreshold: 95, wantBelow: []string{"Arif", "Bashir", "Chaity"}},
+		{name: "lenient threshold", threshold: 50, wantThreshold: 50},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memAttendanceRepo{memRepo: newMemRepo[domain.AttendanceSession](), sessions: 10, tallies: tallies}
+			a := NewAttendance(nil, repo,
+				NewGenericUsecase[domain.CourseOffering](newMemRepo(offering)),
+				NewGenericUsecase[domain.Student](newMemRepo(students...)),
+				nil, &memOutbox{}, 75, time.UTC,
+			)
+			ctx := domain.WithActor(context.Background(), domain.Actor{Role: string(domain.RoleDepartmentAdmin)})
+
+			summary, err := a.Summary(ctx, offering.ID, tt.threshold)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if summary.Threshold != tt.wantThreshold || summary.Sessions != 10 {
+				t.Errorf("Threshold = %v, Sessions = %d, want %v and 10", summary.Threshold, summary.Sessions, tt.wantThreshold)
+			}
+			var order, below []string
+			for _, line := range summary.Students {
+				order = append(order, line.Student.Name)
+				if line.Below {
+					below = append(below, line.Student.Name)
+				}
+			}
+			if want := []string{"Arif", "Bashir", "Chaity", "Dipa"}; !reflect.DeepEqual(order, want) {
+				t.Errorf("students in order %v, want %v", order, want)
+			}
+			if !reflect.DeepEqual(below, tt.wantBelow) || summary.Below != len(tt.wantBelow) {
+				t.Errorf("below threshold %v (count %d), want %v", below, summary.Below, tt.wantBelow)
+			}
+		})
+	}
+}
@@ -0,0 +1,134 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+const (
+	auditQueueSize     = 1024
+	auditBatchSize     = 50
+	auditFlushInterval = 2 * time.Second
+)
+
+// AuditRecorder collects audit entries on a buffered channel and writes them
+// in batches from a background goroutine, keeping inserts off the request path.
+// Entries recorded before Start wait in the queue.
+type AuditRecorder struct {
+	repo  domain.AuditLogRepository
+	queue chan domain.AuditLog
+	done  chan struct{}
+	wg    sync.WaitGroup
+	once  sync.Once
+}
+
+func NewAuditRecorder(repo domain.AuditLogRepository) *AuditRecorder {
+	r := &AuditRecorder{
+		repo:  repo,
+		queue: make(chan domain.AuditLog, auditQueueSize),
+		done:  make(chan struct{}),
+	}
+	return r
+}
+
+// Start launches the background writer.
+func (r *AuditRecorder) Start() {
+	r.wg.Add(1)
+	go r.run()
+}
+
+// Record enqueues an audit entry. The actor (user, IP, user agent) is read
+// from ctx. If the queue is full the entry is dropped rather than blocking.
+func (r *AuditRecorder) Record(ctx context.Context, action, entityName string, entityID uuid.UUID, changes map[string]FieldChange) {
+	entry := domain.AuditLog{
+		Action:     action,
+		EntityName: entityName,
+		EntityID:   entityID,
+		Timestamp:  time.Now(),
+	}
+
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		entry.UserID = actor.UserID
+		entry.IPAddress = actor.IPAddress
+		entry.UserAgent = actor.UserAgent
+	}
+
+	if len(changes) > 0 {
+		if raw, err := json.Marshal(changes); err == nil {
+			j := datatypes.JSON(raw)
+			entry.Changes = &j
+		}
+		fields := make([]string, 0, len(changes))
+		for field := range changes {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		entry.Description = "Changed fields: " + strings.Join(fields, ", ")
+	}
+
+	select {
+	case r.queue <- entry:
+	default:
+		logger.Errorf("audit queue full, dropping %s %s %s", action, entityName, entityID)
+	}
+}
+
+// Close stops the worker after flushing everything still queued.
+func (r *AuditRecorder) Close() {
+	r.once.Do(func() {
+		close(r.done)
+		r.wg.Wait()
+	})
+}
+
+func (r *AuditRecorder) run() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(auditFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.AuditLog, 0, auditBatchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := r.repo.CreateBatch(ctx, batch); err != nil {
+			logger.Errorf("failed to write %d audit logs: %v", len(batch), err)
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case entry := <-r.queue:
+			batch = append(batch, entry)
+			if len(batch) >= auditBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-r.done:
+			for {
+				select {
+				case entry := <-r.queue:
+					batch = append(batch, entry)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strconv"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/eventbus"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrBatchGraduated is returned when promoting a batch that no longer
+	// studies.
+	ErrBatchGraduated = errors.New("batch has graduated")
+	// ErrFinalSemester is returned when a batch has no semester after its
+	// current one; graduate it instead.
+	ErrFinalSemester = errors.New("batch is in its final semester")
+)
+
+// BatchPromotion is the plan (or, without DryRun, the result) of moving a
+// batch to its next semester.
+type BatchPromotion struct {
+	BatchID   uuid.UUID        `json:"batch_id"`
+	BatchName string           `json:"batch_name"`
+	From      *domain.Semester `json:"from,omitempty"`
+	To        *domain.Semester `json:"to"`
+	DryRun    bool             `json:"dry_run"`
+}
+
+// BatchGraduation is the plan (or result) of graduating a batch. Alumni
+// lists the records created, or to be created on a dry run; students
+// already in the directory are listed in Skipped by student ID.
+type BatchGraduation struct {
+	BatchID     uuid.UUID        `json:"batch_id"`
+	BatchName   string           `json:"batch_name"`
+	Semester    *domain.Semester `json:"semester,omitempty"` // Current semester
+	PassingYear string           `json:"passing_year"`
+	Alumni      []domain.Alumni  `json:"alumni"`
+	Skipped     []string         `json:"skipped"`
+	EndedCRs    int              `json:"ended_crs"`
+	DryRun      bool             `json:"dry_run"`
+}
+
+// BatchProgression promotes batches through their department's semesters
+// and graduates them into the alumni directory. Every action can be
+// previewed with dryRun, which runs the same lookups without writing.
+type BatchProgression struct {
+	batches   domain.BatchRepository
+	batchUC   Usecase[domain.Batch]
+	semesters Usecase[domain.Semester]
+	students  Usecase[domain.Student]
+	alumni    Usecase[domain.Alumni]
+	crs       *CRLifecycle
+	outbox    domain.Outbox
+	events    *eventbus.Bus
+}
+
+func NewBatchProgression(batches domain.BatchRepository, batchUC Usecase[domain.Batch], semesters Usecase[domain.Semester], students Usecase[domain.Student], alumni Usecase[domain.Alumni], crs *CRLifecycle, outbox domain.Outbox, events *eventbus.Bus) *BatchProgression {
+	return &BatchProgression{
+		batches:   batches,
+		batchUC:   batchUC,
+		semesters: semesters,
+		students:  students,
+		alumni:    alumni,
+		crs:       crs,
+		outbox:    outbox,
+		events:    events,
+	}
+}
+
+// Promote moves a batch from its current semester (the linked one with the
+// highest Order) to the department's next active semester by Order. A
+// batch without semesters starts at the first one.
+func (p *BatchProgression) Promote(ctx context.Context, batchID uuid.UUID, dryRun bool) (*BatchPromotion, error) {
+	batch, err := p.batchUC.GetByID(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if !batch.IsStudying {
+		return nil, ErrBatchGraduated
+	}
+
+	semesters, err := p.departmentSemesters(ctx, batch.DepartmentID)
+	if err != nil {
+		return nil, err
+	}
+	from := currentSemester(batch)
+
+	plan := &BatchPromotion{BatchID: batch.ID, BatchName: batch.Name, From: from, DryRun: dryRun}
+	for i := range semesters {
+		if from == nil || semesters[i].Order > from.Order {
+			plan.To = &semesters[i]
+			break
+		}
+	}
+	if plan.To == nil {
+		return nil, ErrFinalSemester
+	}
+	if dryRun {
+		return plan, nil
+	}
+
+	event := domain.BatchPromoted{
+		BatchID:      batch.ID,
+		ToSemesterID: plan.To.ID,
+		UniversityID: batch.UniversityID,
+		DepartmentID: batch.DepartmentID,
+	}
+	if from != nil {
+		event.FromSemesterID = &from.ID
+	}
+	err = p.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := p.batches.SetSemesters(ctx, batch.ID, []uuid.UUID{plan.To.ID}); err != nil {
+			return err
+		}
+		return p.outbox.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	p.publish(ctx, event)
+	return plan, nil
+}
+
+// Graduate marks a batch as no longer studying, ends its current CR terms
+// and adds every student without an alumni record (matched by student ID
+// within the department) to the alumni directory. Records of students who
+// claimed their profile are linked to the same account; the others get a
+// claim code. passingYear defaults to the current year. Running it again
+// only adds students missed before.
+func (p *BatchProgression) Graduate(ctx context.Context, batchID uuid.UUID, passingYear string, createdBy uuid.UUID, dryRun bool) (*BatchGraduation, error) {
+	batch, err := p.batchUC.GetByID(ctx, batchID)
+	if err != nil {
+		return nil, err
+	}
+	if passingYear == "" {
+		passingYear = strconv.Itoa(time.Now().Year())
+	}
+
+	existing := map[string]bool{}
+	err = p.alumni.Stream(ctx, map[string]interface{}{"department_id": batch.DepartmentID}, func(a *domain.Alumni) error {
+		existing[a.StudentID] = true
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &BatchGraduation{
+		BatchID:     batch.ID,
+		BatchName:   batch.Name,
+		Semester:    currentSemester(batch),
+		PassingYear: passingYear,
+		Alumni:      []domain.Alumni{},
+		Skipped:     []string{},
+		DryRun:      dryRun,
+	}
+	var linked []int
+	err = p.students.Stream(ctx, map[string]interface{}{"batch_id": batch.ID}, func(s *domain.Student) error {
+		if existing[s.StudentID] {
+			plan.Skipped = append(plan.Skipped, s.StudentID)
+			return nil
+		}
+		existing[s.StudentID] = true
+		alumni := domain.Alumni{
+			FullName:      s.Name,
+			StudentID:     s.StudentID,
+			Email:         s.Email,
+			Phone:         s.Phone,
+			Batch:         batch.Name,
+			PassingYear:   passingYear,
+			CurrentStatus: "Graduate",
+			CreatedBy:     createdBy,
+			UniversityID:  s.UniversityID,
+			DepartmentID:  s.DepartmentID,
+		}
+		if s.UserID != nil {
+			linked = append(linked, len(plan.Alumni))
+			alumni.Claim(*s.UserID, time.Now())
+		}
+		plan.Alumni = append(plan.Alumni, alumni)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Students who claimed their profile get an alumni record already
+	// claimed by the same account, unless it is linked to another record
+	for _, i := range linked {
+		a := &plan.Alumni[i]
+		owned, _, err := p.alumni.GetAll(ctx, map[string]interface{}{"user_id": *a.UserID}, 1, 0)
+		if err != nil {
+			return nil, err
+		}
+		if len(owned) > 0 {
+			a.UserID, a.IsClaimed, a.ClaimedAt = nil, false, nil
+		}
+	}
+	sort.Slice(plan.Alumni, func(i, j int) bool { return plan.Alumni[i].StudentID < plan.Alumni[j].StudentID })
+	sort.Strings(plan.Skipped)
+
+	if dryRun {
+		current, err := p.crs.Current(ctx, batch.ID)
+		if err != nil {
+			return nil, err
+		}
+		plan.EndedCRs = len(current)
+		return plan, nil
+	}
+
+	event := domain.BatchGraduated{
+		BatchID:      batch.ID,
+		PassingYear:  passingYear,
+		UniversityID: batch.UniversityID,
+		DepartmentID: batch.DepartmentID,
+	}
+	changed := batch.IsStudying || len(plan.Alumni) > 0
+	err = p.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if batch.IsStudying {
+			batch.IsStudying = false
+			batch.Students, batch.Semesters, batch.Sessions, batch.Department = nil, nil, nil, nil
+			if err := p.batchUC.Update(ctx, batch); err != nil {
+				return err
+			}
+		}
+		ended, err := p.crs.EndBatch(ctx, batch.ID, domain.CREndGraduated)
+		if err != nil {
+			return err
+		}
+		plan.EndedCRs = ended
+		for i := range plan.Alumni {
+			if err := p.alumni.Create(ctx, &plan.Alumni[i]); err != nil {
+				return err
+			}
+		}
+		if !changed && ended == 0 {
+			return nil
+		}
+		changed = true
+		event.AlumniCreated = len(plan.Alumni)
+		return p.outbox.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if changed {
+		p.publish(ctx, event)
+	}
+	return plan, nil
+}
+
+// departmentSemesters returns the department's active semesters by Order.
+func (p *BatchProgression) departmentSemesters(ctx context.Context, departmentID uuid.UUID) ([]domain.Semester, error) {
+	var semesters []domain.Semester
+	err := p.semesters.Stream(ctx, map[string]interface{}{"department_id": departmentID, "status": "active"}, func(s *domain.Semester) error {
+		s.Batches = nil
+		semesters = append(semesters, *s)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(semesters, func(i, j int) bool { return semesters[i].Order < semesters[j].Order })
+	return semesters, nil
+}
+
+// currentSemester is the linked semester with the highest Order.
+func currentSemester(batch *domain.Batch) *domain.Semester {
+	var current *domain.Semester
+	for i := range batch.Semesters {
+		s := batch.Semesters[i]
+		if current == nil || s.Order > current.Order {
+			current = &s
+		}
+	}
+	return current
+}
+
+func (p *BatchProgression) publish(ctx context.Context, e domain.Event) {
+	if err := p.events.Publish(ctx, e); err != nil {
+		logger.Errorf("%s subscribers failed: %v", e.EventName(), err)
+	}
+}
@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// memBatchRepo is a domain.BatchRepository that records semester links.
+type memBatchRepo struct {
+	*memRepo[domain.Batch]
+	linked []uuid.UUID
+}
+
+func (r *memBatchRepo) SetSemesters(_ context.Context, _ uuid.UUID, semesterIDs []uuid.UUID) error {
+	r.linked = semesterIDs
+	return nil
+}
+
+// memCRRepo is a domain.CRRepository without current terms.
+type memCRRepo struct {
+	*memRepo[domain.CR]
+}
+
+func (r *memCRRepo) LockBatch(context.Context, uuid.UUID) error { return nil }
+func (r *memCRRepo) Current(context.Context, uuid.UUID) ([]domain.CR, error) {
+	return nil, nil
+}
+func (r *memCRRepo) History(context.Context, uuid.UUID) ([]domain.CR, error) {
+	return nil, nil
+}
+func (r *memCRRepo) DueExpiry(context.Context, time.Time) ([]domain.CR, error) {
+	return nil, nil
+}
+func (r *memCRRepo) IsCurrent(context.Context, uuid.UUID) (bool, error) { return false, nil }
+
+func newTestProgression(batches *memBatchRepo, semesters []domain.Semester, students []domain.Student, alumni *memRepo[domain.Alumni], outbox *memOutbox) *BatchProgression {
+	studentUC := NewGenericUsecase[domain.Student](newMemRepo(students...))
+	crs := NewCRLifecycle(&memCRRepo{newMemRepo[domain.CR]()}, nil, studentUC, nil, outbox, nil, 0)
+	return NewBatchProgression(batches, NewGenericUsecase[domain.Batch](batches),
+		NewGenericUsecase[domain.Semester](newMemRepo(semesters...)), studentUC,
+		NewGenericUsecase[domain.Alumni](alumni), crs, outbox, nil)
+}
+
+func TestBatchPromotion(t *testing.T) {
+	dept := uuid.New()
+	semester := func(name string, order int, status string, department uuid.UUID) domain.Semester {
+		return domain.Semester{Base: domain.Base{ID: uuid.New()}, Name: name, Order: order, Status: status, DepartmentID: department}
+	}
+	first := semester("1st Semester", 1, "active", dept)
+	second := semester("2nd Semester", 2, "active", dept)
+	third := semester("3rd Semester", 3, "active", dept)
+	semesters := []domain.Semester{
+		third, first, second,
+		semester("Old 2nd Semester", 2, "archived", dept),
+		semester("Other department", 1, "active", uuid.New()),
+	}
+
+	tests := []struct {
+		name     string
+		linked   []domain.Semester
+		studying bool
+		dryRun   bool
+		wantFrom string
+		wantTo   string
+		wantErr  error
+	}{
+		{name: "new batch starts at the first semester", studying: true, wantTo: "1st Semester"},
+		{name: "next semester by order", linked: []domain.Semester{first}, studying: true, wantFrom: "1st Semester", wantTo: "2nd Semester"},
+		{name: "current is the highest linked", linked: []domain.Semester{second, first}, studying: true, wantFrom: "2nd Semester", wantTo: "3rd Semester"},
+		{name: "dry run", linked: []domain.Semester{first}, studying: true, dryRun: true, wantFrom: "1st Semester", wantTo: "2nd Semester"},
+		{name: "final semester", linked: []domain.Semester{third}, studying: true, wantErr: ErrFinalSemester},
+		{name: "graduated batch", linked: []domain.Semester{first}, wantErr: ErrBatchGraduated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batch := domain.Batch{Base: domain.Base{ID: uuid.New()}, Name: "Batch 10", DepartmentID: dept, IsStudying: tt.studying, Semesters: tt.linked}
+			batches := &memBatchRepo{memRepo: newMemRepo(batch)}
+			outbox := &memOutbox{}
+			p := newTestProgression(batches, semesters, nil, newMemRepo[domain.Alumni](), outbox)
+
+			plan, err := p.Promote(context.Background(), batch.ID, tt.dryRun)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Promote() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if batches.linked != nil || len(outbox.events) != 0 {
+					t.Error("refused promotion changed the batch")
+				}
+				return
+			}
+
+			from := ""
+			if plan.From != nil {
+				from = plan.From.Name
+			}
+			if from != tt.wantFrom || plan.To.Name != tt.wantTo {
+				t.Errorf("promotion %q -> %q, want %q -> %q", from, plan.To.Name, tt.wantFrom, tt.wantTo)
+			}
+			if tt.dryRun {
+				if batches.linked != nil || len(outbox.events) != 0 {
+					t.Error("dry run changed the batch")
+				}
+				return
+			}
+			if !reflect.DeepEqual(batches.linked, []uuid.UUID{plan.To.ID}) {
+				t.Errorf("batch linked to %v, want only %s", batches.linked, plan.To.ID)
+			}
+			if len(outbox.events) != 1 || outbox.events[0].EventName() != "batch.promoted" {
+				t.Errorf("outbox events %v, want one batch.promoted", outbox.events)
+			}
+		})
+	}
+}
+
+func TestBatchGraduation(t *testing.T) {
+	dept := uuid.New()
+	arifUser, dipaUser := uuid.New(), uuid.New()
+	batch := domain.Batch{Base: domain.Base{ID: uuid.New()}, Name: "Batch 10", DepartmentID: dept, IsStudying: true}
+	student := func(id, name string, userID *uuid.UUID) domain.Student {
+		return domain.Student{Base: domain.Base{ID: uuid.New()}, StudentID: id, Name: name, UserID: userID, BatchID: batch.ID, DepartmentID: dept}
+	}
+	students := []domain.Student{
+		student("1902", "Bashir", nil),
+		student("1901", "Arif", &arifUser),
+		student("1903", "Chaity", nil),
+		student("1904", "Dipa", &dipaUser),
+		{Base: domain.Base{ID: uuid.New()}, StudentID: "2001", Name: "Other batch", BatchID: uuid.New(), DepartmentID: dept},
+	}
+	// Chaity is already in the directory; Dipa's account owns an older record
+	existing := []domain.Alumni{
+		{Base: domain.Base{ID: uuid.New()}, StudentID: "1903", FullName: "Chaity", DepartmentID: dept},
+		{Base: domain.Base{ID: uuid.New()}, StudentID: "1750", FullName: "Dipa", DepartmentID: dept, UserID: &dipaUser, IsClaimed: true},
+	}
+
+	tests := []struct {
+		name   string
+		dryRun bool
+	}{
+		{name: "dry run", dryRun: true},
+		{name: "graduate", dryRun: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batches := &memBatchRepo{memRepo: newMemRepo(batch)}
+			alumni := newMemRepo(existing...)
+			outbox := &memOutbox{}
+			p := newTestProgression(batches, nil, students, alumni, outbox)
+
+			plan, err := p.Graduate(context.Background(), batch.ID, "2026", uuid.Nil, tt.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var ids []string
+			claimedBy := map[string]*uuid.UUID{}
+			for _, a := range plan.Alumni {
+				ids = append(ids, a.StudentID)
+				claimedBy[a.StudentID] = a.UserID
+				if a.PassingYear != "2026" || a.Batch != "Batch 10" {
+					t.Errorf("alumni %s: passing year %q batch %q", a.StudentID, a.PassingYear, a.Batch)
+				}
+			}
+			if want := []string{"1901", "1902", "1904"}; !reflect.DeepEqual(ids, want) {
+				t.Errorf("alumni %v, want %v", ids, want)
+			}
+			if !reflect.DeepEqual(plan.Skipped, []string{"1903"}) {
+				t.Errorf("skipped %v, want [1903]", plan.Skipped)
+			}
+			if u := claimedBy["1901"]; u == nil || *u != arifUser {
+				t.Errorf("Arif's record claimed by %v, want their account", u)
+			}
+			if claimedBy["1902"] != nil || claimedBy["1904"] != nil {
+				t.Error("records of unclaimed students, or of accounts with a record, should be left unclaimed")
+			}
+
+			stored, _ := batches.GetByID(context.Background(), batch.ID)
+			if tt.dryRun {
+				if alumni.len() != len(existing) || !stored.IsStudying || len(outbox.events) != 0 {
+					t.Error("dry run changed the directory or the batch")
+				}
+				return
+			}
+			if alumni.len() != len(existing)+3 || stored.IsStudying {
+				t.Errorf("%d alumni records, batch studying %v; want 3 added and the batch graduated", alumni.len(), stored.IsStudying)
+			}
+			if len(outbox.events) != 1 || outbox.events[0].(domain.BatchGraduated).AlumniCreated != 3 {
+				t.Errorf("outbox events %v, want one batch.graduated with 3 alumni", outbox.events)
+			}
+
+			// Running it again adds nobody and publishes nothing
+			again, err := p.Graduate(context.Background(), batch.ID, "2026", uuid.Nil, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(again.Alumni) != 0 || len(again.Skipped) != 4 || len(outbox.events) != 1 {
+				t.Errorf("second run: %d alumni, %d skipped, %d events; want 0, 4, 1", len(again.Alumni), len(again.Skipped), len(outbox.events))
+			}
+		})
+	}
+}
@@ -0,0 +1,387 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Notification types written by BloodDonors.
+const (
+	NotificationBloodRequested     = "BLOOD_REQUESTED"
+	NotificationBloodDonorAccepted = "BLOOD_DONOR_ACCEPTED"
+)
+
+var (
+	// ErrNoStudentProfile is returned when the signed-in user has not
+	// claimed a student profile; only students can be donors.
+	ErrNoStudentProfile = errors.New("no claimed student profile is linked to your account")
+	// ErrInvalidBloodGroup is returned for anything but A, B, AB or O
+	// followed by + or -.
+	ErrInvalidBloodGroup = errors.New("blood_group must be one of A+, A-, B+, B-, AB+, AB-, O+, O-")
+	// ErrFutureDonation is returned for a last donation date after now.
+	ErrFutureDonation = errors.New("last_donated_at cannot be in the future")
+	// ErrNoEligibleDonors is returned when a blood request matches no one.
+	ErrNoEligibleDonors = errors.New("no available donors match the request")
+	// ErrBloodMatchAnswered is returned when a donor answers a request twice.
+	ErrBloodMatchAnswered = errors.New("you have already answered this request")
+)
+
+// DonorProfile is what a student sets when opting in as a blood donor.
+// An empty BloodGroup keeps the one on record (or the student's); a nil
+// IsAvailable keeps the current value (available for new donors).
+type DonorProfile struct {
+	BloodGroup    string
+	LastDonatedAt *time.Time
+	IsAvailable   *bool
+}
+
+// BloodDonors is the consent-based donor registry. Students opt in and out
+// themselves; requesters reach matching donors through notifications, and a
+// donor's phone number is shown only to requesters whose request they
+// accepted. None of it goes through the generic usecase, so donor and
+// request records stay out of the audit log, revisions, events and
+// webhooks.
+type BloodDonors struct {
+	donors        domain.BloodDonorRepository
+	requests      domain.Repository[domain.BloodRequest]
+	matches       domain.Repository[domain.BloodRequestMatch]
+	students      Usecase[domain.Student]
+	notifications domain.Repository[domain.Notification]
+	outbox        domain.Outbox
+	cooldown      time.Duration
+	maxNotified   int
+}
+
+// NewBloodDonors lists donors again cooldown after their last donation and
+// notifies at most maxNotified donors per request.
+func NewBloodDonors(donors domain.BloodDonorRepository, requests domain.Repository[domain.BloodRequest], matches domain.Repository[domain.BloodRequestMatch], students Usecase[domain.Student], notifications domain.Repository[domain.Notification], outbox domain.Outbox, cooldown time.Duration, maxNotified int) *BloodDonors {
+	return &BloodDonors{
+		donors:        donors,
+		requests:      requests,
+		matches:       matches,
+		students:      students,
+		notifications: notifications,
+		outbox:        outbox,
+		cooldown:      cooldown,
+		maxNotified:   maxNotified,
+	}
+}
+
+// Eligible lists available donors past their cooldown. filter takes
+// blood_group, university_id and department_id.
+func (b *BloodDonors) Eligible(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.BloodDonor, int64, error) {
+	return b.donors.Eligible(ctx, filter, time.Now().Add(-b.cooldown), limit, offset)
+}
+
+// Me returns the donor record of the user, or gorm.ErrRecordNotFound when
+// they have not opted in.
+func (b *BloodDonors) Me(ctx context.Context, userID uuid.UUID) (*domain.BloodDonor, error) {
+	donor, err := b.donorOf(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	b.setEligibleAt(donor)
+	return donor, nil
+}
+
+// OptIn registers the user's student profile as a donor, or updates the
+// existing record.
+func (b *BloodDonors) OptIn(ctx context.Context, userID uuid.UUID, p DonorProfile) (*domain.BloodDonor, error) {
+	student, err := b.studentOf(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if p.LastDonatedAt != nil && p.LastDonatedAt.After(time.Now()) {
+		return nil, ErrFutureDonation
+	}
+
+	donor, err := b.donorOf(ctx, userID)
+	isNew := errors.Is(err, gorm.ErrRecordNotFound)
+	if err != nil && !isNew {
+		return nil, err
+	}
+	if isNew {
+		donor = &domain.BloodDonor{UserID: userID, BloodGroup: student.BloodGroup, IsAvailable: true}
+		donor.SetCreatedBy(userID)
+	}
+
+	if p.BloodGroup != "" {
+		donor.BloodGroup = p.BloodGroup
+	}
+	if donor.BloodGroup = domain.NormalizeBloodGroup(donor.BloodGroup); donor.BloodGroup == "" {
+		return nil, ErrInvalidBloodGroup
+	}
+	if p.LastDonatedAt != nil {
+		donor.LastDonatedAt = p.LastDonatedAt
+	}
+	if p.IsAvailable != nil {
+		donor.IsAvailable = *p.IsAvailable
+	}
+	donor.StudentID = student.ID
+	donor.UniversityID = student.UniversityID
+	donor.DepartmentID = student.DepartmentID
+	donor.Name = student.Name
+	donor.SetUpdatedBy(userID)
+
+	if isNew {
+		err = b.donors.Create(ctx, donor)
+	} else {
+		err = b.donors.Update(ctx, donor)
+	}
+	if err != nil {
+		return nil, err
+	}
+	b.setEligibleAt(donor)
+	return donor, nil
+}
+
+// OptOut deletes the user's donor record and their pending and past
+// matches.
+func (b *BloodDonors) OptOut(ctx context.Context, userID uuid.UUID) error {
+	donor, err := b.donorOf(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return b.donors.Delete(ctx, donor.ID)
+}
+
+// Request notifies up to maxNotified eligible donors of req.BloodGroup
+// (in req.UniversityID when set), longest rested first. The requester is
+// never matched with themselves.
+func (b *BloodDonors) Request(ctx context.Context, requesterID uuid.UUID, req domain.BloodRequest) (*domain.BloodRequest, error) {
+	if req.BloodGroup = domain.NormalizeBloodGroup(req.BloodGroup); req.BloodGroup == "" {
+		return nil, ErrInvalidBloodGroup
+	}
+
+	filter := map[string]interface{}{"blood_group": req.BloodGroup}
+	if req.UniversityID != nil {
+		filter["university_id"] = *req.UniversityID
+	}
+	candidates, _, err := b.Eligible(ctx, filter, b.maxNotified+1, 0)
+	if err != nil {
+		return nil, err
+	}
+	var donors []domain.BloodDonor
+	for _, d := range candidates {
+		if d.UserID != requesterID && len(donors) < b.maxNotified {
+			donors = append(donors, d)
+		}
+	}
+	if len(donors) == 0 {
+		return nil, ErrNoEligibleDonors
+	}
+
+	request := &domain.BloodRequest{
+		RequesterID:  requesterID,
+		BloodGroup:   req.BloodGroup,
+		UniversityID: req.UniversityID,
+		Hospital:     req.Hospital,
+		Message:      req.Message,
+		NeededBy:     req.NeededBy,
+	}
+	request.SetCreatedBy(requesterID)
+	request.SetUpdatedBy(requesterID)
+
+	err = b.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := b.requests.Create(ctx, request); err != nil {
+			return err
+		}
+		for _, d := range donors {
+			match := domain.BloodRequestMatch{RequestID: request.ID, DonorID: d.ID, Status: domain.BloodMatchPending}
+			if err := b.matches.Create(ctx, &match); err != nil {
+				return err
+			}
+			request.Matches = append(request.Matches, match)
+
+			body := "Someone needs " + request.BloodGroup + " blood"
+			if request.Hospital != "" {
+				body += " at " + request.Hospital
+			}
+			body += ". Open the request to accept or decline; your phone number is shared only if you accept."
+			if err := b.notify(ctx, d.UserID, NotificationBloodRequested, "Blood needed: "+request.BloodGroup, body, request.ID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Mine lists the requests made by the user, without their matches.
+func (b *BloodDonors) Mine(ctx context.Context, requesterID uuid.UUID, limit, offset int) ([]domain.BloodRequest, int64, error) {
+	return b.requests.GetAll(ctx, map[string]interface{}{"requester_id": requesterID}, limit, offset)
+}
+
+// Get returns a request to its requester, with the name and phone number
+// of every donor who accepted, or to a donor it was sent to, with only
+// their own match. Anyone else gets gorm.ErrRecordNotFound.
+func (b *BloodDonors) Get(ctx context.Context, userID, requestID uuid.UUID) (*domain.BloodRequest, error) {
+	request, err := b.requests.GetByID(ctx, requestID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.RequesterID == userID {
+		for i := range request.Matches {
+			if err := b.reveal(ctx, &request.Matches[i]); err != nil {
+				return nil, err
+			}
+		}
+		return request, nil
+	}
+
+	donor, err := b.donorOf(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, gorm.ErrRecordNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var own []domain.BloodRequestMatch
+	for _, m := range request.Matches {
+		if m.DonorID == donor.ID {
+			own = append(own, m)
+		}
+	}
+	if len(own) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	request.Matches = own
+	return request, nil
+}
+
+// Respond records the user's answer to a request they were matched with.
+// Accepting notifies the requester, who can then see the donor's phone
+// number on the request.
+func (b *BloodDonors) Respond(ctx context.Context, userID, requestID uuid.UUID, accept bool) (*domain.BloodRequestMatch, error) {
+	donor, err := b.donorOf(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var match *domain.BloodRequestMatch
+	err = b.outbox.Transaction(ctx, func(ctx context.Context) error {
+		found, _, err := b.matches.GetAll(ctx, map[string]interface{}{"request_id": requestID, "donor_id": donor.ID}, 1, 0)
+		if err != nil {
+			return err
+		}
+		if len(found) == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		match = &found[0]
+		if match.Status != domain.BloodMatchPending {
+			return ErrBloodMatchAnswered
+		}
+
+		now := time.Now()
+		match.Status = domain.BloodMatchDeclined
+		if accept {
+			match.Status = domain.BloodMatchAccepted
+		}
+		match.RespondedAt = &now
+		match.SetUpdatedBy(userID)
+		if err := b.matches.Update(ctx, match); err != nil {
+			return err
+		}
+		if !accept {
+			return nil
+		}
+
+		request, err := b.requests.GetByID(ctx, requestID)
+		if err != nil {
+			return err
+		}
+		return b.notify(ctx, request.RequesterID, NotificationBloodDonorAccepted,
+			"A donor accepted your request",
+			fmt.Sprintf("%s (%s) can donate. Open the request to see their phone number.", donor.Name, request.BloodGroup),
+			request.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return match, nil
+}
+
+// reveal fills in the donor's name and current phone number on an
+// accepted match.
+func (b *BloodDonors) reveal(ctx context.Context, m *domain.BloodRequestMatch) error {
+	if m.Status != domain.BloodMatchAccepted {
+		return nil
+	}
+	donor, err := b.donors.GetByID(ctx, m.DonorID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	m.Name = donor.Name
+	student, err := b.students.GetByID(ctx, donor.StudentID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	m.Phone = student.Phone
+	return nil
+}
+
+func (b *BloodDonors) notify(ctx context.Context, userID uuid.UUID, kind, title, body string, requestID uuid.UUID) error {
+	raw, err := json.Marshal(map[string]string{"blood_request_id": requestID.String()})
+	if err != nil {
+		return err
+	}
+	data := datatypes.JSON(raw)
+
+	return b.notifications.Create(ctx, &domain.Notification{
+		UserID: userID,
+		Title:  title,
+		Body:   body,
+		Type:   kind,
+		Data:   &data,
+	})
+}
+
+func (b *BloodDonors) donorOf(ctx context.Context, userID uuid.UUID) (*domain.BloodDonor, error) {
+	found, _, err := b.donors.GetAll(ctx, map[string]interface{}{"user_id": userID}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &found[0], nil
+}
+
+func (b *BloodDonors) studentOf(ctx context.Context, userID uuid.UUID) (*domain.Student, error) {
+	found, _, err := b.students.GetAll(ctx, map[string]interface{}{"user_id": userID}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, ErrNoStudentProfile
+	}
+	return &found[0], nil
+}
+
+func (b *BloodDonors) setEligibleAt(d *domain.BloodDonor) {
+	if d.LastDonatedAt == nil {
+		return
+	}
+	if at := d.LastDonatedAt.Add(b.cooldown); at.After(time.Now()) {
+		d.EligibleAt = &at
+	}
+}
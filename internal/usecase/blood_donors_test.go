@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// memDonorRepo is a domain.BloodDonorRepository. Donors hide their user ID
+// from JSON, so GetAll matches user_id itself.
+type memDonorRepo struct {
+	*memRepo[domain.BloodDonor]
+}
+
+func (r *memDonorRepo) GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.BloodDonor, int64, error) {
+	userID, ok := filter["user_id"]
+	if !ok {
+		return r.memRepo.GetAll(ctx, filter, limit, offset)
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.rows {
+		if d.UserID == userID {
+			return []domain.BloodDonor{*d}, 1, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+func (r *memDonorRepo) Eligible(ctx context.Context, filter map[string]interface{}, cutoff time.Time, limit, offset int) ([]domain.BloodDonor, int64, error) {
+	found, _, err := r.memRepo.GetAll(ctx, filter, 0, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	var eligible []domain.BloodDonor
+	for _, d := range found {
+		if d.IsAvailable && (d.LastDonatedAt == nil || d.LastDonatedAt.Before(cutoff)) {
+			eligible = append(eligible, d)
+		}
+	}
+	return eligible, int64(len(eligible)), nil
+}
+
+func TestBloodDonorCooldown(t *testing.T) {
+	const cooldown = 90 * 24 * time.Hour
+	requester := uuid.New()
+
+	tests := []struct {
+		name         string
+		daysAgo      int // since the last donation, 0 for never
+		group        string
+		unavailable  bool
+		userID       uuid.UUID
+		wantNotified bool
+		wantCooling  bool
+	}{
+		{name: "never donated", group: "O+", wantNotified: true},
+		{name: "rested", daysAgo: 100, group: "O+", wantNotified: true},
+		{name: "just past the cooldown", daysAgo: 91, group: "O+", wantNotified: true},
+		{name: "cooling down", daysAgo: 10, group: "O+", wantCooling: true},
+		{name: "unavailable", group: "O+", unavailable: true},
+		{name: "other group", group: "A+"},
+		{name: "requester", group: "O+", userID: requester},
+	}
+
+	var donors []domain.BloodDonor
+	for _, tt := range tests {
+		d := domain.BloodDonor{Base: domain.Base{ID: uuid.New()}, UserID: tt.userID, Name: tt.name, BloodGroup: tt.group, IsAvailable: !tt.unavailable}
+		if d.UserID == uuid.Nil {
+			d.UserID = uuid.New()
+		}
+		if tt.daysAgo > 0 {
+			at := time.Now().AddDate(0, 0, -tt.daysAgo)
+			d.LastDonatedAt = &at
+		}
+		donors = append(donors, d)
+	}
+	notifications := newMemRepo[domain.Notification]()
+	b := NewBloodDonors(
+		&memDonorRepo{newMemRepo(donors...)},
+		newMemRepo[domain.BloodRequest](), newMemRepo[domain.BloodRequestMatch](),
+		nil, notifications, &memOutbox{}, cooldown, 10,
+	)
+
+	// An unescaped "O+" in a query string arrives as "o "
+	request, err := b.Request(context.Background(), requester, domain.BloodRequest{BloodGroup: "o "})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if request.BloodGroup != "O+" {
+		t.Errorf("request for %q, want O+", request.BloodGroup)
+	}
+	notified := map[uuid.UUID]bool{}
+	for _, n := range notifications.rows {
+		notified[n.UserID] = true
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := donors[i]
+			if notified[d.UserID] != tt.wantNotified {
+				t.Errorf("notified = %v, want %v", notified[d.UserID], tt.wantNotified)
+			}
+
+			me, err := b.Me(context.Background(), d.UserID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (me.EligibleAt != nil) != tt.wantCooling {
+				t.Fatalf("EligibleAt = %v, want it set only while cooling down", me.EligibleAt)
+			}
+			if tt.wantCooling && !me.EligibleAt.Equal(d.LastDonatedAt.Add(cooldown)) {
+				t.Errorf("EligibleAt = %s, want %s", me.EligibleAt, d.LastDonatedAt.Add(cooldown))
+			}
+		})
+	}
+
+	var matched []string
+	for _, m := range request.Matches {
+		for _, d := range donors {
+			if d.ID == m.DonorID {
+				matched = append(matched, d.Name)
+			}
+		}
+	}
+	sort.Strings(matched)
+	if len(matched) != 3 || len(notifications.rows) != 3 {
+		t.Errorf("matched %v with %d notifications, want the three eligible donors", matched, len(notifications.rows))
+	}
+}
+
+func TestBloodDonorOptInRejectsBadGroups(t *testing.T) {
+	userID := uuid.New()
+	student := domain.Student{Base: domain.Base{ID: uuid.New()}, UserID: &userID, Name: "Rafi", BloodGroup: "b+"}
+
+	tests := []struct {
+		name      string
+		group     string
+		wantGroup string
+		wantErr   error
+	}{
+		{"group from the student profile", "", "B+", nil},
+		{"lower case", "ab-", "AB-", nil},
+		{"unknown group", "Z+", "", ErrInvalidBloodGroup},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBloodDonors(
+				&memDonorRepo{newMemRepo[domain.BloodDonor]()},
+				newMemRepo[domain.BloodRequest](), newMemRepo[domain.BloodRequestMatch](),
+				NewGenericUsecase[domain.Student](newMemRepo(student)),
+				newMemRepo[domain.Notification](), &memOutbox{}, time.Hour, 10,
+			)
+			donor, err := b.OptIn(context.Background(), userID, DonorProfile{BloodGroup: tt.group})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("OptIn() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && donor.BloodGroup != tt.wantGroup {
+				t.Errorf("BloodGroup = %q, want %q", donor.BloodGroup, tt.wantGroup)
+			}
+		})
+	}
+}
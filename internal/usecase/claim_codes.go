@@ -0,0 +1,201 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/auth"
+)
+
+// ClaimCodeLength is the number of digits in profile verification codes.
+const ClaimCodeLength = 6
+
+// claimCodeAttempts bounds how often a code is regenerated because another
+// unclaimed profile holds it.
+const claimCodeAttempts = 5
+
+// ErrClaimCodesDisabled is returned when the entity is not claimable or no
+// ClaimCodes were configured.
+var ErrClaimCodesDisabled = errors.New("claim codes are not enabled for this entity")
+
+// ClaimCodeIssuer issues new verification codes for claimable profiles.
+// Generic usecases built WithClaimCodes implement it.
+type ClaimCodeIssuer[T any] interface {
+	// IssueClaimCode gives an unclaimed profile a new code, returned in its
+	// IssuedClaimCode, and returns domain.ErrAlreadyClaimed for claimed ones.
+	IssueClaimCode(ctx context.Context, profile *T) error
+	// IssueClaimCodes issues codes for every unclaimed profile matching
+	// filter, in one transaction. With onlyMissing, profiles holding an
+	// unexpired code keep it and are left out of the result.
+	IssueClaimCodes(ctx context.Context, filter map[string]interface{}, onlyMissing bool) ([]T, error)
+}
+
+// ClaimCodes issues and hashes the verification codes of claimable
+// profiles. Codes are stored as keyed hashes and expire after ttl.
+type ClaimCodes struct {
+	secret string
+	ttl    time.Duration
+}
+
+func NewClaimCodes(secret string, ttl time.Duration) *ClaimCodes {
+	return &ClaimCodes{secret: secret, ttl: ttl}
+}
+
+// Hash returns the stored form of a plain code.
+func (c *ClaimCodes) Hash(code string) string {
+	return auth.HashCode(c.secret, code)
+}
+
+// Issue stores the hash of the profile's issued code, generating one when
+// none was given, and restarts its expiry. The plain code stays in
+// IssuedClaimCode for the response.
+func (c *ClaimCodes) Issue(p domain.Claimable) error {
+	code := p.IssuedClaimCode()
+	if code == "" {
+		var err error
+		if code, err = auth.GenerateNumericCode(ClaimCodeLength); err != nil {
+			return err
+		}
+		p.SetIssuedClaimCode(code)
+	}
+	expires := time.Now().Add(c.ttl)
+	p.SetClaimCode(c.Hash(code), &expires)
+	return nil
+}
+
+// WithClaimCodes issues verification codes for domain.Claimable entities on
+// create, and on update when a new code was set with SetIssuedClaimCode.
+// Other updates keep the stored code, which request bodies never carry.
+func WithClaimCodes(codes *ClaimCodes) Option {
+	return func(o *Options) {
+		o.ClaimCodes = codes
+	}
+}
+
+// claimCode applies WithClaimCodes to entity before it is written. Updates
+// also keep the stored account link: only ClaimRepository.Claim links a
+// profile, so a request body cannot attach it to (or detach it from) a user.
+func (u *genericUsecase[T]) claimCode(ctx context.Context, entity *T, create bool) error {
+	p, ok := any(entity).(domain.Claimable)
+	if !ok || u.opts.ClaimCodes == nil {
+		return nil
+	}
+	var stored domain.Claimable
+	if !create {
+		s, err := u.repo.GetByID(ctx, p.GetID())
+		if err != nil {
+			return err
+		}
+		stored = any(s).(domain.Claimable)
+		p.SetClaimState(stored.ClaimState())
+	}
+
+	switch {
+	case p.Claimed():
+		p.SetIssuedClaimCode("")
+	case create || p.IssuedClaimCode() != "":
+		return u.issue(ctx, p)
+	}
+	if create {
+		return nil
+	}
+	p.SetClaimCode(stored.ClaimCode())
+	return nil
+}
+
+// issue gives p a code no other unclaimed profile holds, regenerating it on
+// a collision. Two concurrent issues can still pick the same code; the
+// unique claim code index rejects the second write and retryClaimCode
+// issues it another.
+func (u *genericUsecase[T]) issue(ctx context.Context, p domain.Claimable) error {
+	for attempt := 1; ; attempt++ {
+		if err := u.opts.ClaimCodes.Issue(p); err != nil {
+			return err
+		}
+		hash, _ := p.ClaimCode()
+		_, holders, err := u.repo.GetAll(ctx, map[string]interface{}{"verification_code": hash, "is_claimed": false}, 1, 0)
+		if err != nil {
+			return err
+		}
+		if holders == 0 || attempt == claimCodeAttempts {
+			return nil
+		}
+		p.SetIssuedClaimCode("")
+	}
+}
+
+// retryClaimCode reports whether a write that failed with err should be
+// retried because the code just issued to entity was taken meanwhile, and
+// issues it a new one.
+func (u *genericUsecase[T]) retryClaimCode(ctx context.Context, entity *T, err error, attempt int) bool {
+	p, ok := any(entity).(domain.Claimable)
+	if !ok || !errors.Is(err, domain.ErrClaimCodeTaken) || p.IssuedClaimCode() == "" || attempt >= claimCodeAttempts {
+		return false
+	}
+	p.SetIssuedClaimCode("")
+	return u.issue(ctx, p) == nil
+}
+
+// IssueClaimCode records each issue in the audit log as ISSUE_CODE.
+func (u *genericUsecase[T]) IssueClaimCode(ctx context.Context, profile *T) error {
+	p, ok := any(profile).(domain.Claimable)
+	if !ok || u.opts.ClaimCodes == nil {
+		return ErrClaimCodesDisabled
+	}
+	if p.Claimed() {
+		return domain.ErrAlreadyClaimed
+	}
+
+	code, err := auth.GenerateNumericCode(ClaimCodeLength)
+	if err != nil {
+		return err
+	}
+	p.SetIssuedClaimCode(code)
+	return u.update(ctx, profile, domain.AuditActionIssueCode)
+}
+
+func (u *genericUsecase[T]) IssueClaimCodes(ctx context.Context, filter map[string]interface{}, onlyMissing bool) ([]T, error) {
+	if _, ok := any(new(T)).(domain.Claimable); !ok || u.opts.ClaimCodes == nil {
+		return nil, ErrClaimCodesDisabled
+	}
+
+	f := make(map[string]interface{}, len(filter)+1)
+	for k, v := range filter {
+		f[k] = v
+	}
+	f["is_claimed"] = false
+
+	// Collect first: updating while the cursor is open would hold two
+	// connections per profile.
+	now := time.Now()
+	var profiles []T
+	if err := u.Stream(ctx, f, func(profile *T) error {
+		if onlyMissing {
+			if hash, expires := any(profile).(domain.Claimable).ClaimCode(); hash != "" && expires != nil && expires.After(now) {
+				return nil
+			}
+		}
+		profiles = append(profiles, *profile)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	// All or nothing: the new codes are only ever shown in the response, so a
+	// failure part-way must not leave profiles with codes nobody received.
+	// Each profile gets a code no other unclaimed profile holds (see issue).
+	err := u.inTransaction(ctx, func(ctx context.Context) error {
+		for i := range profiles {
+			if err := u.IssueClaimCode(ctx, &profiles[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return profiles, nil
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestIssueClaimCodesAuditsOnlyCommittedBatches(t *testing.T) {
+	dept := uuid.New()
+	staff := []domain.Staff{
+		{Base: domain.Base{ID: uuid.New()}, Name: "Alice", DepartmentID: dept},
+		{Base: domain.Base{ID: uuid.New()}, Name: "Bob", DepartmentID: dept},
+	}
+
+	tests := []struct {
+		name       string
+		failOn     string
+		wantErr    bool
+		wantAudits int
+	}{
+		{name: "all codes issued", wantAudits: 2},
+		{name: "second update fails", failOn: "Bob", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemRepo(staff...)
+			repo.fail = func(s *domain.Staff) error {
+				if s.Name == tt.failOn {
+					return errors.New("update failed")
+				}
+				return nil
+			}
+			audits := newMemAuditRepo()
+			recorder := NewAuditRecorder(audits)
+			recorder.Start()
+
+			uc := NewGenericUsecase[domain.Staff](repo,
+				WithAuditor(recorder),
+				WithOutbox(&memOutbox{}),
+				WithClaimCodes(NewClaimCodes("secret", time.Hour)),
+			)
+			issued, err := uc.(ClaimCodeIssuer[domain.Staff]).IssueClaimCodes(context.Background(), map[string]interface{}{"department_id": dept}, false)
+			recorder.Close()
+
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("IssueClaimCodes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && len(issued) != len(staff) {
+				t.Errorf("%d codes issued, want %d", len(issued), len(staff))
+			}
+			if got := audits.len(); got != tt.wantAudits {
+				t.Errorf("%d audit logs written, want %d", got, tt.wantAudits)
+			}
+		})
+	}
+}
+
+func TestUpdateKeepsClaimState(t *testing.T) {
+	owner, intruder := uuid.New(), uuid.New()
+	claimedAt := time.Now().Add(-time.Hour)
+	unclaimed := domain.Staff{Base: domain.Base{ID: uuid.New()}, Name: "Alice"}
+	claimed := domain.Staff{Base: domain.Base{ID: uuid.New()}, Name: "Bob", UserID: &owner, IsClaimed: true, ClaimedAt: &claimedAt}
+
+	tests := []struct {
+		name   string
+		stored domain.Staff
+		body   domain.ClaimState
+	}{
+		{"attach an unclaimed profile", unclaimed, domain.ClaimState{UserID: &intruder, IsClaimed: true, ClaimedAt: &claimedAt}},
+		{"move a claimed profile", claimed, domain.ClaimState{UserID: &intruder, IsClaimed: true, ClaimedAt: &claimedAt}},
+		{"detach a claimed profile", claimed, domain.ClaimState{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemRepo(tt.stored)
+			uc := NewGenericUsecase[domain.Staff](repo, WithClaimCodes(NewClaimCodes("secret", time.Hour)))
+
+			update := tt.stored
+			update.Post = "Lab Technician"
+			update.SetClaimState(tt.body)
+			if err := uc.Update(context.Background(), &update); err != nil {
+				t.Fatal(err)
+			}
+
+			saved, err := repo.GetByID(context.Background(), tt.stored.ID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if saved.Post != "Lab Technician" {
+				t.Errorf("Post = %q, want the update applied", saved.Post)
+			}
+			want := tt.stored.ClaimState()
+			if got := saved.ClaimState(); got.IsClaimed != want.IsClaimed || (got.UserID == nil) != (want.UserID == nil) || (got.UserID != nil && *got.UserID != *want.UserID) {
+				t.Errorf("claim state = %+v, want the stored %+v", got, want)
+			}
+		})
+	}
+}
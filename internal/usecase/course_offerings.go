@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/eventbus"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrOfferingMismatch is returned when the semester or session of an
+	// offering belongs to another department than its batch.
+	ErrOfferingMismatch = errors.New("semester and session must belong to the batch's department")
+	// ErrDuplicateOffering is returned when the course is already offered
+	// to the batch and section in that semester.
+	ErrDuplicateOffering = errors.New("course is already offered to this batch and section in that semester")
+	// ErrUnknownTeacher is returned when assigning a teacher that does not
+	// exist.
+	ErrUnknownTeacher = errors.New("teacher not found")
+	// ErrNoTeacherProfile is returned when the signed-in user has not
+	// claimed a teacher profile.
+	ErrNoTeacherProfile = errors.New("no claimed teacher profile is linked to your account")
+)
+
+// StudentCourses is what a student is taking now: their batch's current
+// semester and its course offerings. Semester is nil until the batch is
+// linked to one.
+type StudentCourses struct {
+	BatchID  uuid.UUID               `json:"batch_id"`
+	Semester *domain.Semester        `json:"semester"`
+	Courses  []domain.CourseOffering `json:"courses"`
+}
+
+// CourseOfferings manages course offerings (a course taught to a batch in
+// a semester) and the teachers assigned to them, and answers "my courses"
+// for teachers and students. The signed-in user comes from
+// domain.ActorFromContext.
+type CourseOfferings struct {
+	offerings Usecase[domain.CourseOffering]
+	repo      domain.CourseOfferingRepository
+	courses   Usecase[domain.Course]
+	batches   Usecase[domain.Batch]
+	semesters Usecase[domain.Semester]
+	sessions  Usecase[domain.Session]
+	teachers  Usecase[domain.Teacher]
+	students  Usecase[domain.Student]
+	outbox    domain.Outbox
+	events    *eventbus.Bus
+}
+
+func NewCourseOfferings(offerings Usecase[domain.CourseOffering], repo domain.CourseOfferingRepository, courses Usecase[domain.Course], batches Usecase[domain.Batch], semesters Usecase[domain.Semester], sessions Usecase[domain.Session], teachers Usecase[domain.Teacher], students Usecase[domain.Student], outbox domain.Outbox, events *eventbus.Bus) *CourseOfferings {
+	return &CourseOfferings{
+		offerings: offerings,
+		repo:      repo,
+		courses:   courses,
+		batches:   batches,
+		semesters: semesters,
+		sessions:  sessions,
+		teachers:  teachers,
+		students:  students,
+		outbox:    outbox,
+		events:    events,
+	}
+}
+
+// Create offers a course to a batch and assigns offering.TeacherIDs.
+func (u *CourseOfferings) Create(ctx context.Context, offering *domain.CourseOffering) (*domain.CourseOffering, error) {
+	if err := u.validate(ctx, offering); err != nil {
+		return nil, err
+	}
+	teacherIDs, err := u.checkTeachers(ctx, offering.TeacherIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	offering.Course, offering.Batch, offering.Semester, offering.Session = nil, nil, nil, nil
+	offering.Teachers = nil
+	var event *domain.CourseTeachersAssigned
+	err = u.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.offerings.Create(ctx, offering); err != nil {
+			return err
+		}
+		if len(teacherIDs) == 0 {
+			return nil
+		}
+		if err := u.repo.SetTeachers(ctx, offering.ID, teacherIDs); err != nil {
+			return err
+		}
+		event = &domain.CourseTeachersAssigned{Offering: offering, Added: teacherIDs, Removed: []uuid.UUID{}}
+		return u.outbox.Add(ctx, *event)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if event != nil {
+		u.publish(ctx, *event)
+	}
+	return u.offerings.GetByID(ctx, offering.ID)
+}
+
+// Update moves an offering to another course, batch, semester, session or
+// section. Its teachers are changed with SetTeachers.
+func (u *CourseOfferings) Update(ctx context.Context, changes *domain.CourseOffering) (*domain.CourseOffering, error) {
+	offering, err := u.offerings.GetByID(ctx, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	offering.CourseID = changes.CourseID
+	offering.BatchID = changes.BatchID
+	offering.SemesterID = changes.SemesterID
+	offering.SessionID = changes.SessionID
+	offering.Section = changes.Section
+	offering.UpdatedByID = changes.UpdatedByID
+	if err := u.validate(ctx, offering); err != nil {
+		return nil, err
+	}
+
+	offering.Course, offering.Batch, offering.Semester, offering.Session = nil, nil, nil, nil
+	offering.Teachers = nil
+	if err := u.offerings.Update(ctx, offering); err != nil {
+		return nil, err
+	}
+	return u.offerings.GetByID(ctx, offering.ID)
+}
+
+// SetTeachers replaces the teachers assigned to an offering.
+func (u *CourseOfferings) SetTeachers(ctx context.Context, id uuid.UUID, teacherIDs []uuid.UUID) (*domain.CourseOffering, error) {
+	offering, err := u.offerings.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	teacherIDs, err = u.checkTeachers(ctx, teacherIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uuid.UUID]bool, len(offering.Teachers))
+	for _, t := range offering.Teachers {
+		current[t.ID] = true
+	}
+	event := domain.CourseTeachersAssigned{Offering: offering, Added: []uuid.UUID{}, Removed: []uuid.UUID{}}
+	for _, tid := range teacherIDs {
+		if !current[tid] {
+			event.Added = append(event.Added, tid)
+		}
+		delete(current, tid)
+	}
+	for tid := range current {
+		event.Removed = append(event.Removed, tid)
+	}
+	if len(event.Added) == 0 && len(event.Removed) == 0 {
+		return offering, nil
+	}
+
+	err = u.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := u.repo.SetTeachers(ctx, id, teacherIDs); err != nil {
+			return err
+		}
+		return u.outbox.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	offering, err = u.offerings.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	event.Offering = offering
+	u.publish(ctx, event)
+	return offering, nil
+}
+
+// AddTeacher assigns one more teacher to an offering.
+func (u *CourseOfferings) AddTeacher(ctx context.Context, id, teacherID uuid.UUID) (*domain.CourseOffering, error) {
+	offering, err := u.offerings.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ids := []uuid.UUID{teacherID}
+	for _, t := range offering.Teachers {
+		ids = append(ids, t.ID)
+	}
+	return u.SetTeachers(ctx, id, ids)
+}
+
+// RemoveTeacher unassigns a teacher from an offering.
+func (u *CourseOfferings) RemoveTeacher(ctx context.Context, id, teacherID uuid.UUID) (*domain.CourseOffering, error) {
+	offering, err := u.offerings.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ids := []uuid.UUID{}
+	for _, t := range offering.Teachers {
+		if t.ID != teacherID {
+			ids = append(ids, t.ID)
+		}
+	}
+	return u.SetTeachers(ctx, id, ids)
+}
+
+// TeacherCourses lists the offerings the signed-in teacher is assigned to,
+// newest first, narrowed by filter (batch_id, semester_id, ...).
+func (u *CourseOfferings) TeacherCourses(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]domain.CourseOffering, int64, error) {
+	actor, _ := domain.ActorFromContext(ctx)
+	found, _, err := u.teachers.GetAll(ctx, map[string]interface{}{"user_id": actor.UserID}, 1, 0)
+	if err != nil {
+		return nil, 0, err
+	}
+	if actor.UserID == uuid.Nil || len(found) == 0 {
+		return nil, 0, ErrNoTeacherProfile
+	}
+
+	filter["teacher_id"] = found[0].ID
+	return u.repo.GetAll(ctx, filter, limit, offset)
+}
+
+// StudentCourses returns the offerings of the signed-in student's batch in
+// the batch's current semester, the linked semester with the highest
+// order.
+func (u *CourseOfferings) StudentCourses(ctx context.Context) (*StudentCourses, error) {
+	actor, _ := domain.ActorFromContext(ctx)
+	found, _, err := u.students.GetAll(ctx, map[string]interface{}{"user_id": actor.UserID}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID == uuid.Nil || len(found) == 0 {
+		return nil, ErrNoStudentProfile
+	}
+	student := found[0]
+
+	result := &StudentCourses{BatchID: student.BatchID, Courses: []domain.CourseOffering{}}
+	semesters, _, err := u.semesters.GetAll(ctx, map[string]interface{}{"batch_id": student.BatchID.String()}, 100, 0)
+	if err != nil {
+		return nil, err
+	}
+	for i := range semesters {
+		if result.Semester == nil || semesters[i].Order > result.Semester.Order {
+			result.Semester = &semesters[i]
+		}
+	}
+	if result.Semester == nil {
+		return result, nil
+	}
+	result.Semester.Batches = nil
+
+	offerings, _, err := u.repo.GetAll(ctx, map[string]interface{}{
+		"batch_id":    student.BatchID,
+		"semester_id": result.Semester.ID,
+	}, 100, 0)
+	if err != nil {
+		return nil, err
+	}
+	result.Courses = offerings
+	return result, nil
+}
+
+// validate checks that the course, batch, semester and session exist and
+// fit together, copies the batch's university and department, and rejects
+// a second offering of the course to the same batch, semester and section.
+func (u *CourseOfferings) validate(ctx context.Context, o *domain.CourseOffering) error {
+	o.Section = strings.TrimSpace(o.Section)
+	if _, err := u.courses.GetByID(ctx, o.CourseID); err != nil {
+		return err
+	}
+	batch, err := u.batches.GetByID(ctx, o.BatchID)
+	if err != nil {
+		return err
+	}
+	semester, err := u.semesters.GetByID(ctx, o.SemesterID)
+	if err != nil {
+		return err
+	}
+	if semester.DepartmentID != batch.DepartmentID {
+		return ErrOfferingMismatch
+	}
+	if o.SessionID != nil {
+		session, err := u.sessions.GetByID(ctx, *o.SessionID)
+		if err != nil {
+			return err
+		}
+		if session.DepartmentID != batch.DepartmentID {
+			return ErrOfferingMismatch
+		}
+	}
+	o.UniversityID = batch.UniversityID
+	o.DepartmentID = batch.DepartmentID
+
+	existing, _, err := u.repo.GetAll(ctx, map[string]interface{}{
+		"course_id":   o.CourseID,
+		"batch_id":    o.BatchID,
+		"semester_id": o.SemesterID,
+		"section":     o.Section,
+	}, 2, 0)
+	if err != nil {
+		return err
+	}
+	for _, e := range existing {
+		if e.ID != o.ID {
+			return ErrDuplicateOffering
+		}
+	}
+	return nil
+}
+
+// checkTeachers drops duplicate IDs and makes sure every teacher exists.
+func (u *CourseOfferings) checkTeachers(ctx context.Context, ids []uuid.UUID) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if _, err := u.teachers.GetByID(ctx, id); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrUnknownTeacher
+			}
+			return nil, err
+		}
+		unique = append(unique, id)
+	}
+	return unique, nil
+}
+
+func (u *CourseOfferings) publish(ctx context.Context, e domain.Event) {
+	if err := u.events.Publish(ctx, e); err != nil {
+		logger.Errorf("%s subscribers failed: %v", e.EventName(), err)
+	}
+}
@@ -0,0 +1,404 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/eventbus"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrCRLimitReached is returned when a batch already has the maximum
+	// number of current CRs; hand over one of their terms instead.
+	ErrCRLimitReached = errors.New("batch already has the maximum number of current CRs")
+	// ErrAlreadyCR is returned when the student already holds a current term.
+	ErrAlreadyCR = errors.New("student is already a current CR")
+	// ErrCRNotCurrent is returned when ending or handing over a past term.
+	ErrCRNotCurrent = errors.New("CR term has already ended")
+	// ErrInvalidCRTerm is returned for terms that start in the future or end
+	// before they start.
+	ErrInvalidCRTerm = errors.New("term must start now or earlier and end after it starts")
+)
+
+// CRAppointment starts a CR term for a roster student.
+type CRAppointment struct {
+	StudentID uuid.UUID
+	TermStart *time.Time // Defaults to now
+	TermEnd   *time.Time // Open-ended when nil
+	Fb        string
+	ImageURL  string
+	// Replaces is a current term of the same batch to end in the same step.
+	Replaces    *uuid.UUID
+	AppointedBy uuid.UUID
+}
+
+// CRLifecycle appoints and hands over class representatives and ends their
+// terms, keeping Student.IsCR and the user's role (cr while a term is
+// current, student otherwise) in sync. Writes go through the CR, student
+// and user usecases, so they are audited, and each step commits together
+// with its cr.appointed/cr.term_ended events.
+type CRLifecycle struct {
+	repo        domain.CRRepository
+	crs         Usecase[domain.CR]
+	students    Usecase[domain.Student]
+	users       Usecase[domain.User]
+	outbox      domain.Outbox
+	events      *eventbus.Bus
+	maxPerBatch int
+}
+
+// NewCRLifecycle allows up to maxPerBatch current CRs per batch (no limit
+// when it is 0) and, with a bus, updates the CR record and role of students
+// who claim their profile while serving a term.
+func NewCRLifecycle(repo domain.CRRepository, crs Usecase[domain.CR], students Usecase[domain.Student], users Usecase[domain.User], outbox domain.Outbox, events *eventbus.Bus, maxPerBatch int) *CRLifecycle {
+	l := &CRLifecycle{
+		repo:        repo,
+		crs:         crs,
+		students:    students,
+		users:       users,
+		outbox:      outbox,
+		events:      events,
+		maxPerBatch: maxPerBatch,
+	}
+	if events != nil {
+		eventbus.OnAsync(events, l.onProfileClaimed)
+	}
+	return l
+}
+
+// Appoint starts a term for a.StudentID in the student's batch, ending
+// a.Replaces first when set. It returns gorm.ErrRecordNotFound for unknown
+// students or terms.
+func (l *CRLifecycle) Appoint(ctx context.Context, a CRAppointment) (*domain.CR, error) {
+	now := time.Now()
+	start := now
+	if a.TermStart != nil {
+		start = *a.TermStart
+	}
+	if start.After(now) || (a.TermEnd != nil && !a.TermEnd.After(start)) {
+		return nil, ErrInvalidCRTerm
+	}
+
+	var (
+		cr    *domain.CR
+		event []domain.Event
+	)
+	err := l.outbox.Transaction(ctx, func(ctx context.Context) error {
+		student, err := l.students.GetByID(ctx, a.StudentID)
+		if err != nil {
+			return err
+		}
+		if err := l.repo.LockBatch(ctx, student.BatchID); err != nil {
+			return err
+		}
+
+		if a.Replaces != nil {
+			old, err := l.repo.GetByID(ctx, *a.Replaces)
+			if err != nil {
+				return err
+			}
+			if !old.IsCurrent || old.BatchID != student.BatchID {
+				return ErrCRNotCurrent
+			}
+			if err := l.end(ctx, old, now, domain.CREndHandover); err != nil {
+				return err
+			}
+			event = append(event, domain.CRTermEnded{CR: old})
+		}
+
+		current, err := l.repo.Current(ctx, student.BatchID)
+		if err != nil {
+			return err
+		}
+		for _, c := range current {
+			if c.TargetStudentID != nil && *c.TargetStudentID == student.ID {
+				return ErrAlreadyCR
+			}
+		}
+		if l.maxPerBatch > 0 && len(current) >= l.maxPerBatch {
+			return ErrCRLimitReached
+		}
+
+		cr = &domain.CR{
+			UserID:          student.UserID,
+			UniversityID:    student.UniversityID,
+			DepartmentID:    student.DepartmentID,
+			TargetStudentID: &student.ID,
+			Name:            student.Name,
+			StudentID:       student.StudentID,
+			Email:           student.Email,
+			Phone:           student.Phone,
+			BatchID:         student.BatchID,
+			TermStart:       &start,
+			TermEnd:         a.TermEnd,
+			Fb:              a.Fb,
+			ImageURL:        a.ImageURL,
+			IsCurrent:       true,
+		}
+		cr.CreatedByID = a.AppointedBy
+		if student.Batch != nil {
+			cr.Batch = student.Batch.Name
+		}
+		if err := l.crs.Create(ctx, cr); err != nil {
+			return err
+		}
+		if err := l.sync(ctx, student.ID); err != nil {
+			return err
+		}
+
+		event = append(event, domain.CRAppointed{CR: cr, Replaces: a.Replaces})
+		return l.outbox.Add(ctx, event...)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.publish(ctx, event...)
+	return cr, nil
+}
+
+// End ends a current term early with reason domain.CREndRemoved.
+func (l *CRLifecycle) End(ctx context.Context, id uuid.UUID) (*domain.CR, error) {
+	return l.endOne(ctx, id, time.Now(), domain.CREndRemoved, false)
+}
+
+// Remove deletes a CR record, ending its term first when it is current.
+func (l *CRLifecycle) Remove(ctx context.Context, id uuid.UUID) error {
+	cr, err := l.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if cr.IsCurrent {
+		if _, err := l.endOne(ctx, id, time.Now(), domain.CREndRemoved, false); err != nil && !errors.Is(err, ErrCRNotCurrent) {
+			return err
+		}
+	}
+	return l.crs.Delete(ctx, id)
+}
+
+// Update saves the descriptive fields of a term (name, contact details,
+// photo) and, while it is current, its TermEnd. The student, batch, term
+// start and status only change through Appoint and End.
+func (l *CRLifecycle) Update(ctx context.Context, changes *domain.CR) (*domain.CR, error) {
+	cr, err := l.repo.GetByID(ctx, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+	if cr.IsCurrent {
+		if changes.TermEnd != nil && cr.TermStart != nil && !changes.TermEnd.After(*cr.TermStart) {
+			return nil, ErrInvalidCRTerm
+		}
+		cr.TermEnd = changes.TermEnd
+	}
+	cr.Name = changes.Name
+	cr.Email = changes.Email
+	cr.Phone = changes.Phone
+	cr.Fb = changes.Fb
+	cr.ImageURL = changes.ImageURL
+	cr.UpdatedByID = changes.UpdatedByID
+	cr.User, cr.TargetStudent = nil, nil
+
+	if err := l.crs.Update(ctx, cr); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// EndBatch ends every current term of a batch, e.g. when it graduates, and
+// returns how many it ended.
+func (l *CRLifecycle) EndBatch(ctx context.Context, batchID uuid.UUID, reason string) (int, error) {
+	current, err := l.repo.Current(ctx, batchID)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	for _, cr := range current {
+		_, err := l.endOne(ctx, cr.ID, time.Now(), reason, false)
+		if errors.Is(err, ErrCRNotCurrent) {
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// Current lists the current terms of a batch.
+func (l *CRLifecycle) Current(ctx context.Context, batchID uuid.UUID) ([]domain.CR, error) {
+	return l.repo.Current(ctx, batchID)
+}
+
+// History lists every term of a batch, current ones first, then newest.
+func (l *CRLifecycle) History(ctx context.Context, batchID uuid.UUID) ([]domain.CR, error) {
+	return l.repo.History(ctx, batchID)
+}
+
+// StartExpiry ends terms whose TermEnd has passed every interval in the
+// background.
+func (l *CRLifecycle) StartExpiry(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if n, err := l.ExpireDue(context.Background()); err != nil {
+				logger.Errorf("CR term expiry failed after %d terms: %v", n, err)
+			} else if n > 0 {
+				logger.Infof("expired %d CR terms", n)
+			}
+		}
+	}()
+}
+
+// ExpireDue ends every current term whose TermEnd has passed and returns
+// how many it ended. Terms are ended one per transaction, so a failure
+// leaves the rest for the next run.
+func (l *CRLifecycle) ExpireDue(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := l.repo.DueExpiry(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for _, cr := range due {
+		_, err := l.endOne(ctx, cr.ID, now, domain.CREndExpired, true)
+		if errors.Is(err, ErrCRNotCurrent) {
+			// Ended meanwhile, e.g. by another instance
+			continue
+		}
+		if err != nil {
+			return n, err
+		}
+		n++
+	}
+	return n, nil
+}
+
+// endOne ends term id under the batch lock. With onlyDue, terms whose
+// TermEnd was moved past now in the meantime are left alone.
+func (l *CRLifecycle) endOne(ctx context.Context, id uuid.UUID, now time.Time, reason string, onlyDue bool) (*domain.CR, error) {
+	var cr *domain.CR
+	err := l.outbox.Transaction(ctx, func(ctx context.Context) error {
+		found, err := l.repo.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := l.repo.LockBatch(ctx, found.BatchID); err != nil {
+			return err
+		}
+		// Re-read under the lock
+		if cr, err = l.repo.GetByID(ctx, id); err != nil {
+			return err
+		}
+		if !cr.IsCurrent || (onlyDue && (cr.TermEnd == nil || cr.TermEnd.After(now))) {
+			return ErrCRNotCurrent
+		}
+		if err := l.end(ctx, cr, now, reason); err != nil {
+			return err
+		}
+		return l.outbox.Add(ctx, domain.CRTermEnded{CR: cr})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	l.publish(ctx, domain.CRTermEnded{CR: cr})
+	return cr, nil
+}
+
+// end closes cr at now (keeping an earlier TermEnd for expired terms) and
+// syncs its student.
+func (l *CRLifecycle) end(ctx context.Context, cr *domain.CR, now time.Time, reason string) error {
+	cr.IsCurrent = false
+	cr.EndReason = reason
+	if cr.TermEnd == nil || cr.TermEnd.After(now) {
+		cr.TermEnd = &now
+	}
+	cr.User, cr.TargetStudent = nil, nil
+	if err := l.crs.Update(ctx, cr); err != nil {
+		return err
+	}
+	if cr.TargetStudentID == nil {
+		return nil
+	}
+	return l.sync(ctx, *cr.TargetStudentID)
+}
+
+// sync sets Student.IsCR from the student's current terms and moves their
+// user between the student and cr roles. Other roles (admins, teachers)
+// are left as they are.
+func (l *CRLifecycle) sync(ctx context.Context, studentID uuid.UUID) error {
+	isCR, err := l.repo.IsCurrent(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	student, err := l.students.GetByID(ctx, studentID)
+	if err != nil {
+		return err
+	}
+	if student.IsCR != isCR {
+		student.IsCR = isCR
+		if err := l.students.Update(ctx, student); err != nil {
+			return err
+		}
+	}
+	if student.UserID == nil {
+		return nil
+	}
+
+	user, err := l.users.GetByID(ctx, *student.UserID)
+	if err != nil {
+		return err
+	}
+	role := domain.RoleStudent
+	if isCR {
+		role = domain.RoleCR
+	}
+	if user.Role == role || (user.Role != domain.RoleStudent && user.Role != domain.RoleCR) {
+		return nil
+	}
+	user.Role = role
+	return l.users.Update(ctx, user)
+}
+
+// onProfileClaimed links the current terms of a student who claims their
+// profile to the new account and gives it the cr role.
+func (l *CRLifecycle) onProfileClaimed(ctx context.Context, e domain.ProfileClaimed) error {
+	if e.ProfileType != "student" {
+		return nil
+	}
+	return l.outbox.Transaction(ctx, func(ctx context.Context) error {
+		terms, _, err := l.repo.GetAll(ctx, map[string]interface{}{"target_student_id": e.ProfileID, "is_current": true}, 100, 0)
+		if err != nil {
+			return err
+		}
+		for i := range terms {
+			cr := &terms[i]
+			if cr.UserID != nil && *cr.UserID == e.UserID {
+				continue
+			}
+			cr.UserID = &e.UserID
+			cr.User, cr.TargetStudent = nil, nil
+			if err := l.crs.Update(ctx, cr); err != nil {
+				return err
+			}
+		}
+		return l.sync(ctx, e.ProfileID)
+	})
+}
+
+// publish sends committed events to the in-process bus.
+func (l *CRLifecycle) publish(ctx context.Context, events ...domain.Event) {
+	for _, e := range events {
+		if err := l.events.Publish(ctx, e); err != nil {
+			logger.Errorf("%s subscribers failed: %v", e.EventName(), err)
+		}
+	}
+}
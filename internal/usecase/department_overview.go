@@ -0,0 +1,153 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/google/uuid"
+)
+
+// DepartmentOverview is everything the department home screen shows, in one
+// response. Sections that failed or missed the latency budget are listed in
+// Incomplete and left empty rather than failing the whole request.
+type DepartmentOverview struct {
+	Department    *domain.Department `json:"department"`
+	Batches       []BatchOverview    `json:"batches"`
+	Chairman      *domain.Teacher    `json:"chairman,omitempty"`
+	Teachers      []domain.Teacher   `json:"teachers"`
+	Staff         []domain.Staff     `json:"staff"`
+	CRs           []domain.CR        `json:"crs"`
+	CourseCount   int64              `json:"course_count"`
+	ResourceCount int64              `json:"resource_count"`
+	Incomplete    []string           `json:"incomplete,omitempty"`
+}
+
+// BatchOverview is an active batch with its head count.
+type BatchOverview struct {
+	domain.Batch
+	StudentCount int64 `json:"student_count"`
+}
+
+// DepartmentOverviewUsecase assembles a DepartmentOverview with all
+// sub-queries running in parallel under one deadline.
+type DepartmentOverviewUsecase struct {
+	repo   domain.DepartmentOverviewRepository
+	budget time.Duration
+}
+
+func NewDepartmentOverviewUsecase(repo domain.DepartmentOverviewRepository, budget time.Duration) *DepartmentOverviewUsecase {
+	return &DepartmentOverviewUsecase{repo: repo, budget: budget}
+}
+
+// Get returns the overview of department id. Only the department itself is
+// required: its lookup error (gorm.ErrRecordNotFound, or
+// context.DeadlineExceeded when the budget runs out) is returned as is.
+func (u *DepartmentOverviewUsecase) Get(ctx context.Context, id uuid.UUID) (*DepartmentOverview, error) {
+	ctx, cancel := context.WithTimeout(ctx, u.budget)
+	defer cancel()
+
+	o := &DepartmentOverview{
+		Batches:  []BatchOverview{},
+		Teachers: []domain.Teacher{},
+		Staff:    []domain.Staff{},
+		CRs:      []domain.CR{},
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		deptErr error
+	)
+	section := func(name string, fn func() error) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := fn(); err != nil {
+				logger.Errorf("department %s overview: %s: %v", id, name, err)
+				mu.Lock()
+				o.Incomplete = append(o.Incomplete, name)
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		o.Department, deptErr = u.repo.Department(ctx, id)
+	}()
+
+	section("batches", func() error {
+		batches, err := u.repo.ActiveBatches(ctx, id)
+		if err != nil {
+			return err
+		}
+		ids := make([]uuid.UUID, len(batches))
+		for i := range batches {
+			ids[i] = batches[i].ID
+		}
+		counts, err := u.repo.StudentCounts(ctx, ids)
+		if err != nil {
+			return err
+		}
+		out := make([]BatchOverview, len(batches))
+		for i := range batches {
+			out[i] = BatchOverview{Batch: batches[i], StudentCount: counts[batches[i].ID]}
+		}
+		o.Batches = out
+		return nil
+	})
+	section("teachers", func() error {
+		teachers, err := u.repo.Teachers(ctx, id)
+		if err != nil {
+			return err
+		}
+		// Absent chairmen are shown as chairman but not in the faculty list.
+		present := []domain.Teacher{}
+		for i := range teachers {
+			if teachers[i].IsChairman && o.Chairman == nil {
+				chairman := teachers[i]
+				o.Chairman = &chairman
+			}
+			if teachers[i].IsPresent {
+				present = append(present, teachers[i])
+			}
+		}
+		o.Teachers = present
+		return nil
+	})
+	section("staff", func() error {
+		staff, err := u.repo.Staff(ctx, id)
+		if err == nil {
+			o.Staff = staff
+		}
+		return err
+	})
+	section("crs", func() error {
+		crs, err := u.repo.CurrentCRs(ctx, id)
+		if err == nil {
+			o.CRs = crs
+		}
+		return err
+	})
+	section("course_count", func() (err error) {
+		o.CourseCount, err = u.repo.CountCourses(ctx, id)
+		return err
+	})
+	section("resource_count", func() (err error) {
+		o.ResourceCount, err = u.repo.CountResources(ctx, id)
+		return err
+	})
+
+	wg.Wait()
+	if deptErr != nil {
+		return nil, deptErr
+	}
+	sort.Strings(o.Incomplete)
+	return o, nil
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeOverviewRepo answers every section from fixed data. Sections named in
+// slow block until the context ends; ones in fail return their error.
+type fakeOverviewRepo struct {
+	department *domain.Department
+	batches    []domain.Batch
+	counts     map[uuid.UUID]int64
+	teachers   []domain.Teacher
+	staff      []domain.Staff
+	crs        []domain.CR
+	slow       map[string]bool
+	fail       map[string]error
+}
+
+func (r *fakeOverviewRepo) section(ctx context.Context, name string) error {
+	if r.slow[name] {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return r.fail[name]
+}
+
+func (r *fakeOverviewRepo) Department(ctx context.Context, _ uuid.UUID) (*domain.Department, error) {
+	if err := r.section(ctx, "department"); err != nil {
+		return nil, err
+	}
+	if r.department == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return r.department, nil
+}
+
+func (r *fakeOverviewRepo) ActiveBatches(ctx context.Context, _ uuid.UUID) ([]domain.Batch, error) {
+	return r.batches, r.section(ctx, "batches")
+}
+
+func (r *fakeOverviewRepo) StudentCounts(ctx context.Context, _ []uuid.UUID) (map[uuid.UUID]int64, error) {
+	return r.counts, r.section(ctx, "batches")
+}
+
+func (r *fakeOverviewRepo) Teachers(ctx context.Context, _ uuid.UUID) ([]domain.Teacher, error) {
+	return r.teachers, r.section(ctx, "teachers")
+}
+
+func (r *fakeOverviewRepo) Staff(ctx context.Context, _ uuid.UUID) ([]domain.Staff, error) {
+	return r.staff, r.section(ctx, "staff")
+}
+
+func (r *fakeOverviewRepo) CurrentCRs(ctx context.Context, _ uuid.UUID) ([]domain.CR, error) {
+	return r.crs, r.section(ctx, "crs")
+}
+
+func (r *fakeOverviewRepo) CountCourses(ctx context.Context, _ uuid.UUID) (int64, error) {
+	return 12, r.section(ctx, "course_count")
+}
+
+func (r *fakeOverviewRepo) CountResources(ctx context.Context, _ uuid.UUID) (int64, error) {
+	return 40, r.section(ctx, "resource_count")
+}
+
+func TestDepartmentOverview(t *testing.T) {
+	batch := domain.Batch{Base: domain.Base{ID: uuid.New()}, Name: "Batch 10"}
+	chairman := domain.Teacher{Base: domain.Base{ID: uuid.New()}, Name: "Dr. Rahman", IsChairman: true, IsPresent: false}
+	lecturer := domain.Teacher{Base: domain.Base{ID: uuid.New()}, Name: "Ms. Akter", IsPresent: true}
+	onLeave := domain.Teacher{Base: domain.Base{ID: uuid.New()}, Name: "Mr. Hasan", IsPresent: false}
+
+	newRepo := func() *fakeOverviewRepo {
+		return &fakeOverviewRepo{
+			department: &domain.Department{Base: domain.Base{ID: uuid.New()}, Name: "CSE"},
+			batches:    []domain.Batch{batch},
+			counts:     map[uuid.UUID]int64{batch.ID: 45},
+			teachers:   []domain.Teacher{chairman, lecturer, onLeave},
+			staff:      []domain.Staff{{Name: "Office Assistant"}},
+			crs:        []domain.CR{{}},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		slow           map[string]bool
+		fail           map[string]error
+		noDepartment   bool
+		wantErr        error
+		wantIncomplete []string
+		wantTeachers   []string
+		wantStaff      int
+	}{
+		{
+			name:         "all sections",
+			wantTeachers: []string{"Ms. Akter"},
+			wantStaff:    1,
+		},
+		{
+			name:           "slow section misses the budget",
+			slow:           map[string]bool{"staff": true},
+			wantIncomplete: []string{"staff"},
+			wantTeachers:   []string{"Ms. Akter"},
+		},
+		{
+			name:           "failing sections",
+			fail:           map[string]error{"crs": errors.New("db down"), "batches": errors.New("db down")},
+			wantIncomplete: []string{"batches", "crs"},
+			wantTeachers:   []string{"Ms. Akter"},
+			wantStaff:      1,
+		},
+		{
+			name:         "missing department",
+			noDepartment: true,
+			wantErr:      gorm.ErrRecordNotFound,
+		},
+		{
+			name:    "slow department",
+			slow:    map[string]bool{"department": true},
+			wantErr: context.DeadlineExceeded,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepo()
+			repo.slow, repo.fail = tt.slow, tt.fail
+			if tt.noDepartment {
+				repo.department = nil
+			}
+			uc := NewDepartmentOverviewUsecase(repo, 50*time.Millisecond)
+
+			o, err := uc.Get(context.Background(), uuid.New())
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Get() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			if !reflect.DeepEqual(o.Incomplete, tt.wantIncomplete) {
+				t.Errorf("Incomplete = %v, want %v", o.Incomplete, tt.wantIncomplete)
+			}
+			// The chairman is shown even when absent, but only present
+			// teachers are listed
+			if o.Chairman == nil || o.Chairman.ID != chairman.ID {
+				t.Errorf("Chairman = %v, want %s", o.Chairman, chairman.Name)
+			}
+			var names []string
+			for _, teacher := range o.Teachers {
+				names = append(names, teacher.Name)
+			}
+			if !reflect.DeepEqual(names, tt.wantTeachers) {
+				t.Errorf("Teachers = %v, want %v", names, tt.wantTeachers)
+			}
+			if len(o.Staff) != tt.wantStaff {
+				t.Errorf("%d staff, want %d", len(o.Staff), tt.wantStaff)
+			}
+			if o.Staff == nil || o.Batches == nil || o.CRs == nil {
+				t.Error("missing sections should be empty lists, not null")
+			}
+			if slices.Contains(tt.wantIncomplete, "batches") {
+				if len(o.Batches) != 0 {
+					t.Errorf("Batches = %v, want none from a failed section", o.Batches)
+				}
+			} else if len(o.Batches) != 1 || o.Batches[0].StudentCount != 45 {
+				t.Errorf("Batches = %+v, want Batch 10 with 45 students", o.Batches)
+			}
+		})
+	}
+}
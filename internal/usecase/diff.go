@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// FieldChange holds the before/after value of a single JSON field.
+type FieldChange struct {
+	Old interface{} `json:"old"`
+	New interface{} `json:"new"`
+}
+
+// Bookkeeping columns that change on every write and would only add noise.
+var diffIgnoredFields = map[string]bool{
+	"created_at":    true,
+	"updated_at":    true,
+	"deleted_at":    true,
+	"created_by_id": true,
+	"updated_by_id": true,
+}
+
+// toJSONMap flattens an entity to its JSON representation.
+// A nil interface or nil pointer yields an empty map.
+func toJSONMap(v interface{}) (map[string]interface{}, error) {
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return map[string]interface{}{}, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	out := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// diffEntities compares two entities field by field using their json tags.
+// Only fields present on `after` are considered, so associations omitted from
+// an update payload are not reported as removed. Either side may be nil.
+func diffEntities(before, after interface{}) (map[string]FieldChange, error) {
+	oldMap, err := toJSONMap(before)
+	if err != nil {
+		return nil, err
+	}
+	newMap, err := toJSONMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(map[string]FieldChange)
+
+	// Delete: snapshot every field of the old entity
+	if len(newMap) == 0 {
+		for key, oldVal := range oldMap {
+			if !diffIgnoredFields[key] && oldVal != nil {
+				changes[key] = FieldChange{Old: oldVal}
+			}
+		}
+		return changes, nil
+	}
+
+	for key, newVal := range newMap {
+		if diffIgnoredFields[key] {
+			continue
+		}
+		oldVal, existed := oldMap[key]
+		if existed && reflect.DeepEqual(oldVal, newVal) {
+			continue
+		}
+		if !existed && (newVal == nil || newVal == "") {
+			continue
+		}
+		changes[key] = FieldChange{Old: oldVal, New: newVal}
+	}
+	return changes, nil
+}
@@ -0,0 +1,354 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/ical"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// NotificationExamReminder is the type of the reminders Exams sends to a
+// batch ahead of each exam.
+const NotificationExamReminder = "EXAM_REMINDER"
+
+var (
+	// ErrInvalidExam is returned for an unknown exam type or an exam that
+	// does not end after it starts.
+	ErrInvalidExam = errors.New("exams need a type of midterm, final, lab or quiz and must end after they start")
+	// ErrChapterNotInCourse is returned when an exam's syllabus lists a
+	// chapter of another course.
+	ErrChapterNotInCourse = errors.New("syllabus chapters must belong to the exam's course")
+)
+
+// Exams manages batches' exam schedules. Academic admins and the batch's
+// CRs write them; SendReminders notifies the batch's students ahead of
+// each exam.
+type Exams struct {
+	exams         Usecase[domain.Exam]
+	repo          domain.ExamRepository
+	courses       Usecase[domain.Course]
+	batches       Usecase[domain.Batch]
+	chapters      Usecase[domain.Chapter]
+	students      Usecase[domain.Student]
+	notifications domain.Repository[domain.Notification]
+	outbox        domain.Outbox
+	leads         []time.Duration
+	loc           *time.Location
+}
+
+// NewExams reminds students each of leads ahead of an exam and formats
+// exam times in loc.
+func NewExams(exams Usecase[domain.Exam], repo domain.ExamRepository, courses Usecase[domain.Course], batches Usecase[domain.Batch], chapters Usecase[domain.Chapter], students Usecase[domain.Student], notifications domain.Repository[domain.Notification], outbox domain.Outbox, leads []time.Duration, loc *time.Location) *Exams {
+	leads = slices.Clone(leads)
+	slices.Sort(leads)
+	return &Exams{
+		exams:         exams,
+		repo:          repo,
+		courses:       courses,
+		batches:       batches,
+		chapters:      chapters,
+		students:      students,
+		notifications: notifications,
+		outbox:        outbox,
+		leads:         leads,
+		loc:           loc,
+	}
+}
+
+// Create schedules an exam and sets its syllabus from exam.ChapterIDs.
+func (e *Exams) Create(ctx context.Context, exam *domain.Exam) (*domain.Exam, error) {
+	if err := e.prepare(ctx, exam); err != nil {
+		return nil, err
+	}
+
+	err := e.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := e.exams.Create(ctx, exam); err != nil {
+			return err
+		}
+		return e.repo.SetChapters(ctx, exam.ID, exam.ChapterIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e.exams.GetByID(ctx, exam.ID)
+}
+
+// Update reschedules an exam. The syllabus is replaced when chapter_ids
+// is given, and moving the exam sends its reminders again.
+func (e *Exams) Update(ctx context.Context, changes *domain.Exam) (*domain.Exam, error) {
+	exam, err := e.exams.GetByID(ctx, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeBatchEditor(ctx, e.students, exam.BatchID); err != nil {
+		return nil, err
+	}
+
+	moved := !exam.StartsAt.Equal(changes.StartsAt)
+	exam.CourseID = changes.CourseID
+	exam.BatchID = changes.BatchID
+	exam.Type = changes.Type
+	exam.Title = changes.Title
+	exam.StartsAt = changes.StartsAt
+	exam.EndsAt = changes.EndsAt
+	exam.Room = changes.Room
+	exam.Note = changes.Note
+	exam.ChapterIDs = changes.ChapterIDs
+	exam.UpdatedByID = changes.UpdatedByID
+	if err := e.prepare(ctx, exam); err != nil {
+		return nil, err
+	}
+
+	err = e.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := e.exams.Update(ctx, exam); err != nil {
+			return err
+		}
+		if exam.ChapterIDs != nil {
+			if err := e.repo.SetChapters(ctx, exam.ID, exam.ChapterIDs); err != nil {
+				return err
+			}
+		}
+		if moved {
+			return e.repo.ClearReminders(ctx, exam.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return e.exams.GetByID(ctx, exam.ID)
+}
+
+// Delete cancels an exam.
+func (e *Exams) Delete(ctx context.Context, id uuid.UUID) error {
+	exam, err := e.exams.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeBatchEditor(ctx, e.students, exam.BatchID); err != nil {
+		return err
+	}
+	return e.exams.Delete(ctx, id)
+}
+
+// Upcoming lists the batch's exams that have not ended, soonest first,
+// optionally of one type.
+func (e *Exams) Upcoming(ctx context.Context, batchID uuid.UUID, examType string, limit, offset int) ([]domain.Exam, int64, error) {
+	return e.repo.Schedule(ctx, batchID, time.Now(), examType, limit, offset)
+}
+
+// Calendar returns the batch's exams from a month ago on as a calendar
+// feed.
+func (e *Exams) Calendar(ctx context.Context, batchID uuid.UUID) (ical.Calendar, error) {
+	batch, err := e.batches.GetByID(ctx, batchID)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	exams, _, err := e.repo.Schedule(ctx, batchID, time.Now().AddDate(0, 0, -feedDaysBack), "", 500, 0)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+
+	cal := ical.Calendar{Name: batch.Name + " exams", Events: []ical.Event{}}
+	for i := range exams {
+		cal.Events = append(cal.Events, examEvent(&exams[i]))
+	}
+	return cal, nil
+}
+
+// StartReminders sends due exam reminders every interval until the process
+// exits.
+func (e *Exams) StartReminders(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if n, err := e.SendReminders(context.Background(), time.Now()); err != nil {
+				logger.Errorf("exam reminders failed after %d exams: %v", n, err)
+			} else if n > 0 {
+				logger.Infof("sent reminders for %d exams", n)
+			}
+		}
+	}()
+}
+
+// SendReminders notifies the students of every batch with an exam starting
+// within one of the reminder leads of now that it has not been reminded
+// of, and returns how many exams it sent reminders for. An exam scheduled
+// at short notice gets one reminder, for the shortest lead it is within.
+// Each exam's reminders commit in their own transaction.
+func (e *Exams) SendReminders(ctx context.Context, now time.Time) (int, error) {
+	if len(e.leads) == 0 {
+		return 0, nil
+	}
+	exams, err := e.repo.Starting(ctx, now, now.Add(e.leads[len(e.leads)-1]))
+	if err != nil {
+		return 0, err
+	}
+
+	n := 0
+	for i := range exams {
+		sent, err := e.remind(ctx, &exams[i], now)
+		if err != nil {
+			return n, err
+		}
+		if sent {
+			n++
+		}
+	}
+	return n, nil
+}
+
+func (e *Exams) remind(ctx context.Context, exam *domain.Exam, now time.Time) (bool, error) {
+	reminded, err := e.repo.Reminded(ctx, exam.ID)
+	if err != nil {
+		return false, err
+	}
+	var due []domain.ExamReminder
+	for _, lead := range e.leads {
+		hours := int(lead / time.Hour)
+		if exam.StartsAt.Sub(now) <= lead && !slices.Contains(reminded, hours) {
+			due = append(due, domain.ExamReminder{ExamID: exam.ID, LeadHours: hours})
+		}
+	}
+	if len(due) == 0 {
+		return false, nil
+	}
+
+	var users []uuid.UUID
+	err = e.students.Stream(ctx, map[string]interface{}{"batch_id": exam.BatchID}, func(s *domain.Student) error {
+		if s.UserID != nil {
+			users = append(users, *s.UserID)
+		}
+		return nil
+	})
+	if err != nil {
+		return false, err
+	}
+
+	raw, err := json.Marshal(map[string]string{
+		"exam_id":   exam.ID.String(),
+		"starts_at": exam.StartsAt.Format(time.RFC3339),
+	})
+	if err != nil {
+		return false, err
+	}
+	data := datatypes.JSON(raw)
+	body := exam.StartsAt.In(e.loc).Format("Mon 2 Jan 15:04")
+	if exam.Room != "" {
+		body += ", " + exam.Room
+	}
+
+	sent := false
+	err = e.outbox.Transaction(ctx, func(ctx context.Context) error {
+		added, err := e.repo.AddReminders(ctx, due)
+		if err != nil || added == 0 {
+			return err
+		}
+		for _, uid := range users {
+			err := e.notifications.Create(ctx, &domain.Notification{
+				UserID: uid,
+				Title:  "Upcoming exam: " + examName(exam),
+				Body:   body,
+				Type:   NotificationExamReminder,
+				Data:   &data,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		sent = true
+		return nil
+	})
+	return sent, err
+}
+
+// prepare checks an exam, copies the batch's university and department and
+// makes sure the syllabus chapters belong to the course.
+func (e *Exams) prepare(ctx context.Context, exam *domain.Exam) error {
+	exam.Type = strings.ToLower(strings.TrimSpace(exam.Type))
+	if !slices.Contains(domain.ExamTypes, exam.Type) || !exam.EndsAt.After(exam.StartsAt) {
+		return ErrInvalidExam
+	}
+	batch, err := e.batches.GetByID(ctx, exam.BatchID)
+	if err != nil {
+		return err
+	}
+	if err := authorizeBatchEditor(ctx, e.students, batch.ID); err != nil {
+		return err
+	}
+	course, err := e.courses.GetByID(ctx, exam.CourseID)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range exam.ChapterIDs {
+		chapter, err := e.chapters.GetByID(ctx, id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrChapterNotInCourse
+		}
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(chapter.CourseCode, course.CourseCode) {
+			return ErrChapterNotInCourse
+		}
+	}
+
+	exam.UniversityID = batch.UniversityID
+	exam.DepartmentID = batch.DepartmentID
+	exam.Course, exam.Chapters = nil, nil
+	return nil
+}
+
+// examName is "CSE101 midterm", or the course code and title when set.
+func examName(exam *domain.Exam) string {
+	name := exam.Type
+	if exam.Title != "" {
+		name = exam.Title
+	}
+	if exam.Course != nil {
+		name = exam.Course.CourseCode + " " + name
+	}
+	return name
+}
+
+// examEvent turns an exam into a feed event with its syllabus in the
+// description.
+func examEvent(exam *domain.Exam) ical.Event {
+	chapters := slices.Clone(exam.Chapters)
+	sort.Slice(chapters, func(i, j int) bool { return chapters[i].ChapterNo < chapters[j].ChapterNo })
+	var notes []string
+	if exam.Course != nil {
+		notes = append(notes, exam.Course.CourseTitle)
+	}
+	for _, c := range chapters {
+		notes = append(notes, fmt.Sprintf("Chapter %d: %s", c.ChapterNo, c.ChapterTitle))
+	}
+	if exam.Note != "" {
+		notes = append(notes, exam.Note)
+	}
+
+	return ical.Event{
+		UID:         "exam-" + exam.ID.String() + "@campusassistant",
+		Summary:     examName(exam),
+		Description: strings.Join(notes, "\n"),
+		Location:    exam.Room,
+		Start:       exam.StartsAt,
+		End:         exam.EndsAt,
+		Status:      ical.StatusConfirmed,
+		Updated:     exam.UpdatedAt,
+	}
+}
@@ -2,8 +2,12 @@ package usecase
 
 import (
 	"context"
+	"errors"
+	"reflect"
 
 	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/eventbus"
+	"campusassistant-api/pkg/logger"
 
 	"github.com/google/uuid"
 )
@@ -14,19 +18,102 @@ type Usecase[T any] interface {
 	GetAll(ctx context.Context, filter map[string]interface{}, limit, offset int) ([]T, int64, error)
 	Update(ctx context.Context, entity *T) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Stream calls fn for every entity matching filter, without the page cap.
+	Stream(ctx context.Context, filter map[string]interface{}, fn func(*T) error) error
+}
+
+// Options holds the optional collaborators of a generic usecase.
+type Options struct {
+	Auditor     *AuditRecorder
+	Revisions   domain.RevisionRepository
+	SlugAliases domain.SlugAliasRepository
+	Events      *eventbus.Bus
+	Outbox      domain.Outbox
+	ClaimCodes  *ClaimCodes
+}
+
+// Option configures a generic usecase.
+type Option func(*Options)
+
+// WithAuditor records an AuditLog entry for every Create/Update/Delete.
+func WithAuditor(a *AuditRecorder) Option {
+	return func(o *Options) {
+		o.Auditor = a
+	}
+}
+
+// WithRevisions keeps JSON snapshots of entities implementing domain.Versioned.
+func WithRevisions(repo domain.RevisionRepository) Option {
+	return func(o *Options) {
+		o.Revisions = repo
+	}
+}
+
+// WithSlugAliases lets GetBySlug find entities by slugs retired on rename.
+func WithSlugAliases(repo domain.SlugAliasRepository) Option {
+	return func(o *Options) {
+		o.SlugAliases = repo
+	}
+}
+
+// WithEvents publishes EntityCreated/Updated/Deleted events after each write.
+func WithEvents(bus *eventbus.Bus) Option {
+	return func(o *Options) {
+		o.Events = bus
+	}
+}
+
+// WithOutbox stores EntityCreated/Updated/Deleted events in the outbox in
+// the same transaction as the write, for the relay to dispatch.
+func WithOutbox(outbox domain.Outbox) Option {
+	return func(o *Options) {
+		o.Outbox = outbox
+	}
 }
 
 type genericUsecase[T any] struct {
-	repo domain.Repository[T]
+	repo       domain.Repository[T]
+	opts       Options
+	entityName string
 }
 
-func NewGenericUsecase[T any](repo domain.Repository[T]) Usecase[T] {
-	return &genericUsecase[T]{repo: repo}
+func NewGenericUsecase[T any](repo domain.Repository[T], opts ...Option) Usecase[T] {
+	u := &genericUsecase[T]{
+		repo:       repo,
+		entityName: reflect.TypeOf(new(T)).Elem().Name(),
+	}
+	for _, opt := range opts {
+		opt(&u.opts)
+	}
+	return u
 }
 
 func (u *genericUsecase[T]) Create(ctx context.Context, entity *T) error {
 	// Add business logic/validation here if needed
-	return u.repo.Create(ctx, entity)
+	if err := u.claimCode(ctx, entity, true); err != nil {
+		return err
+	}
+
+	event := domain.EntityCreated[T]{Entity: entity}
+	slug := requestedSlug(entity)
+	for attempt := 1; ; attempt++ {
+		err := u.write(ctx, event, func(ctx context.Context) error {
+			return u.repo.Create(ctx, entity)
+		})
+		if err == nil {
+			break
+		}
+		if !u.retry(ctx, entity, slug, err, attempt) {
+			return err
+		}
+	}
+
+	u.afterCommit(ctx, func(ctx context.Context) {
+		u.audit(ctx, domain.AuditActionCreate, entityID(entity), nil, entity)
+		u.snapshot(ctx, domain.AuditActionCreate, entityID(entity), entity)
+		u.publish(ctx, event)
+	})
+	return nil
 }
 
 func (u *genericUsecase[T]) GetByID(ctx context.Context, id uuid.UUID) (*T, error) {
@@ -37,10 +124,176 @@ func (u *genericUsecase[T]) GetAll(ctx context.Context, filter map[string]interf
 	return u.repo.GetAll(ctx, filter, limit, offset)
 }
 
+func (u *genericUsecase[T]) Stream(ctx context.Context, filter map[string]interface{}, fn func(*T) error) error {
+	if s, ok := u.repo.(domain.Streamer[T]); ok {
+		return s.Stream(ctx, filter, fn)
+	}
+
+	// Fallback for repositories without cursor support: walk page by page.
+	const pageSize = 500
+	for offset := 0; ; offset += pageSize {
+		page := make(map[string]interface{}, len(filter))
+		for k, v := range filter {
+			page[k] = v
+		}
+		entities, _, err := u.repo.GetAll(ctx, page, pageSize, offset)
+		if err != nil {
+			return err
+		}
+		for i := range entities {
+			if err := fn(&entities[i]); err != nil {
+				return err
+			}
+		}
+		if len(entities) < pageSize {
+			return nil
+		}
+	}
+}
+
 func (u *genericUsecase[T]) Update(ctx context.Context, entity *T) error {
-	return u.repo.Update(ctx, entity)
+	return u.update(ctx, entity, domain.AuditActionUpdate)
+}
+
+func (u *genericUsecase[T]) update(ctx context.Context, entity *T, action string) error {
+	// Capture the previous state for the audit diff
+	var before *T
+	if u.opts.Auditor != nil {
+		before, _ = u.repo.GetByID(ctx, entityID(entity))
+	}
+
+	if err := u.claimCode(ctx, entity, false); err != nil {
+		return err
+	}
+
+	event := domain.EntityUpdated[T]{Entity: entity, Before: before}
+	slug := requestedSlug(entity)
+	for attempt := 1; ; attempt++ {
+		err := u.write(ctx, event, func(ctx context.Context) error {
+			return u.repo.Update(ctx, entity)
+		})
+		if err == nil {
+			break
+		}
+		if !u.retry(ctx, entity, slug, err, attempt) {
+			return err
+		}
+	}
+
+	u.afterCommit(ctx, func(ctx context.Context) {
+		u.audit(ctx, action, entityID(entity), before, entity)
+		u.snapshot(ctx, action, entityID(entity), entity)
+		u.publish(ctx, event)
+	})
+	return nil
 }
 
 func (u *genericUsecase[T]) Delete(ctx context.Context, id uuid.UUID) error {
-	return u.repo.Delete(ctx, id)
+	var before *T
+	if u.opts.Auditor != nil || u.versioned() {
+		before, _ = u.repo.GetByID(ctx, id)
+	}
+
+	event := domain.EntityDeleted[T]{ID: id, Entity: before}
+	if err := u.write(ctx, event, func(ctx context.Context) error {
+		return u.repo.Delete(ctx, id)
+	}); err != nil {
+		return err
+	}
+
+	u.afterCommit(ctx, func(ctx context.Context) {
+		u.audit(ctx, domain.AuditActionDelete, id, before, nil)
+		if before != nil {
+			u.snapshot(ctx, domain.AuditActionDelete, id, before)
+		}
+		u.publish(ctx, event)
+	})
+	return nil
+}
+
+// slugAttempts bounds how often a write is repeated because concurrent
+// writes kept taking the slug it was given.
+const slugAttempts = 5
+
+// retry reports whether a write that failed with err should be repeated
+// because a concurrent write took the slug or claim code it was given. The
+// slug goes back to the requested one so the hooks pick the next free one.
+func (u *genericUsecase[T]) retry(ctx context.Context, entity *T, slug string, err error, attempt int) bool {
+	if errors.Is(err, domain.ErrSlugTaken) && attempt < slugAttempts {
+		domain.RestoreSlug(entity, slug)
+		return true
+	}
+	return u.retryClaimCode(ctx, entity, err, attempt)
+}
+
+// requestedSlug returns the slug a slugged entity was submitted with.
+func requestedSlug(entity any) string {
+	if s, ok := entity.(domain.Slugged); ok {
+		return s.GetSlug()
+	}
+	return ""
+}
+
+// inTransaction runs fn in one transaction when an outbox is configured, so
+// several writes commit together.
+func (u *genericUsecase[T]) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if u.opts.Outbox == nil {
+		return fn(ctx)
+	}
+	return u.opts.Outbox.Transaction(ctx, fn)
+}
+
+// write runs op and, with an outbox configured, stores event in the same
+// transaction so the change and its event commit or roll back together.
+// The event is serialized after op, so it carries generated IDs and slugs.
+func (u *genericUsecase[T]) write(ctx context.Context, event domain.Event, op func(ctx context.Context) error) error {
+	if u.opts.Outbox == nil {
+		return op(ctx)
+	}
+	return u.opts.Outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := op(ctx); err != nil {
+			return err
+		}
+		return u.opts.Outbox.Add(ctx, event)
+	})
+}
+
+// afterCommit runs the side effects of a write (audit, revision, bus event)
+// once the transaction the caller may have opened around it commits, so a
+// write that is rolled back with it leaves no trace.
+func (u *genericUsecase[T]) afterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if u.opts.Outbox == nil {
+		fn(ctx)
+		return
+	}
+	u.opts.Outbox.AfterCommit(ctx, fn)
+}
+
+// publish sends an event to the in-process bus. The write has already
+// happened, so failing sync subscribers are logged rather than returned.
+func (u *genericUsecase[T]) publish(ctx context.Context, e eventbus.Event) {
+	if err := u.opts.Events.Publish(ctx, e); err != nil {
+		logger.Errorf("%s subscribers failed: %v", e.EventName(), err)
+	}
+}
+
+func (u *genericUsecase[T]) audit(ctx context.Context, action string, id uuid.UUID, before, after *T) {
+	if u.opts.Auditor == nil {
+		return
+	}
+
+	changes, err := diffEntities(before, after)
+	if err != nil {
+		changes = nil
+	}
+
+	u.opts.Auditor.Record(ctx, action, u.entityName, id, changes)
+}
+
+// entityID returns the primary key of entities embedding domain.Base.
+func entityID[T any](entity *T) uuid.UUID {
+	if e, ok := any(entity).(domain.Entity); ok {
+		return e.GetID()
+	}
+	return uuid.Nil
 }
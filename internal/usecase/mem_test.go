@@ -0,0 +1,208 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+// memRepo is an in-memory domain.Repository. GetAll matches filter keys
+// against the JSON field names, which equal the column names of the models.
+type memRepo[T any] struct {
+	mu   sync.Mutex
+	rows []*T
+	fail func(*T) error // makes Create and Update fail for some entities
+}
+
+func newMemRepo[T any](rows ...T) *memRepo[T] {
+	r := &memRepo[T]{}
+	for i := range rows {
+		r.rows = append(r.rows, &rows[i])
+	}
+	return r
+}
+
+func (r *memRepo[T]) Create(_ context.Context, e *T) error {
+	if r.fail != nil {
+		if err := r.fail(e); err != nil {
+			return err
+		}
+	}
+	if ent, ok := any(e).(interface{ SetID(uuid.UUID) }); ok && entityID(e) == uuid.Nil {
+		ent.SetID(uuid.New())
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rows = append(r.rows, e)
+	return nil
+}
+
+func (r *memRepo[T]) GetByID(_ context.Context, id uuid.UUID) (*T, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, row := range r.rows {
+		if entityID(row) == id {
+			copied := *row
+			return &copied, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memRepo[T]) GetAll(_ context.Context, filter map[string]interface{}, limit, offset int) ([]T, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []T
+	for _, row := range r.rows {
+		if matches(row, filter) {
+			found = append(found, *row)
+		}
+	}
+	count := int64(len(found))
+	if offset < len(found) {
+		found = found[offset:]
+	} else {
+		found = nil
+	}
+	if limit > 0 && limit < len(found) {
+		found = found[:limit]
+	}
+	return found, count, nil
+}
+
+func (r *memRepo[T]) Update(_ context.Context, e *T) error {
+	if r.fail != nil {
+		if err := r.fail(e); err != nil {
+			return err
+		}
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, row := range r.rows {
+		if entityID(row) == entityID(e) {
+			copied := *e
+			r.rows[i] = &copied
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memRepo[T]) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, row := range r.rows {
+		if entityID(row) == id {
+			r.rows = append(r.rows[:i], r.rows[i+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memRepo[T]) len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.rows)
+}
+
+func matches(row any, filter map[string]interface{}) bool {
+	raw, err := json.Marshal(row)
+	if err != nil {
+		return false
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return false
+	}
+	for key, want := range filter {
+		if fmt.Sprint(fields[key]) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return true
+}
+
+// memAuditRepo is an in-memory domain.AuditLogRepository.
+type memAuditRepo struct {
+	*memRepo[domain.AuditLog]
+}
+
+func newMemAuditRepo() *memAuditRepo {
+	return &memAuditRepo{memRepo: newMemRepo[domain.AuditLog]()}
+}
+
+func (r *memAuditRepo) CreateBatch(ctx context.Context, logs []domain.AuditLog) error {
+	for i := range logs {
+		if err := r.Create(ctx, &logs[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memOutbox is a domain.Outbox without a database: a failing transaction
+// writes nothing to it and drops the hooks registered inside, like a
+// rolled back one. Repositories are not rolled back.
+type memOutbox struct {
+	mu     sync.Mutex
+	events []domain.Event
+}
+
+type memTxKey struct{}
+
+type memTx struct {
+	hooks  []func(ctx context.Context)
+	events []domain.Event
+}
+
+func (o *memOutbox) Transaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	tx := &memTx{}
+	if err := fn(context.WithValue(ctx, memTxKey{}, tx)); err != nil {
+		return err
+	}
+	if parent, ok := ctx.Value(memTxKey{}).(*memTx); ok {
+		parent.hooks = append(parent.hooks, tx.hooks...)
+		parent.events = append(parent.events, tx.events...)
+		return nil
+	}
+
+	o.mu.Lock()
+	o.events = append(o.events, tx.events...)
+	o.mu.Unlock()
+	for _, hook := range tx.hooks {
+		hook(ctx)
+	}
+	return nil
+}
+
+func (o *memOutbox) AfterCommit(ctx context.Context, fn func(ctx context.Context)) {
+	if tx, ok := ctx.Value(memTxKey{}).(*memTx); ok {
+		tx.hooks = append(tx.hooks, fn)
+		return
+	}
+	fn(ctx)
+}
+
+func (o *memOutbox) Add(ctx context.Context, events ...domain.Event) error {
+	if tx, ok := ctx.Value(memTxKey{}).(*memTx); ok {
+		tx.events = append(tx.events, events...)
+		return nil
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, events...)
+	return nil
+}
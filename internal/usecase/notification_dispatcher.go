@@ -0,0 +1,127 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Notification types written by NotificationDispatcher.
+const (
+	NotificationResourceApproved = "RESOURCE_APPROVED"
+	NotificationResourceRejected = "RESOURCE_REJECTED"
+	NotificationAppointment      = "APPOINTMENT" // Data carries the status and the calendar file
+)
+
+// NotificationDispatcher stores in-app notifications: for the uploader when
+// a submission is reviewed, and for the student and teacher of an
+// office-hour appointment, with its calendar file attached. Push delivery
+// hangs off the stored record. Notifications are keyed on the outbox event,
+// so an event the relay hands over again does not notify twice.
+type NotificationDispatcher struct {
+	repo domain.NotificationRepository
+	loc  *time.Location
+}
+
+// NewNotificationDispatcher writes times in notification texts in loc.
+func NewNotificationDispatcher(repo domain.NotificationRepository, loc *time.Location) *NotificationDispatcher {
+	return &NotificationDispatcher{repo: repo, loc: loc}
+}
+
+func (d *NotificationDispatcher) Name() string { return "notifications" }
+
+// Dispatch handles resource review and appointment events and ignores
+// everything else.
+func (d *NotificationDispatcher) Dispatch(ctx context.Context, e *domain.OutboxEvent) error {
+	switch e.EventName {
+	case domain.ResourceApproved{}.EventName():
+		var ev domain.ResourceApproved
+		if err := json.Unmarshal(e.Payload, &ev); err != nil {
+			return err
+		}
+		return d.notifyUploader(ctx, e.ID, ev.Resource, NotificationResourceApproved,
+			"Your submission was approved! 🎉", ev.Resource.Title)
+	case domain.ResourceRejected{}.EventName():
+		var ev domain.ResourceRejected
+		if err := json.Unmarshal(e.Payload, &ev); err != nil {
+			return err
+		}
+		return d.notifyUploader(ctx, e.ID, ev.Resource, NotificationResourceRejected,
+			"Your submission needs revision ❌", ev.Reason)
+	case domain.AppointmentRequested{}.EventName():
+		return d.notifyAppointment(ctx, e.ID, e.Payload, false, true, "New appointment request")
+	case domain.AppointmentApproved{}.EventName():
+		return d.notifyAppointment(ctx, e.ID, e.Payload, true, true, "Appointment confirmed ✅")
+	case domain.AppointmentDeclined{}.EventName():
+		return d.notifyAppointment(ctx, e.ID, e.Payload, true, false, "Appointment declined")
+	case domain.AppointmentCancelled{}.EventName():
+		return d.notifyAppointment(ctx, e.ID, e.Payload, true, true, "Appointment cancelled")
+	}
+	return nil
+}
+
+// notifyAppointment notifies the student and/or the teacher (when they
+// have an account) with the appointment's calendar file. All appointment
+// events share the {"appointment": ...} payload.
+func (d *NotificationDispatcher) notifyAppointment(ctx context.Context, eventID uuid.UUID, payload []byte, student, teacher bool, title string) error {
+	var ev domain.AppointmentRequested
+	if err := json.Unmarshal(payload, &ev); err != nil {
+		return err
+	}
+	a := ev.Appointment
+	if a == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(map[string]string{
+		"appointment_id": a.ID.String(),
+		"status":         a.Status,
+		"ics":            string(AppointmentCalendar(a).Bytes()),
+	})
+	if err != nil {
+		return err
+	}
+	data := datatypes.JSON(raw)
+
+	when := a.StartsAt.In(d.loc).Format("Mon 2 Jan 15:04")
+	var recipients []domain.Notification
+	if student {
+		recipients = append(recipients, domain.Notification{UserID: a.StudentUserID, Body: a.TeacherName + ", " + when})
+	}
+	if teacher && a.TeacherUserID != nil {
+		recipients = append(recipients, domain.Notification{UserID: *a.TeacherUserID, Body: a.StudentName + ", " + when})
+	}
+	for _, n := range recipients {
+		n.Title, n.Type, n.Data, n.EventID = title, NotificationAppointment, &data, &eventID
+		if err := d.repo.CreateOnce(ctx, &n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *NotificationDispatcher) notifyUploader(ctx context.Context, eventID uuid.UUID, r *domain.Resource, kind, title, body string) error {
+	if r == nil || r.UploaderID == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(map[string]string{"resource_id": r.ID.String()})
+	if err != nil {
+		return err
+	}
+	data := datatypes.JSON(raw)
+
+	return d.repo.CreateOnce(ctx, &domain.Notification{
+		UserID:  *r.UploaderID,
+		Title:   title,
+		Body:    body,
+		Type:    kind,
+		Data:    &data,
+		EventID: &eventID,
+	})
+}
@@ -0,0 +1,485 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/eventbus"
+	"campusassistant-api/pkg/ical"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrNotOfficeHourOwner is returned when someone other than the teacher
+	// or an academic admin manages office hours or answers a booking.
+	ErrNotOfficeHourOwner = errors.New("only the teacher or an academic admin can do this")
+	// ErrInvalidOfficeHour is returned for a bad weekday, times or slot
+	// length.
+	ErrInvalidOfficeHour = errors.New("office hours need a weekday from 0 (Sunday) to 6, HH:MM times with start before end, and a slot length that fits")
+	// ErrSlotUnavailable is returned when booking a time the office hours do
+	// not offer, or one in the past.
+	ErrSlotUnavailable = errors.New("that time is not an open office-hour slot")
+	// ErrSlotTaken is returned when the teacher already has a booking then.
+	ErrSlotTaken = errors.New("slot is already booked")
+	// ErrStudentBusy is returned when the student already has an
+	// appointment at that time.
+	ErrStudentBusy = errors.New("you already have an appointment at that time")
+	// ErrAppointmentClosed is returned when answering or cancelling an
+	// appointment in a state that does not allow it.
+	ErrAppointmentClosed = errors.New("appointment can no longer be changed this way")
+	// ErrInvalidDate is returned for dates not written as YYYY-MM-DD.
+	ErrInvalidDate = errors.New("dates must look like 2025-01-31")
+)
+
+// Booking is a student's request for one office-hour slot.
+type Booking struct {
+	OfficeHourID uuid.UUID
+	StartsAt     time.Time
+	Purpose      string
+}
+
+// Slot is one bookable time of an OfficeHour.
+type Slot struct {
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Available bool      `json:"available"`
+}
+
+// OfficeHours manages teachers' weekly office hours and the appointments
+// students book in them. Teachers (or academic admins) publish hours and
+// approve or decline bookings; students book and cancel. The acting user
+// comes from domain.ActorFromContext. Every appointment change commits
+// with an appointment.* event, which the notification dispatcher turns
+// into notifications with a calendar file for both sides.
+type OfficeHours struct {
+	hours        Usecase[domain.OfficeHour]
+	appointments Usecase[domain.Appointment]
+	repo         domain.AppointmentRepository
+	teachers     Usecase[domain.Teacher]
+	students     Usecase[domain.Student]
+	outbox       domain.Outbox
+	events       *eventbus.Bus
+	loc          *time.Location
+}
+
+// NewOfficeHours reads office-hour times as wall clock times in loc.
+func NewOfficeHours(hours Usecase[domain.OfficeHour], appointments Usecase[domain.Appointment], repo domain.AppointmentRepository, teachers Usecase[domain.Teacher], students Usecase[domain.Student], outbox domain.Outbox, events *eventbus.Bus, loc *time.Location) *OfficeHours {
+	return &OfficeHours{
+		hours:        hours,
+		appointments: appointments,
+		repo:         repo,
+		teachers:     teachers,
+		students:     students,
+		outbox:       outbox,
+		events:       events,
+		loc:          loc,
+	}
+}
+
+// CreateHour publishes office hours for hour.TeacherID.
+func (o *OfficeHours) CreateHour(ctx context.Context, hour *domain.OfficeHour) error {
+	if err := validateOfficeHour(hour); err != nil {
+		return err
+	}
+	teacher, err := o.teachers.GetByID(ctx, hour.TeacherID)
+	if err != nil {
+		return err
+	}
+	if err := authorizeTeacher(ctx, teacher); err != nil {
+		return err
+	}
+
+	hour.UniversityID = teacher.UniversityID
+	hour.DepartmentID = teacher.DepartmentID
+	hour.Teacher = nil
+	return o.hours.Create(ctx, hour)
+}
+
+// UpdateHour changes the schedule, location, note and active flag of
+// office hours. Appointments already booked keep their times.
+func (o *OfficeHours) UpdateHour(ctx context.Context, changes *domain.OfficeHour) (*domain.OfficeHour, error) {
+	hour, err := o.hours.GetByID(ctx, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeTeacher(ctx, hour.Teacher); err != nil {
+		return nil, err
+	}
+
+	hour.Weekday = changes.Weekday
+	hour.StartTime = changes.StartTime
+	hour.EndTime = changes.EndTime
+	hour.SlotMinutes = changes.SlotMinutes
+	hour.Location = changes.Location
+	hour.Note = changes.Note
+	hour.IsActive = changes.IsActive
+	hour.UpdatedByID = changes.UpdatedByID
+	if err := validateOfficeHour(hour); err != nil {
+		return nil, err
+	}
+
+	hour.Teacher = nil
+	if err := o.hours.Update(ctx, hour); err != nil {
+		return nil, err
+	}
+	return hour, nil
+}
+
+// DeleteHour removes office hours. Appointments already booked stay; the
+// teacher can still answer them.
+func (o *OfficeHours) DeleteHour(ctx context.Context, id uuid.UUID) error {
+	hour, err := o.hours.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeTeacher(ctx, hour.Teacher); err != nil {
+		return err
+	}
+	return o.hours.Delete(ctx, id)
+}
+
+// Slots lists the slots of active office hours over days days starting on
+// the date from (YYYY-MM-DD, today when empty), marking the ones already
+// booked. Past slots are left out.
+func (o *OfficeHours) Slots(ctx context.Context, hourID uuid.UUID, from string, days int) ([]Slot, error) {
+	first, err := parseDate(from, o.loc)
+	if err != nil {
+		return nil, err
+	}
+	hour, err := o.hours.GetByID(ctx, hourID)
+	if err != nil {
+		return nil, err
+	}
+	slots := []Slot{}
+	if !hour.IsActive {
+		return slots, nil
+	}
+
+	last := first.AddDate(0, 0, days)
+	booked, err := o.repo.Booked(ctx, hour.TeacherID, first, last)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end := clockMinutes(hour.StartTime), clockMinutes(hour.EndTime)
+	now := time.Now()
+	for day := first; day.Before(last); day = day.AddDate(0, 0, 1) {
+		if int(day.Weekday()) != hour.Weekday {
+			continue
+		}
+		for m := start; m+hour.SlotMinutes <= end; m += hour.SlotMinutes {
+			s := Slot{StartsAt: time.Date(day.Year(), day.Month(), day.Day(), m/60, m%60, 0, 0, o.loc)}
+			s.EndsAt = s.StartsAt.Add(time.Duration(hour.SlotMinutes) * time.Minute)
+			if !s.StartsAt.After(now) {
+				continue
+			}
+			s.Available = true
+			for _, a := range booked {
+				if a.StartsAt.Before(s.EndsAt) && a.EndsAt.After(s.StartsAt) {
+					s.Available = false
+					break
+				}
+			}
+			slots = append(slots, s)
+		}
+	}
+	return slots, nil
+}
+
+// Book requests a slot for the signed-in student. The slot must be offered
+// by active office hours, in the future, and free for both the teacher and
+// the student.
+func (o *OfficeHours) Book(ctx context.Context, b Booking) (*domain.Appointment, error) {
+	actor, _ := domain.ActorFromContext(ctx)
+	found, _, err := o.students.GetAll(ctx, map[string]interface{}{"user_id": actor.UserID}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID == uuid.Nil || len(found) == 0 {
+		return nil, ErrNoStudentProfile
+	}
+	student := found[0]
+
+	hour, err := o.hours.GetByID(ctx, b.OfficeHourID)
+	if err != nil {
+		return nil, err
+	}
+	if !hour.IsActive || hour.Teacher == nil || !b.StartsAt.After(time.Now()) || !o.offers(hour, b.StartsAt) {
+		return nil, ErrSlotUnavailable
+	}
+
+	appointment := &domain.Appointment{
+		OfficeHourID:  hour.ID,
+		TeacherID:     hour.TeacherID,
+		TeacherUserID: hour.Teacher.UserID,
+		TeacherName:   hour.Teacher.Name,
+		StudentID:     student.ID,
+		StudentUserID: actor.UserID,
+		StudentName:   student.Name,
+		UniversityID:  hour.UniversityID,
+		DepartmentID:  hour.DepartmentID,
+		StartsAt:      b.StartsAt.UTC(),
+		EndsAt:        b.StartsAt.UTC().Add(time.Duration(hour.SlotMinutes) * time.Minute),
+		Location:      hour.Location,
+		Purpose:       b.Purpose,
+		Status:        domain.AppointmentStatusPending,
+	}
+	appointment.SetCreatedBy(actor.UserID)
+	appointment.SetUpdatedBy(actor.UserID)
+
+	event := domain.AppointmentRequested{Appointment: appointment}
+	err = o.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := o.repo.LockTeacher(ctx, hour.TeacherID); err != nil {
+			return err
+		}
+		clashes, err := o.repo.Overlapping(ctx, hour.TeacherID, student.ID, appointment.StartsAt, appointment.EndsAt)
+		if err != nil {
+			return err
+		}
+		for _, c := range clashes {
+			if c.TeacherID == hour.TeacherID {
+				return ErrSlotTaken
+			}
+		}
+		if len(clashes) > 0 {
+			return ErrStudentBusy
+		}
+
+		if err := o.appointments.Create(ctx, appointment); err != nil {
+			return err
+		}
+		return o.outbox.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o.publish(ctx, event)
+	return appointment, nil
+}
+
+// Approve confirms a pending appointment; response is an optional note.
+func (o *OfficeHours) Approve(ctx context.Context, id uuid.UUID, response string) (*domain.Appointment, error) {
+	return o.decide(ctx, id, response, domain.AppointmentStatusApproved)
+}
+
+// Decline turns down a pending or approved appointment.
+func (o *OfficeHours) Decline(ctx context.Context, id uuid.UUID, response string) (*domain.Appointment, error) {
+	return o.decide(ctx, id, response, domain.AppointmentStatusDeclined)
+}
+
+func (o *OfficeHours) decide(ctx context.Context, id uuid.UUID, response, status string) (*domain.Appointment, error) {
+	return o.change(ctx, id, func(a *domain.Appointment, teacher *domain.Teacher, now time.Time) (domain.Event, error) {
+		if err := authorizeTeacher(ctx, teacher); err != nil {
+			return nil, err
+		}
+		if a.Status != domain.AppointmentStatusPending && !(status == domain.AppointmentStatusDeclined && a.Status == domain.AppointmentStatusApproved) {
+			return nil, ErrAppointmentClosed
+		}
+
+		a.Status = status
+		a.Response = response
+		a.DecidedAt = &now
+		// The teacher may have claimed their profile since the booking
+		a.TeacherUserID = teacher.UserID
+		if status == domain.AppointmentStatusApproved {
+			return domain.AppointmentApproved{Appointment: a}, nil
+		}
+		return domain.AppointmentDeclined{Appointment: a}, nil
+	})
+}
+
+// Cancel withdraws the signed-in student's pending or approved
+// appointment.
+func (o *OfficeHours) Cancel(ctx context.Context, id uuid.UUID) (*domain.Appointment, error) {
+	return o.change(ctx, id, func(a *domain.Appointment, teacher *domain.Teacher, now time.Time) (domain.Event, error) {
+		if actor, _ := domain.ActorFromContext(ctx); actor.UserID != a.StudentUserID {
+			return nil, gorm.ErrRecordNotFound
+		}
+		if a.Status != domain.AppointmentStatusPending && a.Status != domain.AppointmentStatusApproved {
+			return nil, ErrAppointmentClosed
+		}
+
+		a.Status = domain.AppointmentStatusCancelled
+		a.CancelledAt = &now
+		return domain.AppointmentCancelled{Appointment: a}, nil
+	})
+}
+
+// change applies fn to an appointment under the teacher lock and saves it
+// with the event fn returns.
+func (o *OfficeHours) change(ctx context.Context, id uuid.UUID, fn func(a *domain.Appointment, teacher *domain.Teacher, now time.Time) (domain.Event, error)) (*domain.Appointment, error) {
+	var (
+		appointment *domain.Appointment
+		event       domain.Event
+	)
+	err := o.outbox.Transaction(ctx, func(ctx context.Context) error {
+		a, err := o.appointments.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if err := o.repo.LockTeacher(ctx, a.TeacherID); err != nil {
+			return err
+		}
+		teacher, err := o.teachers.GetByID(ctx, a.TeacherID)
+		if err != nil {
+			return err
+		}
+
+		if event, err = fn(a, teacher, time.Now()); err != nil {
+			return err
+		}
+		a.Sequence++
+		if actor, ok := domain.ActorFromContext(ctx); ok && actor.UserID != uuid.Nil {
+			a.SetUpdatedBy(actor.UserID)
+		}
+		if err := o.appointments.Update(ctx, a); err != nil {
+			return err
+		}
+		appointment = a
+		return o.outbox.Add(ctx, event)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	o.publish(ctx, event)
+	return appointment, nil
+}
+
+// Get returns an appointment to its student, its teacher or an academic
+// admin; anyone else gets gorm.ErrRecordNotFound.
+func (o *OfficeHours) Get(ctx context.Context, id uuid.UUID) (*domain.Appointment, error) {
+	a, err := o.appointments.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	actor, _ := domain.ActorFromContext(ctx)
+	if actor.UserID != uuid.Nil && actor.UserID == a.StudentUserID {
+		return a, nil
+	}
+	teacher, err := o.teachers.GetByID(ctx, a.TeacherID)
+	if err != nil {
+		return nil, err
+	}
+	if authorizeTeacher(ctx, teacher) != nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return a, nil
+}
+
+// Mine lists the appointments of the signed-in user as a student or a
+// teacher, soonest first.
+func (o *OfficeHours) Mine(ctx context.Context, status string, limit, offset int) ([]domain.Appointment, int64, error) {
+	actor, _ := domain.ActorFromContext(ctx)
+	return o.repo.Involving(ctx, actor.UserID, status, limit, offset)
+}
+
+// dateLayout is how API dates are written.
+const dateLayout = "2006-01-02"
+
+// parseDate parses a YYYY-MM-DD date as midnight in loc, or returns
+// today's midnight for "".
+func parseDate(s string, loc *time.Location) (time.Time, error) {
+	if s == "" {
+		now := time.Now().In(loc)
+		return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, loc), nil
+	}
+	t, err := time.ParseInLocation(dateLayout, s, loc)
+	if err != nil {
+		return time.Time{}, ErrInvalidDate
+	}
+	return t, nil
+}
+
+// offers reports whether t starts a slot of hour.
+func (o *OfficeHours) offers(hour *domain.OfficeHour, t time.Time) bool {
+	t = t.In(o.loc)
+	if int(t.Weekday()) != hour.Weekday || t.Second() != 0 || t.Nanosecond() != 0 {
+		return false
+	}
+	m := t.Hour()*60 + t.Minute()
+	start, end := clockMinutes(hour.StartTime), clockMinutes(hour.EndTime)
+	return m >= start && m+hour.SlotMinutes <= end && (m-start)%hour.SlotMinutes == 0
+}
+
+func (o *OfficeHours) publish(ctx context.Context, e domain.Event) {
+	if err := o.events.Publish(ctx, e); err != nil {
+		logger.Errorf("%s subscribers failed: %v", e.EventName(), err)
+	}
+}
+
+// AppointmentCalendar is the calendar file of an appointment: tentative
+// while pending, confirmed once approved and a cancellation afterwards.
+func AppointmentCalendar(a *domain.Appointment) ical.Calendar {
+	event := ical.Event{
+		UID:      "appointment-" + a.ID.String() + "@campusassistant",
+		Summary:  fmt.Sprintf("Office hours: %s with %s", a.TeacherName, a.StudentName),
+		Location: a.Location,
+		Start:    a.StartsAt,
+		End:      a.EndsAt,
+		Sequence: a.Sequence,
+		Updated:  a.UpdatedAt,
+	}
+	var notes []string
+	if a.Purpose != "" {
+		notes = append(notes, "Purpose: "+a.Purpose)
+	}
+	if a.Response != "" {
+		notes = append(notes, "Response: "+a.Response)
+	}
+	event.Description = strings.Join(notes, "\n")
+
+	cal := ical.Calendar{Method: ical.MethodRequest, Events: []ical.Event{event}}
+	switch a.Status {
+	case domain.AppointmentStatusPending:
+		cal.Events[0].Status = ical.StatusTentative
+	case domain.AppointmentStatusApproved:
+		cal.Events[0].Status = ical.StatusConfirmed
+	default:
+		cal.Method = ical.MethodCancel
+		cal.Events[0].Status = ical.StatusCancelled
+	}
+	return cal
+}
+
+// authorizeTeacher allows academic admins and the teacher's own account.
+func authorizeTeacher(ctx context.Context, teacher *domain.Teacher) error {
+	actor, _ := domain.ActorFromContext(ctx)
+	switch domain.Role(actor.Role) {
+	case domain.RoleSuperAdmin, domain.RoleUniversityAdmin, domain.RoleDepartmentAdmin:
+		return nil
+	}
+	if teacher != nil && teacher.UserID != nil && actor.UserID != uuid.Nil && *teacher.UserID == actor.UserID {
+		return nil
+	}
+	return ErrNotOfficeHourOwner
+}
+
+func validateOfficeHour(h *domain.OfficeHour) error {
+	start, end := clockMinutes(h.StartTime), clockMinutes(h.EndTime)
+	if h.SlotMinutes == 0 {
+		h.SlotMinutes = 15
+	}
+	if h.Weekday < 0 || h.Weekday > 6 || start < 0 || end < 0 || start >= end ||
+		h.SlotMinutes < 5 || h.SlotMinutes > end-start {
+		return ErrInvalidOfficeHour
+	}
+	return nil
+}
+
+// clockMinutes parses "15:04" into minutes after midnight, or -1.
+func clockMinutes(s string) int {
+	t, err := time.Parse("15:04", s)
+	if err != nil || len(s) != 5 {
+		return -1
+	}
+	return t.Hour()*60 + t.Minute()
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// memAppointmentRepo is a domain.AppointmentRepository whose teacher has
+// the booked appointments.
+type memAppointmentRepo struct {
+	*memRepo[domain.Appointment]
+	booked []domain.Appointment
+}
+
+func (r *memAppointmentRepo) LockTeacher(context.Context, uuid.UUID) error { return nil }
+
+func (r *memAppointmentRepo) Overlapping(context.Context, uuid.UUID, uuid.UUID, time.Time, time.Time) ([]domain.Appointment, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *memAppointmentRepo) Booked(_ context.Context, _ uuid.UUID, from, to time.Time) ([]domain.Appointment, error) {
+	var found []domain.Appointment
+	for _, a := range r.booked {
+		if !a.StartsAt.Before(from) && a.StartsAt.Before(to) {
+			found = append(found, a)
+		}
+	}
+	return found, nil
+}
+
+func (r *memAppointmentRepo) Involving(context.Context, uuid.UUID, string, int, int) ([]domain.Appointment, int64, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func TestClockMinutes(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"09:30", 570},
+		{"00:00", 0},
+		{"23:59", 1439},
+		{"9:30", -1},
+		{"24:00", -1},
+		{"09:60", -1},
+		{"09:30:00", -1},
+		{"", -1},
+	}
+	for _, tt := range tests {
+		if got := clockMinutes(tt.in); got != tt.want {
+			t.Errorf("clockMinutes(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestOfficeHourSlots(t *testing.T) {
+	loc := time.FixedZone("BDT", 6*60*60)
+	// A day next week, so no slot is in the past
+	day := time.Now().In(loc).AddDate(0, 0, 7)
+	day = time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc)
+	at := func(clock string) time.Time {
+		m := clockMinutes(clock)
+		return day.Add(time.Duration(m) * time.Minute)
+	}
+
+	tests := []struct {
+		name          string
+		slotMinutes   int
+		inactive      bool
+		booked        [][2]string
+		wantStarts    []string
+		wantAvailable []bool
+	}{
+		{
+			name:          "hour in 20 minute slots",
+			slotMinutes:   20,
+			wantStarts:    []string{"10:00", "10:20", "10:40"},
+			wantAvailable: []bool{true, true, true},
+		},
+		{
+			name:          "slots that do not fit are left out",
+			slotMinutes:   25,
+			wantStarts:    []string{"10:00", "10:25"},
+			wantAvailable: []bool{true, true},
+		},
+		{
+			name:          "booked slot",
+			slotMinutes:   20,
+			booked:        [][2]string{{"10:20", "10:40"}},
+			wantStarts:    []string{"10:00", "10:20", "10:40"},
+			wantAvailable: []bool{true, false, true},
+		},
+		{
+			name:          "booking across two slots",
+			slotMinutes:   20,
+			booked:        [][2]string{{"10:10", "10:30"}},
+			wantStarts:    []string{"10:00", "10:20", "10:40"},
+			wantAvailable: []bool{false, false, true},
+		},
+		{
+			name:        "inactive hours",
+			slotMinutes: 20,
+			inactive:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hour := domain.OfficeHour{
+				Base:        domain.Base{ID: uuid.New()},
+				TeacherID:   uuid.New(),
+				Weekday:     int(day.Weekday()),
+				StartTime:   "10:00",
+				EndTime:     "11:00",
+				SlotMinutes: tt.slotMinutes,
+				IsActive:    !tt.inactive,
+			}
+			repo := &memAppointmentRepo{memRepo: newMemRepo[domain.Appointment]()}
+			for _, b := range tt.booked {
+				repo.booked = append(repo.booked, domain.Appointment{StartsAt: at(b[0]), EndsAt: at(b[1])})
+			}
+			o := NewOfficeHours(NewGenericUsecase[domain.OfficeHour](newMemRepo(hour)), nil, repo, nil, nil, &memOutbox{}, nil, loc)
+
+			// A week from the day before has the weekday once
+			slots, err := o.Slots(context.Background(), hour.ID, day.AddDate(0, 0, -1).Format(dateLayout), 7)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var starts []string
+			var available []bool
+			for _, s := range slots {
+				starts = append(starts, s.StartsAt.In(loc).Format("15:04"))
+				available = append(available, s.Available)
+				if got := s.EndsAt.Sub(s.StartsAt); got != time.Duration(tt.slotMinutes)*time.Minute {
+					t.Errorf("slot at %s lasts %s", s.StartsAt, got)
+				}
+			}
+			if !reflect.DeepEqual(starts, tt.wantStarts) || !reflect.DeepEqual(available, tt.wantAvailable) {
+				t.Errorf("slots %v available %v, want %v available %v", starts, available, tt.wantStarts, tt.wantAvailable)
+			}
+		})
+	}
+}
+
+func TestOfficeHourOffers(t *testing.T) {
+	loc := time.FixedZone("BDT", 6*60*60)
+	monday := time.Date(2026, time.March, 2, 0, 0, 0, 0, loc)
+	hour := &domain.OfficeHour{Weekday: int(time.Monday), StartTime: "10:00", EndTime: "11:00", SlotMinutes: 25}
+	o := &OfficeHours{loc: loc}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		{"first slot", monday.Add(10 * time.Hour), true},
+		{"second slot", monday.Add(10*time.Hour + 25*time.Minute), true},
+		{"same slot in UTC", monday.Add(10*time.Hour + 25*time.Minute).UTC(), true},
+		{"between slots", monday.Add(10*time.Hour + 20*time.Minute), false},
+		{"slot that does not fit", monday.Add(10*time.Hour + 50*time.Minute), false},
+		{"before the hours", monday.Add(9*time.Hour + 35*time.Minute), false},
+		{"other weekday", monday.AddDate(0, 0, 1).Add(10 * time.Hour), false},
+		{"with seconds", monday.Add(10*time.Hour + time.Second), false},
+	}
+	for _, tt := range tests {
+		if got := o.offers(hour, tt.at); got != tt.want {
+			t.Errorf("%s: offers(%s) = %v, want %v", tt.name, tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestValidateOfficeHour(t *testing.T) {
+	tests := []struct {
+		name     string
+		hour     domain.OfficeHour
+		wantErr  bool
+		wantSlot int
+	}{
+		{"default slot length", domain.OfficeHour{Weekday: 1, StartTime: "10:00", EndTime: "11:00"}, false, 15},
+		{"whole hours as one slot", domain.OfficeHour{Weekday: 6, StartTime: "10:00", EndTime: "10:30", SlotMinutes: 30}, false, 30},
+		{"slot longer than the hours", domain.OfficeHour{Weekday: 1, StartTime: "10:00", EndTime: "10:30", SlotMinutes: 45}, true, 45},
+		{"slot too short", domain.OfficeHour{Weekday: 1, StartTime: "10:00", EndTime: "11:00", SlotMinutes: 3}, true, 3},
+		{"ends before it starts", domain.OfficeHour{Weekday: 1, StartTime: "11:00", EndTime: "10:00"}, true, 15},
+		{"bad weekday", domain.OfficeHour{Weekday: 7, StartTime: "10:00", EndTime: "11:00"}, true, 15},
+		{"bad time", domain.OfficeHour{Weekday: 1, StartTime: "10am", EndTime: "11:00"}, true, 15},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.hour
+			err := validateOfficeHour(&h)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateOfficeHour() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if h.SlotMinutes != tt.wantSlot {
+				t.Errorf("SlotMinutes = %d, want %d", h.SlotMinutes, tt.wantSlot)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/logger"
+)
+
+const (
+	outboxBatchSize   = 50
+	outboxLease       = 2 * time.Minute
+	outboxBudget      = time.Minute
+	outboxMaxAttempts = 10
+	outboxBaseBackoff = 10 * time.Second
+	outboxMaxBackoff  = time.Hour
+	outboxRetention   = 7 * 24 * time.Hour
+)
+
+// OutboxRelay moves events from the outbox to the dispatchers. Each event is
+// retried with backoff until every dispatcher has accepted it; dispatchers
+// that already succeeded are skipped on retries. Several relays (one per API
+// instance) can run at once since claims use SKIP LOCKED.
+type OutboxRelay struct {
+	repo        domain.OutboxRepository
+	dispatchers []domain.OutboxDispatcher
+	// budget is how long a batch may take to start its events, well under
+	// outboxLease so another relay does not claim them while they run
+	budget time.Duration
+}
+
+func NewOutboxRelay(repo domain.OutboxRepository, dispatchers ...domain.OutboxDispatcher) *OutboxRelay {
+	return &OutboxRelay{repo: repo, dispatchers: dispatchers, budget: outboxBudget}
+}
+
+// Start polls the outbox every interval in the background, draining full
+// batches back to back, and purges processed events hourly.
+func (r *OutboxRelay) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		purge := time.Now()
+		for range ticker.C {
+			for {
+				n, err := r.RunOnce(context.Background())
+				if err != nil {
+					logger.Errorf("outbox relay failed: %v", err)
+				}
+				if err != nil || n < outboxBatchSize {
+					break
+				}
+			}
+
+			if time.Since(purge) >= time.Hour {
+				purge = time.Now()
+				if _, err := r.repo.DeleteProcessed(context.Background(), time.Now().Add(-outboxRetention)); err != nil {
+					logger.Errorf("failed to purge outbox: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// RunOnce claims one batch of due events and dispatches them. Events not
+// started within the relay's budget are released for the next run without
+// counting an attempt. It returns how many events were claimed.
+func (r *OutboxRelay) RunOnce(ctx context.Context) (int, error) {
+	start := time.Now()
+	events, err := r.repo.Claim(ctx, start, outboxLease, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	for i := range events {
+		if time.Since(start) >= r.budget {
+			r.release(ctx, events[i:])
+			break
+		}
+		r.process(ctx, &events[i])
+	}
+	return len(events), nil
+}
+
+// release makes claimed events due again right away.
+func (r *OutboxRelay) release(ctx context.Context, events []domain.OutboxEvent) {
+	now := time.Now()
+	for i := range events {
+		events[i].NextAttemptAt = now
+		if err := r.repo.Save(ctx, &events[i]); err != nil {
+			logger.Errorf("failed to release outbox event %s: %v", events[i].ID, err)
+		}
+	}
+}
+
+func (r *OutboxRelay) process(ctx context.Context, e *domain.OutboxEvent) {
+	var errs []error
+	for _, d := range r.dispatchers {
+		if slices.Contains(e.Dispatched, d.Name()) {
+			continue
+		}
+		if err := dispatchOutbox(ctx, d, e); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", d.Name(), err))
+			continue
+		}
+		e.Dispatched = append(e.Dispatched, d.Name())
+	}
+
+	e.Attempts++
+	switch {
+	case len(errs) == 0:
+		now := time.Now()
+		e.Status = domain.OutboxDone
+		e.ProcessedAt = &now
+		e.LastError = ""
+	case e.Attempts >= outboxMaxAttempts:
+		e.Status = domain.OutboxFailed
+		e.LastError = errors.Join(errs...).Error()
+		logger.Errorf("outbox event %s (%s) failed for good: %s", e.ID, e.EventName, e.LastError)
+	default:
+		e.NextAttemptAt = time.Now().Add(backoff(e.Attempts, outboxBaseBackoff, outboxMaxBackoff))
+		e.LastError = errors.Join(errs...).Error()
+	}
+
+	if err := r.repo.Save(ctx, e); err != nil {
+		// The lease runs out and the event is retried; dispatchers that
+		// already succeeded see it again (at-least-once).
+		logger.Errorf("failed to save outbox event %s: %v", e.ID, err)
+	}
+}
+
+func dispatchOutbox(ctx context.Context, d domain.OutboxDispatcher, e *domain.OutboxEvent) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = fmt.Errorf("panic: %v", rec)
+		}
+	}()
+	return d.Dispatch(ctx, e)
+}
+
+// backoff returns the wait after the given number of failed attempts:
+// base, 2*base, 4*base, ... capped at max.
+func backoff(attempts int, base, max time.Duration) time.Duration {
+	wait := base
+	for i := 1; i < attempts && wait < max; i++ {
+		wait *= 2
+	}
+	return min(wait, max)
+}
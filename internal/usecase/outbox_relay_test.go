@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// memOutboxRepo is a domain.OutboxRepository over a fixed set of events.
+type memOutboxRepo struct {
+	memOutbox
+	stored []domain.OutboxEvent
+}
+
+func (r *memOutboxRepo) Claim(_ context.Context, now time.Time, lease time.Duration, limit int) ([]domain.OutboxEvent, error) {
+	var claimed []domain.OutboxEvent
+	for i := range r.stored {
+		e := &r.stored[i]
+		if e.Status == domain.OutboxPending && !e.NextAttemptAt.After(now) && len(claimed) < limit {
+			e.NextAttemptAt = now.Add(lease)
+			claimed = append(claimed, *e)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *memOutboxRepo) Save(_ context.Context, e *domain.OutboxEvent) error {
+	for i := range r.stored {
+		if r.stored[i].ID == e.ID {
+			r.stored[i] = *e
+		}
+	}
+	return nil
+}
+
+func (r *memOutboxRepo) DeleteProcessed(context.Context, time.Time) (int64, error) { return 0, nil }
+
+// countingDispatcher accepts every event.
+type countingDispatcher struct{ calls atomic.Int32 }
+
+func (d *countingDispatcher) Name() string { return "counting" }
+
+func (d *countingDispatcher) Dispatch(context.Context, *domain.OutboxEvent) error {
+	d.calls.Add(1)
+	return nil
+}
+
+func TestOutboxRelayBudget(t *testing.T) {
+	tests := []struct {
+		name       string
+		budget     time.Duration
+		wantCalls  int32
+		wantStatus domain.OutboxStatus
+	}{
+		{name: "within the budget", budget: time.Minute, wantCalls: 3, wantStatus: domain.OutboxDone},
+		{name: "budget spent", budget: 0, wantCalls: 0, wantStatus: domain.OutboxPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memOutboxRepo{}
+			for i := 0; i < 3; i++ {
+				repo.stored = append(repo.stored, domain.OutboxEvent{ID: uuid.New(), EventName: "notice.created", Status: domain.OutboxPending, NextAttemptAt: time.Now()})
+			}
+			dispatcher := &countingDispatcher{}
+			relay := NewOutboxRelay(repo, dispatcher)
+			relay.budget = tt.budget
+
+			n, err := relay.RunOnce(context.Background())
+			if err != nil || n != 3 {
+				t.Fatalf("RunOnce() = %d, %v; want 3 claimed", n, err)
+			}
+			if got := dispatcher.calls.Load(); got != tt.wantCalls {
+				t.Errorf("%d dispatches, want %d", got, tt.wantCalls)
+			}
+			for _, e := range repo.stored {
+				if e.Status != tt.wantStatus {
+					t.Errorf("event status %q, want %q", e.Status, tt.wantStatus)
+				}
+				// Released events are due again at once, without an attempt
+				if e.Status == domain.OutboxPending && (e.Attempts != 0 || e.NextAttemptAt.After(time.Now())) {
+					t.Errorf("released event = %+v, want it due with no attempts", e)
+				}
+			}
+		})
+	}
+}
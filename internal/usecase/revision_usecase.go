@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/logger"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// ErrRevisionsDisabled is returned when an entity has not opted in to revisions.
+var ErrRevisionsDisabled = errors.New("revisions are not enabled for this entity")
+
+// RevisionUsecase exposes revision history for entities implementing domain.Versioned.
+type RevisionUsecase[T any] interface {
+	ListRevisions(ctx context.Context, id uuid.UUID) ([]domain.Revision, error)
+	GetRevision(ctx context.Context, id uuid.UUID, version int) (*domain.Revision, error)
+	DiffRevisions(ctx context.Context, id uuid.UUID, from, to int) (map[string]FieldChange, error)
+	RestoreRevision(ctx context.Context, id uuid.UUID, version int) (*T, error)
+}
+
+// versioned reports whether T opted in and a revision store is configured.
+func (u *genericUsecase[T]) versioned() bool {
+	if u.opts.Revisions == nil {
+		return false
+	}
+	v, ok := any(new(T)).(domain.Versioned)
+	return ok && v.KeepRevisions()
+}
+
+// snapshot stores the entity as a new revision. Failures are logged but never
+// fail the write itself, since the entity change has already been committed.
+func (u *genericUsecase[T]) snapshot(ctx context.Context, action string, id uuid.UUID, entity *T) {
+	if !u.versioned() || entity == nil {
+		return
+	}
+
+	raw, err := json.Marshal(entity)
+	if err != nil {
+		logger.Errorf("failed to snapshot %s %s: %v", u.entityName, id, err)
+		return
+	}
+
+	rev := &domain.Revision{
+		EntityName: u.entityName,
+		EntityID:   id,
+		Action:     action,
+		Snapshot:   datatypes.JSON(raw),
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		rev.CreatedByID = actor.UserID
+		rev.UpdatedByID = actor.UserID
+	}
+
+	if err := u.opts.Revisions.Create(ctx, rev); err != nil {
+		logger.Errorf("failed to save revision for %s %s: %v", u.entityName, id, err)
+	}
+}
+
+func (u *genericUsecase[T]) ListRevisions(ctx context.Context, id uuid.UUID) ([]domain.Revision, error) {
+	if !u.versioned() {
+		return nil, ErrRevisionsDisabled
+	}
+	return u.opts.Revisions.ListByEntity(ctx, u.entityName, id)
+}
+
+func (u *genericUsecase[T]) GetRevision(ctx context.Context, id uuid.UUID, version int) (*domain.Revision, error) {
+	if !u.versioned() {
+		return nil, ErrRevisionsDisabled
+	}
+	return u.opts.Revisions.GetByVersion(ctx, u.entityName, id, version)
+}
+
+func (u *genericUsecase[T]) DiffRevisions(ctx context.Context, id uuid.UUID, from, to int) (map[string]FieldChange, error) {
+	older, err := u.GetRevision(ctx, id, from)
+	if err != nil {
+		return nil, err
+	}
+	newer, err := u.GetRevision(ctx, id, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffEntities(json.RawMessage(older.Snapshot), json.RawMessage(newer.Snapshot))
+}
+
+// RestoreRevision overwrites the live entity with the given snapshot.
+// The restore itself is recorded as a new revision, so it can be undone too.
+func (u *genericUsecase[T]) RestoreRevision(ctx context.Context, id uuid.UUID, version int) (*T, error) {
+	rev, err := u.GetRevision(ctx, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only live entities can be rolled back; undeleting is out of scope.
+	if _, err := u.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	var entity T
+	if err := json.Unmarshal(rev.Snapshot, &entity); err != nil {
+		return nil, err
+	}
+	if setter, ok := any(&entity).(domain.Entity); ok {
+		setter.SetID(id)
+	}
+	if actor, ok := domain.ActorFromContext(ctx); ok {
+		if auditable, ok := any(&entity).(domain.Auditable); ok {
+			auditable.SetUpdatedBy(actor.UserID)
+		}
+	}
+
+	if err := u.update(ctx, &entity, domain.AuditActionRestore); err != nil {
+		return nil, err
+	}
+	return &entity, nil
+}
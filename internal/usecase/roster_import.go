@@ -0,0 +1,263 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/spreadsheet"
+
+	"github.com/google/uuid"
+)
+
+// ErrImportRejected is returned when a non-dry-run import has invalid or
+// duplicate rows, or a row fails to save; nothing is written in that case.
+var ErrImportRejected = errors.New("import rejected: fix the reported rows and try again")
+
+// RosterScope is the university/department every imported row belongs to.
+type RosterScope struct {
+	UniversityID uuid.UUID
+	DepartmentID uuid.UUID
+}
+
+type ImportOptions struct {
+	DryRun         bool // Validate only, never write
+	SkipDuplicates bool // Import the remaining rows instead of rejecting the file
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowValid     ImportRowStatus = "valid"
+	ImportRowInvalid   ImportRowStatus = "invalid"
+	ImportRowDuplicate ImportRowStatus = "duplicate"
+	ImportRowCreated   ImportRowStatus = "created"
+	ImportRowFailed    ImportRowStatus = "failed"
+)
+
+// ImportRowResult reports the outcome for one spreadsheet row.
+type ImportRowResult struct {
+	Row    int             `json:"row"` // Spreadsheet line number (header is line 1)
+	Status ImportRowStatus `json:"status"`
+	Key    string          `json:"key,omitempty"` // Identity used for duplicate detection
+	Errors []string        `json:"errors,omitempty"`
+	ID     *uuid.UUID      `json:"id,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun     bool              `json:"dry_run"`
+	Total      int               `json:"total"`
+	Valid      int               `json:"valid"`
+	Invalid    int               `json:"invalid"`
+	Duplicates int               `json:"duplicates"`
+	Created    int               `json:"created"`
+	Failed     int               `json:"failed"`
+	Rows       []ImportRowResult `json:"rows"`
+}
+
+// RosterMapper converts spreadsheet rows into entities of one type.
+type RosterMapper[T any] interface {
+	// Map builds an entity from a row, returning every validation problem found.
+	Map(ctx context.Context, row spreadsheet.Row, scope RosterScope) (*T, []string)
+	// Key identifies the entity for duplicate detection within the file.
+	Key(entity *T) string
+	// Exists reports whether a matching entity is already stored.
+	Exists(ctx context.Context, entity *T) (bool, error)
+}
+
+// RosterImporter validates a whole file before writing any of it, then
+// writes it in one transaction so an import lands completely or not at all.
+type RosterImporter[T any] struct {
+	uc     Usecase[T]
+	mapper RosterMapper[T]
+	outbox domain.Outbox
+}
+
+func NewRosterImporter[T any](uc Usecase[T], mapper RosterMapper[T], outbox domain.Outbox) *RosterImporter[T] {
+	return &RosterImporter[T]{uc: uc, mapper: mapper, outbox: outbox}
+}
+
+func (i *RosterImporter[T]) Import(ctx context.Context, rows []spreadsheet.Row, scope RosterScope, opts ImportOptions) (*ImportReport, error) {
+	report := &ImportReport{
+		DryRun: opts.DryRun,
+		Total:  len(rows),
+		Rows:   make([]ImportRowResult, len(rows)),
+	}
+	entities := make([]*T, len(rows))
+	seen := make(map[string]int)
+
+	for idx, row := range rows {
+		result := ImportRowResult{Row: idx + 2}
+
+		entity, errs := i.mapper.Map(ctx, row, scope)
+		if len(errs) > 0 {
+			result.Status = ImportRowInvalid
+			result.Errors = errs
+			report.Invalid++
+			report.Rows[idx] = result
+			continue
+		}
+
+		result.Key = i.mapper.Key(entity)
+		if first, dup := seen[result.Key]; dup {
+			result.Status = ImportRowDuplicate
+			result.Errors = []string{fmt.Sprintf("duplicate of row %d", first)}
+			report.Duplicates++
+			report.Rows[idx] = result
+			continue
+		}
+		seen[result.Key] = result.Row
+
+		exists, err := i.mapper.Exists(ctx, entity)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			result.Status = ImportRowDuplicate
+			result.Errors = []string{"already exists"}
+			report.Duplicates++
+			report.Rows[idx] = result
+			continue
+		}
+
+		result.Status = ImportRowValid
+		report.Valid++
+		entities[idx] = entity
+		report.Rows[idx] = result
+	}
+
+	if opts.DryRun {
+		return report, nil
+	}
+	if report.Invalid > 0 || (report.Duplicates > 0 && !opts.SkipDuplicates) {
+		return report, ErrImportRejected
+	}
+
+	err := i.outbox.Transaction(ctx, func(ctx context.Context) error {
+		// Each create runs in its own savepoint, so every failing row is
+		// reported before the whole import is rolled back
+		for idx, entity := range entities {
+			if entity == nil {
+				continue
+			}
+			if err := i.uc.Create(ctx, entity); err != nil {
+				report.Rows[idx].Status = ImportRowFailed
+				report.Rows[idx].Errors = []string{err.Error()}
+				report.Failed++
+				continue
+			}
+			id := entityID(entity)
+			report.Rows[idx].Status = ImportRowCreated
+			report.Rows[idx].ID = &id
+			report.Created++
+		}
+		if report.Failed > 0 {
+			return ErrImportRejected
+		}
+		return nil
+	})
+	if err != nil {
+		// Rolled back: the rows saved before the failure were not kept
+		for idx := range report.Rows {
+			if report.Rows[idx].Status == ImportRowCreated {
+				report.Rows[idx].Status = ImportRowValid
+				report.Rows[idx].ID = nil
+			}
+		}
+		report.Created = 0
+		return report, err
+	}
+
+	return report, nil
+}
+
+// ── Row helpers ──────────────────────────────────────────────────────────────
+
+// pick returns the first non-empty value among the given column aliases.
+func pick(row spreadsheet.Row, columns ...string) string {
+	for _, col := range columns {
+		if v := strings.TrimSpace(row[col]); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func parseBoolCell(v string, def bool) (bool, error) {
+	switch strings.ToLower(strings.TrimSpace(v)) {
+	case "":
+		return def, nil
+	case "1", "true", "yes", "y":
+		return true, nil
+	case "0", "false", "no", "n":
+		return false, nil
+	}
+	return def, fmt.Errorf("%q is not a yes/no value", v)
+}
+
+func parseIntCell(v string) (int, error) {
+	if strings.TrimSpace(v) == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(v))
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", v)
+	}
+	return n, nil
+}
+
+// refLookup resolves a referenced entity (batch, session, hall) by ID, slug
+// or name within a scope column, caching hits for the duration of an import.
+type refLookup[T any] struct {
+	label    string
+	repo     domain.Repository[T]
+	scopeCol string
+	cache    map[string]uuid.UUID
+}
+
+func newRefLookup[T any](label string, repo domain.Repository[T], scopeCol string) *refLookup[T] {
+	return &refLookup[T]{label: label, repo: repo, scopeCol: scopeCol, cache: make(map[string]uuid.UUID)}
+}
+
+func (l *refLookup[T]) resolve(ctx context.Context, scopeID uuid.UUID, value string) (uuid.UUID, error) {
+	cacheKey := scopeID.String() + "|" + strings.ToLower(value)
+	if id, ok := l.cache[cacheKey]; ok {
+		return id, nil
+	}
+
+	// IDs are matched within the scope like slugs and names, so a row
+	// cannot point at another university's batch, session or hall
+	filters := []map[string]interface{}{
+		{l.scopeCol: scopeID, "slug": strings.ToLower(value)},
+		{l.scopeCol: scopeID, "name": value},
+	}
+	if id, err := uuid.Parse(value); err == nil {
+		filters = []map[string]interface{}{{l.scopeCol: scopeID, "id": id}}
+	}
+
+	for _, filter := range filters {
+		found, _, err := l.repo.GetAll(ctx, filter, 1, 0)
+		if err != nil {
+			return uuid.Nil, err
+		}
+		if len(found) > 0 {
+			id := entityID(&found[0])
+			l.cache[cacheKey] = id
+			return id, nil
+		}
+	}
+
+	return uuid.Nil, fmt.Errorf("%s %q not found", l.label, value)
+}
+
+// exists reports whether any row matches filter.
+func exists[T any](ctx context.Context, repo domain.Repository[T], filter map[string]interface{}) (bool, error) {
+	found, _, err := repo.GetAll(ctx, filter, 1, 0)
+	if err != nil {
+		return false, err
+	}
+	return len(found) > 0, nil
+}
@@ -0,0 +1,108 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/spreadsheet"
+
+	"github.com/google/uuid"
+)
+
+// staffNames maps a "name" column to staff profiles.
+type staffNames struct{}
+
+func (staffNames) Map(_ context.Context, row spreadsheet.Row, scope RosterScope) (*domain.Staff, []string) {
+	return &domain.Staff{Name: row["name"], UniversityID: scope.UniversityID, DepartmentID: scope.DepartmentID}, nil
+}
+
+func (staffNames) Key(s *domain.Staff) string { return s.Name }
+
+func (staffNames) Exists(context.Context, *domain.Staff) (bool, error) { return false, nil }
+
+func TestRosterImportAuditsOnlyCommittedRows(t *testing.T) {
+	rows := []spreadsheet.Row{{"name": "Alice"}, {"name": "Bob"}, {"name": "Carol"}}
+
+	tests := []struct {
+		name        string
+		failOn      string
+		opts        ImportOptions
+		wantErr     error
+		wantCreated int
+		wantAudits  int
+		wantEvents  int
+	}{
+		{name: "all rows saved", wantCreated: 3, wantAudits: 3, wantEvents: 3},
+		{name: "a later row fails", failOn: "Carol", wantErr: ErrImportRejected},
+		{name: "dry run", opts: ImportOptions{DryRun: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemRepo[domain.Staff]()
+			repo.fail = func(s *domain.Staff) error {
+				if s.Name == tt.failOn {
+					return errors.New("insert failed")
+				}
+				return nil
+			}
+			audits := newMemAuditRepo()
+			recorder := NewAuditRecorder(audits)
+			recorder.Start()
+			outbox := &memOutbox{}
+
+			uc := NewGenericUsecase[domain.Staff](repo, WithAuditor(recorder), WithOutbox(outbox))
+			importer := NewRosterImporter(uc, staffNames{}, outbox)
+			scope := RosterScope{UniversityID: uuid.New(), DepartmentID: uuid.New()}
+
+			report, err := importer.Import(context.Background(), rows, scope, tt.opts)
+			recorder.Close()
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Import() error = %v, want %v", err, tt.wantErr)
+			}
+			if report.Created != tt.wantCreated {
+				t.Errorf("Created = %d, want %d", report.Created, tt.wantCreated)
+			}
+			if got := audits.len(); got != tt.wantAudits {
+				t.Errorf("%d audit logs written, want %d", got, tt.wantAudits)
+			}
+			if got := len(outbox.events); got != tt.wantEvents {
+				t.Errorf("%d outbox events stored, want %d", got, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestRefLookupStaysInScope(t *testing.T) {
+	dept, otherDept := uuid.New(), uuid.New()
+	own := domain.Batch{Base: domain.Base{ID: uuid.New()}, Name: "Batch 10", Slug: "batch-10", DepartmentID: dept}
+	foreign := domain.Batch{Base: domain.Base{ID: uuid.New()}, Name: "Batch 11", Slug: "batch-11", DepartmentID: otherDept}
+	lookup := newRefLookup("batch", newMemRepo(own, foreign), "department_id")
+
+	tests := []struct {
+		name    string
+		value   string
+		want    uuid.UUID
+		wantErr bool
+	}{
+		{"own ID", own.ID.String(), own.ID, false},
+		{"own slug", "Batch-10", own.ID, false},
+		{"own name", "Batch 10", own.ID, false},
+		{"other department's ID", foreign.ID.String(), uuid.Nil, true},
+		{"other department's slug", "batch-11", uuid.Nil, true},
+		{"unknown ID", uuid.New().String(), uuid.Nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lookup.resolve(context.Background(), dept, tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("resolve(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("resolve(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/spreadsheet"
+)
+
+// ── Students ─────────────────────────────────────────────────────────────────
+
+type studentRosterMapper struct {
+	students domain.Repository[domain.Student]
+	batches  *refLookup[domain.Batch]
+	sessions *refLookup[domain.Session]
+	halls    *refLookup[domain.Hall]
+}
+
+// NewStudentRosterMapper maps columns: name, student_id, email, phone,
+// blood_group, batch, session, hall, is_regular, weight.
+// Batch is resolved within the department; session and hall within the university.
+func NewStudentRosterMapper(
+	students domain.Repository[domain.Student],
+	batches domain.Repository[domain.Batch],
+	sessions domain.Repository[domain.Session],
+	halls domain.Repository[domain.Hall],
+) RosterMapper[domain.Student] {
+	return &studentRosterMapper{
+		students: students,
+		batches:  newRefLookup("batch", batches, "department_id"),
+		sessions: newRefLookup("session", sessions, "university_id"),
+		halls:    newRefLookup("hall", halls, "university_id"),
+	}
+}
+
+func (m *studentRosterMapper) Map(ctx context.Context, row spreadsheet.Row, scope RosterScope) (*domain.Student, []string) {
+	var errs []string
+
+	s := &domain.Student{
+		UniversityID: scope.UniversityID,
+		DepartmentID: scope.DepartmentID,
+		Name:         pick(row, "name", "full_name", "student_name"),
+		StudentID:    pick(row, "student_id", "id", "roll", "roll_no"),
+		Email:        strings.ToLower(pick(row, "email")),
+		Phone:        pick(row, "phone", "mobile"),
+		BloodGroup:   strings.ToUpper(pick(row, "blood_group", "blood")),
+	}
+
+	if s.Name == "" {
+		errs = append(errs, "name is required")
+	}
+	if s.StudentID == "" {
+		errs = append(errs, "student_id is required")
+	}
+
+	if batch := pick(row, "batch", "batch_name", "batch_slug"); batch == "" {
+		errs = append(errs, "batch is required")
+	} else if id, err := m.batches.resolve(ctx, scope.DepartmentID, batch); err != nil {
+		errs = append(errs, err.Error())
+	} else {
+		s.BatchID = id
+	}
+
+	if session := pick(row, "session", "session_name", "session_slug"); session == "" {
+		errs = append(errs, "session is required")
+	} else if id, err := m.sessions.resolve(ctx, scope.UniversityID, session); err != nil {
+		errs = append(errs, err.Error())
+	} else {
+		s.SessionID = id
+	}
+
+	if hall := pick(row, "hall", "hall_name", "hall_slug"); hall != "" {
+		if id, err := m.halls.resolve(ctx, scope.UniversityID, hall); err != nil {
+			errs = append(errs, err.Error())
+		} else {
+			s.HallID = &id
+		}
+	}
+
+	var err error
+	if s.IsRegular, err = parseBoolCell(pick(row, "is_regular", "regular"), true); err != nil {
+		errs = append(errs, "is_regular: "+err.Error())
+	}
+	if s.Weight, err = parseIntCell(pick(row, "weight", "order")); err != nil {
+		errs = append(errs, "weight: "+err.Error())
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return s, nil
+}
+
+func (m *studentRosterMapper) Key(s *domain.Student) string {
+	return strings.ToLower(s.StudentID)
+}
+
+func (m *studentRosterMapper) Exists(ctx context.Context, s *domain.Student) (bool, error) {
+	return exists(ctx, m.students, map[string]interface{}{
+		"department_id": s.DepartmentID,
+		"student_id":    s.StudentID,
+	})
+}
+
+// ── Teachers ─────────────────────────────────────────────────────────────────
+
+type teacherRosterMapper struct {
+	teachers domain.Repository[domain.Teacher]
+}
+
+// NewTeacherRosterMapper maps columns: name, email, phone, designation, about,
+// interests, phd, publications, is_chairman, is_present, weight.
+func NewTeacherRosterMapper(teachers domain.Repository[domain.Teacher]) RosterMapper[domain.Teacher] {
+	return &teacherRosterMapper{teachers: teachers}
+}
+
+func (m *teacherRosterMapper) Map(ctx context.Context, row spreadsheet.Row, scope RosterScope) (*domain.Teacher, []string) {
+	var errs []string
+
+	t := &domain.Teacher{
+		UniversityID: scope.UniversityID,
+		DepartmentID: scope.DepartmentID,
+		Name:         pick(row, "name", "full_name", "teacher_name"),
+		Email:        strings.ToLower(pick(row, "email")),
+		Phone:        pick(row, "phone", "mobile"),
+		Designation:  pick(row, "designation", "title"),
+		About:        pick(row, "about"),
+		Interests:    pick(row, "interests", "research_interests"),
+		PhD:          pick(row, "phd"),
+		Publications: pick(row, "publications"),
+	}
+
+	if t.Name == "" {
+		errs = append(errs, "name is required")
+	}
+
+	var err error
+	if t.IsChairman, err = parseBoolCell(pick(row, "is_chairman", "chairman"), false); err != nil {
+		errs = append(errs, "is_chairman: "+err.Error())
+	}
+	if t.IsPresent, err = parseBoolCell(pick(row, "is_present", "present"), true); err != nil {
+		errs = append(errs, "is_present: "+err.Error())
+	}
+	if t.Weight, err = parseIntCell(pick(row, "weight", "order")); err != nil {
+		errs = append(errs, "weight: "+err.Error())
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return t, nil
+}
+
+// Teachers are identified by email when present, otherwise by name.
+func (m *teacherRosterMapper) Key(t *domain.Teacher) string {
+	if t.Email != "" {
+		return t.Email
+	}
+	return strings.ToLower(t.Name)
+}
+
+func (m *teacherRosterMapper) Exists(ctx context.Context, t *domain.Teacher) (bool, error) {
+	filter := map[string]interface{}{"department_id": t.DepartmentID}
+	if t.Email != "" {
+		filter["email"] = t.Email
+	} else {
+		filter["name"] = t.Name
+	}
+	return exists(ctx, m.teachers, filter)
+}
+
+// ── Staff ────────────────────────────────────────────────────────────────────
+
+type staffRosterMapper struct {
+	staffs domain.Repository[domain.Staff]
+}
+
+// NewStaffRosterMapper maps columns: name, post, mobile, image_url, serial.
+func NewStaffRosterMapper(staffs domain.Repository[domain.Staff]) RosterMapper[domain.Staff] {
+	return &staffRosterMapper{staffs: staffs}
+}
+
+func (m *staffRosterMapper) Map(ctx context.Context, row spreadsheet.Row, scope RosterScope) (*domain.Staff, []string) {
+	var errs []string
+
+	s := &domain.Staff{
+		UniversityID: scope.UniversityID,
+		DepartmentID: scope.DepartmentID,
+		Name:         pick(row, "name", "full_name", "staff_name"),
+		Post:         pick(row, "post", "designation"),
+		Mobile:       pick(row, "mobile", "phone"),
+		ImageURL:     pick(row, "image_url", "image"),
+	}
+
+	if s.Name == "" {
+		errs = append(errs, "name is required")
+	}
+
+	var err error
+	if s.Serial, err = parseIntCell(pick(row, "serial", "order")); err != nil {
+		errs = append(errs, "serial: "+err.Error())
+	}
+
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	return s, nil
+}
+
+// Staff are identified by mobile number when present, otherwise by name.
+func (m *staffRosterMapper) Key(s *domain.Staff) string {
+	if s.Mobile != "" {
+		return s.Mobile
+	}
+	return strings.ToLower(s.Name)
+}
+
+func (m *staffRosterMapper) Exists(ctx context.Context, s *domain.Staff) (bool, error) {
+	filter := map[string]interface{}{"department_id": s.DepartmentID}
+	if s.Mobile != "" {
+		filter["mobile"] = s.Mobile
+	} else {
+		filter["name"] = s.Name
+	}
+	return exists(ctx, m.staffs, filter)
+}
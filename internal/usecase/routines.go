@@ -0,0 +1,355 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/ical"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidRoutineEntry is returned for a bad weekday, times, room or
+	// validity range.
+	ErrInvalidRoutineEntry = errors.New("routine entries need a weekday from 0 (Sunday) to 6, HH:MM times with start before end, a room, and valid_from on or before valid_until as YYYY-MM-DD")
+	// ErrTeacherNotAssigned is returned when a routine entry names a teacher
+	// who is not assigned to its course offering.
+	ErrTeacherNotAssigned = errors.New("teacher is not assigned to the course offering")
+	// ErrRoutineClash is returned, wrapped with the details, when a routine
+	// entry would double-book a room or a teacher.
+	ErrRoutineClash = errors.New("routine clash")
+	// ErrNotBatchEditor is returned when someone other than an academic
+	// admin or a current CR of the batch changes its schedules.
+	ErrNotBatchEditor = errors.New("only academic admins and the batch's CRs can do this")
+)
+
+// feedDaysBack is how many days of past classes the routine feed keeps.
+const feedDaysBack = 30
+
+// Class is one meeting of a routine entry.
+type Class struct {
+	StartsAt time.Time           `json:"starts_at"`
+	EndsAt   time.Time           `json:"ends_at"`
+	Entry    domain.RoutineEntry `json:"entry"`
+}
+
+// Routines manages the weekly class routine of batches. Academic admins and
+// the batch's CRs write it; saving refuses entries that double-book a room
+// or a teacher, checking and saving under a lock on the university's weekday
+// so concurrent saves cannot book the same slot. Times are wall clock times in the campus time zone. Classes
+// are not held on the days off of the department's academic calendar.
+type Routines struct {
+	entries   Usecase[domain.RoutineEntry]
+	repo      domain.RoutineRepository
+	offerings Usecase[domain.CourseOffering]
+	batches   Usecase[domain.Batch]
+	students  Usecase[domain.Student]
+	calendar  *AcademicCalendar
+	outbox    domain.Outbox
+	loc       *time.Location
+}
+
+// NewRoutines reads routine times and dates in loc.
+func NewRoutines(entries Usecase[domain.RoutineEntry], repo domain.RoutineRepository, offerings Usecase[domain.CourseOffering], batches Usecase[domain.Batch], students Usecase[domain.Student], calendar *AcademicCalendar, outbox domain.Outbox, loc *time.Location) *Routines {
+	return &Routines{
+		entries:   entries,
+		repo:      repo,
+		offerings: offerings,
+		batches:   batches,
+		students:  students,
+		calendar:  calendar,
+		outbox:    outbox,
+		loc:       loc,
+	}
+}
+
+// Create adds a weekly class for entry.OfferingID.
+func (r *Routines) Create(ctx context.Context, entry *domain.RoutineEntry) (*domain.RoutineEntry, error) {
+	err := r.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.prepare(ctx, entry); err != nil {
+			return err
+		}
+		return r.entries.Create(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.entries.GetByID(ctx, entry.ID)
+}
+
+// Update replaces the offering, teacher, time, room, validity and note of
+// an entry.
+func (r *Routines) Update(ctx context.Context, changes *domain.RoutineEntry) (*domain.RoutineEntry, error) {
+	entry, err := r.entries.GetByID(ctx, changes.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeBatchEditor(ctx, r.students, entry.BatchID); err != nil {
+		return nil, err
+	}
+
+	entry.OfferingID = changes.OfferingID
+	entry.TeacherID = changes.TeacherID
+	entry.Weekday = changes.Weekday
+	entry.StartTime = changes.StartTime
+	entry.EndTime = changes.EndTime
+	entry.Room = changes.Room
+	entry.ValidFrom = changes.ValidFrom
+	entry.ValidUntil = changes.ValidUntil
+	entry.Note = changes.Note
+	entry.UpdatedByID = changes.UpdatedByID
+	err = r.outbox.Transaction(ctx, func(ctx context.Context) error {
+		if err := r.prepare(ctx, entry); err != nil {
+			return err
+		}
+		return r.entries.Update(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return r.entries.GetByID(ctx, entry.ID)
+}
+
+// Delete removes an entry from the routine.
+func (r *Routines) Delete(ctx context.Context, id uuid.UUID) error {
+	entry, err := r.entries.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := authorizeBatchEditor(ctx, r.students, entry.BatchID); err != nil {
+		return err
+	}
+	return r.entries.Delete(ctx, id)
+}
+
+// Week returns the batch's entries in effect on the date (YYYY-MM-DD,
+// today when empty), by weekday and start time.
+func (r *Routines) Week(ctx context.Context, batchID uuid.UUID, date string) ([]domain.RoutineEntry, error) {
+	day, err := parseDate(date, r.loc)
+	if err != nil {
+		return nil, err
+	}
+	d := day.Format(dateLayout)
+	return r.repo.Batch(ctx, batchID, d, d)
+}
+
+// Calendar returns the batch's classes from a month ago to a year ahead as
+// a calendar feed, one event per class, leaving out days off.
+func (r *Routines) Calendar(ctx context.Context, batchID uuid.UUID) (ical.Calendar, error) {
+	batch, err := r.batches.GetByID(ctx, batchID)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	today, _ := parseDate("", r.loc)
+	from, until := today.AddDate(0, 0, -feedDaysBack), today.AddDate(1, 0, 0)
+	entries, err := r.repo.Batch(ctx, batchID, from.Format(dateLayout), until.Format(dateLayout))
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+	off, err := r.calendar.DaysOff(ctx, batchScope(batch.UniversityID, batch.DepartmentID), from, until)
+	if err != nil {
+		return ical.Calendar{}, err
+	}
+
+	cal := ical.Calendar{Name: batch.Name + " class routine", Events: []ical.Event{}}
+	for _, class := range r.classes(entries, from, until, off) {
+		cal.Events = append(cal.Events, classEvent(class))
+	}
+	return cal, nil
+}
+
+// Today lists the signed-in student's classes on the date (YYYY-MM-DD,
+// today when empty), by start time. There are none on days off.
+func (r *Routines) Today(ctx context.Context, date string) ([]Class, error) {
+	day, err := parseDate(date, r.loc)
+	if err != nil {
+		return nil, err
+	}
+	actor, _ := domain.ActorFromContext(ctx)
+	found, _, err := r.students.GetAll(ctx, map[string]interface{}{"user_id": actor.UserID}, 1, 0)
+	if err != nil {
+		return nil, err
+	}
+	if actor.UserID == uuid.Nil || len(found) == 0 {
+		return nil, ErrNoStudentProfile
+	}
+
+	d := day.Format(dateLayout)
+	entries, err := r.repo.Batch(ctx, found[0].BatchID, d, d)
+	if err != nil {
+		return nil, err
+	}
+	off, err := r.calendar.DaysOff(ctx, batchScope(found[0].UniversityID, found[0].DepartmentID), day, day)
+	if err != nil {
+		return nil, err
+	}
+	return r.classes(entries, day, day, off), nil
+}
+
+// prepare checks an entry, fills in its teacher, batch, university and
+// department from the offering, and looks for clashes. It runs in the
+// transaction of the save and locks the entry's weekday until it commits.
+func (r *Routines) prepare(ctx context.Context, entry *domain.RoutineEntry) error {
+	entry.Room = strings.TrimSpace(entry.Room)
+	if err := r.validate(entry); err != nil {
+		return err
+	}
+	offering, err := r.offerings.GetByID(ctx, entry.OfferingID)
+	if err != nil {
+		return err
+	}
+	if err := authorizeBatchEditor(ctx, r.students, offering.BatchID); err != nil {
+		return err
+	}
+
+	if entry.TeacherID == nil && len(offering.Teachers) == 1 {
+		entry.TeacherID = &offering.Teachers[0].ID
+	}
+	if entry.TeacherID != nil {
+		assigned := false
+		for _, t := range offering.Teachers {
+			assigned = assigned || t.ID == *entry.TeacherID
+		}
+		if !assigned {
+			return ErrTeacherNotAssigned
+		}
+	}
+	entry.BatchID = offering.BatchID
+	entry.UniversityID = offering.UniversityID
+	entry.DepartmentID = offering.DepartmentID
+	entry.Offering, entry.Teacher = nil, nil
+
+	if err := r.repo.LockWeekday(ctx, entry.UniversityID, entry.Weekday); err != nil {
+		return err
+	}
+	clashes, err := r.repo.Clashes(ctx, entry)
+	if err != nil {
+		return err
+	}
+	if len(clashes) > 0 {
+		return fmt.Errorf("%w: %s", ErrRoutineClash, describeClash(entry, &clashes[0]))
+	}
+	return nil
+}
+
+func (r *Routines) validate(e *domain.RoutineEntry) error {
+	start, end := clockMinutes(e.StartTime), clockMinutes(e.EndTime)
+	from, errFrom := time.Parse(dateLayout, e.ValidFrom)
+	until, errUntil := time.Parse(dateLayout, e.ValidUntil)
+	if e.Weekday < 0 || e.Weekday > 6 || start < 0 || end < 0 || start >= end || e.Room == "" ||
+		errFrom != nil || errUntil != nil || until.Before(from) {
+		return ErrInvalidRoutineEntry
+	}
+	return nil
+}
+
+// classes expands entries into their meetings on the days from first to
+// last (both midnights in the campus time zone) that are not off, by start
+// time.
+func (r *Routines) classes(entries []domain.RoutineEntry, first, last time.Time, off map[string]bool) []Class {
+	classes := []Class{}
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format(dateLayout)
+		if off[date] {
+			continue
+		}
+		for _, e := range entries {
+			if int(day.Weekday()) != e.Weekday || date < e.ValidFrom || date > e.ValidUntil {
+				continue
+			}
+			start, end := clockMinutes(e.StartTime), clockMinutes(e.EndTime)
+			classes = append(classes, Class{
+				StartsAt: time.Date(day.Year(), day.Month(), day.Day(), start/60, start%60, 0, 0, r.loc),
+				EndsAt:   time.Date(day.Year(), day.Month(), day.Day(), end/60, end%60, 0, 0, r.loc),
+				Entry:    e,
+			})
+		}
+	}
+	return classes
+}
+
+// authorizeBatchEditor allows academic admins and the current CRs of the
+// batch.
+func authorizeBatchEditor(ctx context.Context, students Usecase[domain.Student], batchID uuid.UUID) error {
+	actor, _ := domain.ActorFromContext(ctx)
+	switch domain.Role(actor.Role) {
+	case domain.RoleSuperAdmin, domain.RoleUniversityAdmin, domain.RoleDepartmentAdmin:
+		return nil
+	case domain.RoleCR:
+		if actor.UserID == uuid.Nil {
+			break
+		}
+		found, _, err := students.GetAll(ctx, map[string]interface{}{"user_id": actor.UserID}, 1, 0)
+		if err != nil {
+			return err
+		}
+		if len(found) == 1 && found[0].IsCR && found[0].BatchID == batchID {
+			return nil
+		}
+	}
+	return ErrNotBatchEditor
+}
+
+// batchScope is the academic calendar a batch follows: its department's.
+func batchScope(universityID, departmentID uuid.UUID) CalendarScope {
+	return CalendarScope{UniversityID: universityID, DepartmentID: &departmentID}
+}
+
+// describeClash says what entry runs into.
+func describeClash(entry, other *domain.RoutineEntry) string {
+	when := fmt.Sprintf("%s %s-%s", time.Weekday(other.Weekday), other.StartTime, other.EndTime)
+	if strings.EqualFold(other.Room, entry.Room) && other.UniversityID == entry.UniversityID {
+		return fmt.Sprintf("room %s is used by %s on %s", other.Room, entryCourse(other), when)
+	}
+	teacher := "the teacher"
+	if other.Teacher != nil && other.Teacher.Name != "" {
+		teacher = other.Teacher.Name
+	}
+	return fmt.Sprintf("%s teaches %s on %s", teacher, entryCourse(other), when)
+}
+
+func entryCourse(e *domain.RoutineEntry) string {
+	if e.Offering == nil || e.Offering.Course == nil {
+		return "another class"
+	}
+	name := e.Offering.Course.CourseCode
+	if e.Offering.Section != "" {
+		name += " (section " + e.Offering.Section + ")"
+	}
+	return name
+}
+
+// classEvent turns a class into a feed event. Its UID is stable for the
+// entry and date, so edits replace the event in subscribed calendars.
+func classEvent(c Class) ical.Event {
+	e := c.Entry
+	event := ical.Event{
+		UID:      fmt.Sprintf("routine-%s-%s@campusassistant", e.ID, c.StartsAt.Format("20060102")),
+		Summary:  entryCourse(&e),
+		Location: e.Room,
+		Start:    c.StartsAt,
+		End:      c.EndsAt,
+		Status:   ical.StatusConfirmed,
+		Updated:  e.UpdatedAt,
+	}
+	if e.Offering != nil && e.Offering.Course != nil {
+		event.Summary = e.Offering.Course.CourseCode + " " + e.Offering.Course.CourseTitle
+		if e.Offering.Section != "" {
+			event.Summary += " (section " + e.Offering.Section + ")"
+		}
+	}
+	var notes []string
+	if e.Teacher != nil && e.Teacher.Name != "" {
+		notes = append(notes, "Teacher: "+e.Teacher.Name)
+	}
+	if e.Note != "" {
+		notes = append(notes, e.Note)
+	}
+	event.Description = strings.Join(notes, "\n")
+	return event
+}
@@ -0,0 +1,184 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// memRoutineRepo is an in-memory domain.RoutineRepository whose Clashes
+// applies the same conditions as the postgres query.
+type memRoutineRepo struct {
+	*memRepo[domain.RoutineEntry]
+	locked []int
+}
+
+func (r *memRoutineRepo) LockWeekday(_ context.Context, _ uuid.UUID, weekday int) error {
+	r.locked = append(r.locked, weekday)
+	return nil
+}
+
+func (r *memRoutineRepo) Clashes(_ context.Context, entry *domain.RoutineEntry) ([]domain.RoutineEntry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var clashes []domain.RoutineEntry
+	for _, e := range r.rows {
+		if e.ID == entry.ID || e.Weekday != entry.Weekday ||
+			e.StartTime >= entry.EndTime || e.EndTime <= entry.StartTime ||
+			e.ValidFrom > entry.ValidUntil || e.ValidUntil < entry.ValidFrom {
+			continue
+		}
+		sameRoom := e.UniversityID == entry.UniversityID && strings.EqualFold(e.Room, entry.Room)
+		sameTeacher := entry.TeacherID != nil && e.TeacherID != nil && *e.TeacherID == *entry.TeacherID
+		if sameRoom || sameTeacher {
+			clashes = append(clashes, *e)
+		}
+	}
+	return clashes, nil
+}
+
+func (r *memRoutineRepo) Batch(context.Context, uuid.UUID, string, string) ([]domain.RoutineEntry, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestRoutineClashes(t *testing.T) {
+	university := uuid.New()
+	karim := domain.Teacher{Base: domain.Base{ID: uuid.New()}, Name: "Dr. Karim"}
+	nasrin := domain.Teacher{Base: domain.Base{ID: uuid.New()}, Name: "Ms. Nasrin"}
+	outsider := domain.Teacher{Base: domain.Base{ID: uuid.New()}, Name: "Mr. Alam"}
+
+	// CSE101 is taught by Dr. Karim; CSE203 by either of them
+	cse101 := domain.CourseOffering{
+		Base:         domain.Base{ID: uuid.New()},
+		Course:       &domain.Course{CourseCode: "CSE101"},
+		BatchID:      uuid.New(),
+		UniversityID: university,
+		DepartmentID: uuid.New(),
+		Teachers:     []domain.Teacher{karim},
+	}
+	cse203 := domain.CourseOffering{
+		Base:         domain.Base{ID: uuid.New()},
+		BatchID:      uuid.New(),
+		UniversityID: university,
+		DepartmentID: uuid.New(),
+		Teachers:     []domain.Teacher{karim, nasrin},
+	}
+	// Dr. Karim teaches CSE101 in Lab 3 on Sundays at 9
+	booked := domain.RoutineEntry{
+		Base:         domain.Base{ID: uuid.New()},
+		OfferingID:   cse101.ID,
+		Offering:     &cse101,
+		TeacherID:    &karim.ID,
+		Teacher:      &karim,
+		BatchID:      cse101.BatchID,
+		UniversityID: university,
+		Weekday:      0,
+		StartTime:    "09:00",
+		EndTime:      "10:00",
+		Room:         "Lab 3",
+		ValidFrom:    "2026-01-01",
+		ValidUntil:   "2026-06-30",
+	}
+
+	entry := func(edit func(e *domain.RoutineEntry)) domain.RoutineEntry {
+		e := domain.RoutineEntry{
+			OfferingID: cse203.ID,
+			TeacherID:  &nasrin.ID,
+			Weekday:    0,
+			StartTime:  "09:30",
+			EndTime:    "10:30",
+			Room:       "Lab 4",
+			ValidFrom:  "2026-01-01",
+			ValidUntil: "2026-06-30",
+		}
+		if edit != nil {
+			edit(&e)
+		}
+		return e
+	}
+
+	tests := []struct {
+		name      string
+		entry     domain.RoutineEntry
+		wantErr   error
+		wantClash string
+	}{
+		{name: "other room and teacher", entry: entry(nil)},
+		{name: "same room, other case", entry: entry(func(e *domain.RoutineEntry) { e.Room = " lab 3 " }), wantErr: ErrRoutineClash, wantClash: "room Lab 3 is used by CSE101 on Sunday 09:00-10:00"},
+		{name: "same teacher, other room", entry: entry(func(e *domain.RoutineEntry) { e.TeacherID = &karim.ID }), wantErr: ErrRoutineClash, wantClash: "Dr. Karim teaches CSE101 on Sunday 09:00-10:00"},
+		{name: "same room right after", entry: entry(func(e *domain.RoutineEntry) { e.Room, e.StartTime, e.EndTime = "Lab 3", "10:00", "11:00" })},
+		{name: "same room on another weekday", entry: entry(func(e *domain.RoutineEntry) { e.Room, e.Weekday = "Lab 3", 1 })},
+		{name: "same room after the booking ends", entry: entry(func(e *domain.RoutineEntry) { e.Room, e.ValidFrom, e.ValidUntil = "Lab 3", "2026-07-01", "2026-12-31" })},
+		{name: "teacher not on the offering", entry: entry(func(e *domain.RoutineEntry) { e.TeacherID = &outsider.ID }), wantErr: ErrTeacherNotAssigned},
+		{name: "ends before it starts", entry: entry(func(e *domain.RoutineEntry) { e.StartTime, e.EndTime = "10:30", "09:30" }), wantErr: ErrInvalidRoutineEntry},
+		{name: "no room", entry: entry(func(e *domain.RoutineEntry) { e.Room = "  " }), wantErr: ErrInvalidRoutineEntry},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &memRoutineRepo{memRepo: newMemRepo(booked)}
+			routines := NewRoutines(
+				NewGenericUsecase[domain.RoutineEntry](repo), repo,
+				NewGenericUsecase[domain.CourseOffering](newMemRepo(cse101, cse203)),
+				nil, NewGenericUsecase[domain.Student](newMemRepo[domain.Student]()),
+				nil, &memOutbox{}, time.UTC,
+			)
+			ctx := domain.WithActor(context.Background(), domain.Actor{Role: string(domain.RoleDepartmentAdmin)})
+
+			e := tt.entry
+			saved, err := routines.Create(ctx, &e)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Create() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantClash != "" && !strings.HasSuffix(err.Error(), tt.wantClash) {
+				t.Errorf("Create() error = %q, want it to say %q", err, tt.wantClash)
+			}
+			if tt.wantErr != nil {
+				if repo.len() != 1 {
+					t.Errorf("%d entries stored, want the refused one left out", repo.len())
+				}
+				return
+			}
+			if saved.UniversityID != university || saved.BatchID != cse203.BatchID {
+				t.Errorf("saved entry not placed from its offering: %+v", saved)
+			}
+			if len(repo.locked) != 1 || repo.locked[0] != tt.entry.Weekday {
+				t.Errorf("locked weekdays %v, want [%d]", repo.locked, tt.entry.Weekday)
+			}
+		})
+	}
+}
+
+func TestRoutineTeacherDefaultsAndSelfClash(t *testing.T) {
+	karim := domain.Teacher{Base: domain.Base{ID: uuid.New()}, Name: "Dr. Karim"}
+	offering := domain.CourseOffering{Base: domain.Base{ID: uuid.New()}, UniversityID: uuid.New(), Teachers: []domain.Teacher{karim}}
+	repo := &memRoutineRepo{memRepo: newMemRepo[domain.RoutineEntry]()}
+	routines := NewRoutines(
+		NewGenericUsecase[domain.RoutineEntry](repo), repo,
+		NewGenericUsecase[domain.CourseOffering](newMemRepo(offering)),
+		nil, NewGenericUsecase[domain.Student](newMemRepo[domain.Student]()),
+		nil, &memOutbox{}, time.UTC,
+	)
+	ctx := domain.WithActor(context.Background(), domain.Actor{Role: string(domain.RoleDepartmentAdmin)})
+
+	e := domain.RoutineEntry{OfferingID: offering.ID, Weekday: 2, StartTime: "11:00", EndTime: "12:00", Room: "Lab 1", ValidFrom: "2026-01-01", ValidUntil: "2026-06-30"}
+	saved, err := routines.Create(ctx, &e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if saved.TeacherID == nil || *saved.TeacherID != karim.ID {
+		t.Fatalf("TeacherID = %v, want the offering's only teacher", saved.TeacherID)
+	}
+
+	// Moving an entry within its own slot does not clash with itself
+	moved := *saved
+	moved.StartTime, moved.EndTime = "11:30", "12:30"
+	if _, err := routines.Update(ctx, &moved); err != nil {
+		t.Fatalf("Update() error = %v, want the entry to move", err)
+	}
+}
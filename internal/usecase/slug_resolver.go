@@ -0,0 +1,78 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidSlugPath is returned for paths that are empty or deeper than
+// university/department/batch.
+var ErrInvalidSlugPath = errors.New("path must be university[/department[/batch]]")
+
+// ResolvedPath holds the entities named by a university/department/batch
+// slug path. Levels beyond the given path are nil. Path is the canonical slug
+// path; it differs from the request when an old (renamed) slug was used.
+type ResolvedPath struct {
+	Path       string             `json:"path"`
+	University *domain.University `json:"university"`
+	Department *domain.Department `json:"department,omitempty"`
+	Batch      *domain.Batch      `json:"batch,omitempty"`
+}
+
+// SlugResolver turns front-end URLs such as "du/psychology/batch-10" into
+// entities, resolving each level inside the one above it.
+type SlugResolver struct {
+	universities domain.Repository[domain.University]
+	departments  domain.Repository[domain.Department]
+	batches      domain.Repository[domain.Batch]
+	aliases      domain.SlugAliasRepository
+}
+
+func NewSlugResolver(
+	universities domain.Repository[domain.University],
+	departments domain.Repository[domain.Department],
+	batches domain.Repository[domain.Batch],
+	aliases domain.SlugAliasRepository,
+) *SlugResolver {
+	return &SlugResolver{universities: universities, departments: departments, batches: batches, aliases: aliases}
+}
+
+// Resolve looks up 1-3 slugs in order. The error names the level that failed
+// and wraps the lookup error (gorm.ErrRecordNotFound when a slug is unknown).
+func (r *SlugResolver) Resolve(ctx context.Context, slugs []string) (*ResolvedPath, error) {
+	if len(slugs) == 0 || len(slugs) > 3 {
+		return nil, ErrInvalidSlugPath
+	}
+
+	var (
+		out ResolvedPath
+		err error
+	)
+
+	if out.University, err = findBySlug(ctx, r.universities, r.aliases, slugs[0], uuid.Nil); err != nil {
+		return nil, fmt.Errorf("university %q: %w", slugs[0], err)
+	}
+	out.Path = out.University.Slug
+	if len(slugs) == 1 {
+		return &out, nil
+	}
+
+	if out.Department, err = findBySlug(ctx, r.departments, r.aliases, slugs[1], out.University.ID); err != nil {
+		return nil, fmt.Errorf("department %q: %w", slugs[1], err)
+	}
+	out.Path += "/" + out.Department.Slug
+	if len(slugs) == 2 {
+		return &out, nil
+	}
+
+	if out.Batch, err = findBySlug(ctx, r.batches, r.aliases, slugs[2], out.Department.ID); err != nil {
+		return nil, fmt.Errorf("batch %q: %w", slugs[2], err)
+	}
+	out.Path += "/" + out.Batch.Slug
+	return &out, nil
+}
@@ -0,0 +1,91 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"campusassistant-api/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSlugsDisabled is returned for entities that do not implement domain.Slugged.
+	ErrSlugsDisabled = errors.New("slug lookup is not supported for this entity")
+	// ErrSlugAmbiguous is returned when a scoped slug matches in several scopes
+	// and no scope was given to pick one.
+	ErrSlugAmbiguous = errors.New("slug matches more than one entity")
+)
+
+// SlugUsecase looks entities up by slug for models implementing domain.Slugged.
+type SlugUsecase[T any] interface {
+	// GetBySlug finds the entity with slug inside scope (the SlugScope column
+	// value). scope may be uuid.Nil when the slug is unambiguous on its own.
+	// A retired slug still finds the entity; its GetSlug then differs from slug.
+	GetBySlug(ctx context.Context, slug string, scope uuid.UUID) (*T, error)
+}
+
+// slugScope returns T's scope column and whether T has slugs at all.
+func slugScope[T any]() (string, bool) {
+	s, ok := any(new(T)).(domain.Slugged)
+	if !ok {
+		return "", false
+	}
+	return s.SlugScope(), true
+}
+
+func (u *genericUsecase[T]) GetBySlug(ctx context.Context, slug string, scope uuid.UUID) (*T, error) {
+	return findBySlug(ctx, u.repo, u.opts.SlugAliases, slug, scope)
+}
+
+// findBySlug is GetBySlug over a bare repository. aliases may be nil.
+func findBySlug[T any](ctx context.Context, repo domain.Repository[T], aliases domain.SlugAliasRepository, slug string, scope uuid.UUID) (*T, error) {
+	column, ok := slugScope[T]()
+	if !ok {
+		return nil, ErrSlugsDisabled
+	}
+
+	filter := map[string]interface{}{"slug": slug}
+	if column != "" && scope != uuid.Nil {
+		filter[column] = scope
+	}
+
+	entities, _, err := repo.GetAll(ctx, filter, 2, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch len(entities) {
+	case 0:
+		return findByAlias(ctx, repo, aliases, slug, scope)
+	case 1:
+		return &entities[0], nil
+	default:
+		return nil, ErrSlugAmbiguous
+	}
+}
+
+// findByAlias follows a slug retired by a rename to the entity's current record.
+func findByAlias[T any](ctx context.Context, repo domain.Repository[T], aliases domain.SlugAliasRepository, slug string, scope uuid.UUID) (*T, error) {
+	if aliases == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	column, _ := slugScope[T]()
+	if column == "" {
+		scope = uuid.Nil
+	}
+	found, err := aliases.Find(ctx, reflect.TypeOf(new(T)).Elem().Name(), slug, scope)
+	if err != nil {
+		return nil, err
+	}
+	switch len(found) {
+	case 0:
+		return nil, gorm.ErrRecordNotFound
+	case 1:
+		return repo.GetByID(ctx, found[0].EntityID)
+	default:
+		return nil, ErrSlugAmbiguous
+	}
+}
@@ -0,0 +1,366 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"syscall"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/logger"
+	"campusassistant-api/pkg/webhook"
+
+	"github.com/google/uuid"
+)
+
+const (
+	webhookMaxAttempts   = 8
+	webhookBaseBackoff   = 30 * time.Second
+	webhookMaxBackoff    = 6 * time.Hour
+	webhookTimeout       = 10 * time.Second
+	webhookInlineBudget  = 20 * time.Second
+	webhookResponseLimit = 1024
+	webhookRetryBatch    = 50
+)
+
+var (
+	ErrInvalidWebhookURL = errors.New("webhook url must be an absolute http or https URL")
+	ErrNoWebhookEvents   = errors.New("webhook needs at least one event pattern")
+	// ErrWebhookAddressBlocked is returned for webhook hosts that are, or
+	// resolve to, loopback, private, link-local, multicast, unspecified or
+	// other special-purpose addresses.
+	ErrWebhookAddressBlocked = errors.New("webhook url must point to a public address")
+)
+
+// WebhookPayload is the JSON body POSTed to subscribers. ID identifies the
+// event and stays the same across retries and redeliveries, so receivers
+// can de-duplicate on it.
+type WebhookPayload struct {
+	ID           uuid.UUID       `json:"id"`
+	Event        string          `json:"event"`
+	OccurredAt   time.Time       `json:"occurred_at"`
+	UniversityID *uuid.UUID      `json:"university_id,omitempty"`
+	DepartmentID *uuid.UUID      `json:"department_id,omitempty"`
+	Data         json.RawMessage `json:"data"`
+}
+
+// WebhookDispatcher fans domain events out to matching webhook
+// subscriptions, signs each request and retries failures with exponential
+// backoff. Every attempt is recorded in the delivery log.
+type WebhookDispatcher struct {
+	repo   domain.WebhookRepository
+	client *http.Client
+	// inlineBudget bounds the first attempts Dispatch makes itself, so a
+	// slow receiver cannot hold the relay's batch past its lease
+	inlineBudget time.Duration
+}
+
+// NewWebhookDispatcher uses client for outgoing requests; nil selects a
+// client with a 10s timeout that does not follow redirects, bypasses
+// proxies and refuses to connect to non-public addresses. The address is
+// checked when dialing, so a host re-resolving to an internal address after
+// ValidateWebhook accepted it is still refused.
+func NewWebhookDispatcher(repo domain.WebhookRepository, client *http.Client) *WebhookDispatcher {
+	if client == nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		transport.DialContext = (&net.Dialer{
+			Timeout: webhookTimeout,
+			Control: publicAddressOnly,
+		}).DialContext
+		client = &http.Client{
+			Transport: transport,
+			Timeout:   webhookTimeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		}
+	}
+	return &WebhookDispatcher{repo: repo, client: client, inlineBudget: webhookInlineBudget}
+}
+
+func (d *WebhookDispatcher) Name() string { return "webhooks" }
+
+// Dispatch records a delivery for each subscription interested in an outbox
+// event and makes the first attempts right away, as long as they fit in 20
+// seconds; the rest are left due for the retry worker. The payload ID is the
+// outbox event ID, so relay retries never create a second delivery for the
+// same subscription and receivers can de-duplicate on it.
+func (d *WebhookDispatcher) Dispatch(ctx context.Context, e *domain.OutboxEvent) error {
+	return d.deliver(ctx, WebhookPayload{
+		ID:           e.ID,
+		Event:        e.EventName,
+		OccurredAt:   e.CreatedAt.UTC(),
+		UniversityID: e.UniversityID,
+		DepartmentID: e.DepartmentID,
+		Data:         json.RawMessage(e.Payload),
+	})
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, payload WebhookPayload) error {
+	var universityID, departmentID uuid.UUID
+	if payload.UniversityID != nil {
+		universityID = *payload.UniversityID
+	}
+	if payload.DepartmentID != nil {
+		departmentID = *payload.DepartmentID
+	}
+
+	subs, err := d.repo.Active(ctx, universityID, departmentID)
+	if err != nil {
+		return err
+	}
+
+	start := time.Now()
+	var raw []byte
+	var errs []error
+	for i := range subs {
+		sub := &subs[i]
+		if !sub.Matches(payload.Event) || !sub.InScope(universityID, departmentID) {
+			continue
+		}
+		done, err := d.repo.HasDelivery(ctx, sub.ID, payload.ID)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if done {
+			continue
+		}
+		if raw == nil {
+			if raw, err = json.Marshal(payload); err != nil {
+				return fmt.Errorf("encode %s payload: %w", payload.Event, err)
+			}
+		}
+
+		now := time.Now()
+		delivery := &domain.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        payload.ID,
+			EventName:      payload.Event,
+			Payload:        raw,
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  &now,
+		}
+		if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if now.Sub(start) < d.inlineBudget {
+			d.attempt(ctx, sub, delivery)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// StartRetries re-attempts due deliveries every interval in the background.
+func (d *WebhookDispatcher) StartRetries(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := d.RetryDue(context.Background()); err != nil {
+				logger.Errorf("webhook retry pass failed: %v", err)
+			}
+		}
+	}()
+}
+
+// RetryDue attempts every pending delivery whose backoff has elapsed.
+// Deliveries for deleted or disabled subscriptions are marked failed.
+func (d *WebhookDispatcher) RetryDue(ctx context.Context) error {
+	due, err := d.repo.DueDeliveries(ctx, time.Now(), webhookRetryBatch)
+	if err != nil {
+		return err
+	}
+
+	subs := map[uuid.UUID]*domain.WebhookSubscription{}
+	for i := range due {
+		delivery := &due[i]
+		sub, ok := subs[delivery.SubscriptionID]
+		if !ok {
+			sub, _ = d.repo.GetByID(ctx, delivery.SubscriptionID)
+			subs[delivery.SubscriptionID] = sub
+		}
+		if sub == nil || !sub.Active {
+			delivery.Status = domain.WebhookDeliveryFailed
+			delivery.NextAttemptAt = nil
+			delivery.LastError = "subscription deleted or disabled"
+			if err := d.repo.UpdateDelivery(ctx, delivery); err != nil {
+				logger.Errorf("failed to update webhook delivery %s: %v", delivery.ID, err)
+			}
+			continue
+		}
+		d.attempt(ctx, sub, delivery)
+	}
+	return nil
+}
+
+// Redeliver sends a logged delivery again as a new delivery row and returns
+// it after one synchronous attempt. Failed redeliveries join the normal
+// retry schedule.
+func (d *WebhookDispatcher) Redeliver(ctx context.Context, subscriptionID, deliveryID uuid.UUID) (*domain.WebhookDelivery, error) {
+	sub, err := d.repo.GetByID(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	orig, err := d.repo.GetDelivery(ctx, subscriptionID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := &domain.WebhookDelivery{
+		SubscriptionID: sub.ID,
+		EventID:        orig.EventID,
+		EventName:      orig.EventName,
+		Payload:        orig.Payload,
+		Status:         domain.WebhookDeliveryPending,
+		RedeliveryOf:   &orig.ID,
+	}
+	if err := d.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	d.attempt(ctx, sub, delivery)
+	return delivery, nil
+}
+
+// attempt POSTs the delivery once and stores the outcome and, on failure,
+// when to try next.
+func (d *WebhookDispatcher) attempt(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery) {
+	start := time.Now()
+	code, body, err := d.post(ctx, sub, delivery, start)
+
+	delivery.Attempts++
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	delivery.ResponseCode = code
+	delivery.ResponseBody = body
+	delivery.LastError = ""
+
+	switch {
+	case err == nil && code >= 200 && code < 300:
+		now := time.Now()
+		delivery.Status = domain.WebhookDeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+	default:
+		if err != nil {
+			delivery.LastError = err.Error()
+		} else {
+			delivery.LastError = fmt.Sprintf("receiver answered %d", code)
+		}
+		if delivery.Attempts >= webhookMaxAttempts {
+			delivery.Status = domain.WebhookDeliveryFailed
+			delivery.NextAttemptAt = nil
+		} else {
+			next := time.Now().Add(backoff(delivery.Attempts, webhookBaseBackoff, webhookMaxBackoff))
+			delivery.Status = domain.WebhookDeliveryPending
+			delivery.NextAttemptAt = &next
+		}
+	}
+
+	if err := d.repo.UpdateDelivery(context.WithoutCancel(ctx), delivery); err != nil {
+		logger.Errorf("failed to update webhook delivery %s: %v", delivery.ID, err)
+	}
+}
+
+func (d *WebhookDispatcher) post(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery, at time.Time) (int, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "CampusAssistant-Webhooks/1.0")
+	req.Header.Set(webhook.EventHeader, delivery.EventName)
+	req.Header.Set(webhook.DeliveryHeader, delivery.ID.String())
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(sub.Secret, at, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	return resp.StatusCode, string(body), nil
+}
+
+// ValidateWebhook checks the URL and event patterns of a subscription. The
+// URL's host must resolve to public addresses only.
+func ValidateWebhook(ctx context.Context, sub *domain.WebhookSubscription) error {
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return ErrInvalidWebhookURL
+	}
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil || len(addrs) == 0 {
+		return ErrInvalidWebhookURL
+	}
+	for _, a := range addrs {
+		if !publicIP(a.IP) {
+			return ErrWebhookAddressBlocked
+		}
+	}
+
+	events := sub.Events[:0]
+	for _, e := range sub.Events {
+		if e = strings.TrimSpace(e); e != "" {
+			events = append(events, e)
+		}
+	}
+	if len(events) == 0 {
+		return ErrNoWebhookEvents
+	}
+	sub.Events = events
+	return nil
+}
+
+// publicAddressOnly is a net.Dialer Control hook that refuses connections
+// to non-public addresses.
+func publicAddressOnly(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	if ip := net.ParseIP(host); ip == nil || !publicIP(ip) {
+		return ErrWebhookAddressBlocked
+	}
+	return nil
+}
+
+// specialNets are the non-public ranges the net.IP predicates miss: shared
+// address space (carrier-grade NAT), IETF protocol assignments, benchmarking
+// networks and the NAT64 prefix, which embeds any IPv4 address.
+var specialNets = func() []*net.IPNet {
+	var nets []*net.IPNet
+	for _, cidr := range []string{"100.64.0.0/10", "192.0.0.0/24", "198.18.0.0/15", "64:ff9b::/96"} {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets = append(nets, n)
+	}
+	return nets
+}()
+
+// publicIP reports whether webhooks may be sent to ip.
+func publicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() {
+		return false
+	}
+	for _, n := range specialNets {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,478 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"campusassistant-api/internal/domain"
+	"campusassistant-api/pkg/webhook"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// memWebhookRepo is an in-memory domain.WebhookRepository.
+type memWebhookRepo struct {
+	mu         sync.Mutex
+	subs       map[uuid.UUID]*domain.WebhookSubscription
+	deliveries []*domain.WebhookDelivery
+}
+
+func newMemWebhookRepo(subs ...domain.WebhookSubscription) *memWebhookRepo {
+	r := &memWebhookRepo{subs: map[uuid.UUID]*domain.WebhookSubscription{}}
+	for i := range subs {
+		r.subs[subs[i].ID] = &subs[i]
+	}
+	return r
+}
+
+func (r *memWebhookRepo) Create(_ context.Context, s *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs[s.ID] = s
+	return nil
+}
+
+func (r *memWebhookRepo) GetByID(_ context.Context, id uuid.UUID) (*domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if s, ok := r.subs[id]; ok {
+		return s, nil
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memWebhookRepo) GetAll(context.Context, map[string]interface{}, int, int) ([]domain.WebhookSubscription, int64, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (r *memWebhookRepo) Update(_ context.Context, s *domain.WebhookSubscription) error {
+	return r.Create(context.Background(), s)
+}
+
+func (r *memWebhookRepo) Delete(_ context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs, id)
+	return nil
+}
+
+func (r *memWebhookRepo) Active(_ context.Context, universityID, departmentID uuid.UUID) ([]domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var subs []domain.WebhookSubscription
+	for _, s := range r.subs {
+		if s.Active && s.InScope(universityID, departmentID) {
+			subs = append(subs, *s)
+		}
+	}
+	return subs, nil
+}
+
+func (r *memWebhookRepo) UpdateSecret(_ context.Context, id uuid.UUID, secret string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subs[id].Secret = secret
+	return nil
+}
+
+func (r *memWebhookRepo) CreateDelivery(_ context.Context, d *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d.ID = uuid.New()
+	d.CreatedAt = time.Now()
+	r.deliveries = append(r.deliveries, d)
+	return nil
+}
+
+func (r *memWebhookRepo) UpdateDelivery(_ context.Context, d *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.deliveries {
+		if stored.ID == d.ID {
+			*stored = *d
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memWebhookRepo) GetDelivery(_ context.Context, subscriptionID, id uuid.UUID) (*domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.deliveries {
+		if d.ID == id && d.SubscriptionID == subscriptionID {
+			return d, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memWebhookRepo) HasDelivery(_ context.Context, subscriptionID, eventID uuid.UUID) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.deliveries {
+		if d.SubscriptionID == subscriptionID && d.EventID == eventID && d.RedeliveryOf == nil {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memWebhookRepo) ListDeliveries(context.Context, uuid.UUID, map[string]interface{}, int, int) ([]domain.WebhookDelivery, int64, error) {
+	return nil, 0, errors.New("not implemented")
+}
+
+func (r *memWebhookRepo) DueDeliveries(_ context.Context, now time.Time, limit int) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var due []domain.WebhookDelivery
+	for _, d := range r.deliveries {
+		if d.Status == domain.WebhookDeliveryPending && d.NextAttemptAt != nil && !d.NextAttemptAt.After(now) && len(due) < limit {
+			due = append(due, *d)
+		}
+	}
+	return due, nil
+}
+
+// receiver is a webhook endpoint answering with status and recording the
+// requests it got.
+type receiver struct {
+	*httptest.Server
+	mu       sync.Mutex
+	status   int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T, status int) *receiver {
+	rec := &receiver{status: status}
+	rec.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		rec.mu.Lock()
+		rec.requests = append(rec.requests, r)
+		rec.bodies = append(rec.bodies, body)
+		status := rec.status
+		rec.mu.Unlock()
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte("ack"))
+	}))
+	t.Cleanup(rec.Close)
+	return rec
+}
+
+func (rec *receiver) setStatus(status int) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.status = status
+}
+
+func (rec *receiver) calls() int {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	return len(rec.requests)
+}
+
+func testSubscription(url string) domain.WebhookSubscription {
+	sub := domain.WebhookSubscription{
+		URL:    url,
+		Events: pq.StringArray{"notice.*"},
+		Active: true,
+		Secret: "whsec_test",
+	}
+	sub.ID = uuid.New()
+	return sub
+}
+
+func testEvent() *domain.OutboxEvent {
+	return &domain.OutboxEvent{
+		ID:        uuid.New(),
+		EventName: "notice.created",
+		Payload:   []byte(`{"title":"Exam routine"}`),
+		CreatedAt: time.Now(),
+	}
+}
+
+func TestWebhookDispatchSignsAndSucceeds(t *testing.T) {
+	rec := newReceiver(t, http.StatusOK)
+	repo := newMemWebhookRepo(testSubscription(rec.URL))
+	d := NewWebhookDispatcher(repo, rec.Client())
+	e := testEvent()
+
+	if err := d.Dispatch(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.deliveries) != 1 || rec.calls() != 1 {
+		t.Fatalf("got %d deliveries and %d calls, want 1 and 1", len(repo.deliveries), rec.calls())
+	}
+	delivery := repo.deliveries[0]
+	if delivery.Status != domain.WebhookDeliverySucceeded || delivery.DeliveredAt == nil || delivery.NextAttemptAt != nil {
+		t.Fatalf("delivery = %+v, want succeeded", delivery)
+	}
+
+	req := rec.requests[0]
+	if err := webhook.Verify("whsec_test", req.Header.Get(webhook.SignatureHeader), rec.bodies[0], 0); err != nil {
+		t.Fatalf("signature does not verify: %v", err)
+	}
+	if got := req.Header.Get(webhook.EventHeader); got != e.EventName {
+		t.Errorf("event header = %q, want %q", got, e.EventName)
+	}
+	var payload WebhookPayload
+	if err := json.Unmarshal(rec.bodies[0], &payload); err != nil || payload.ID != e.ID {
+		t.Errorf("payload id = %v (%v), want %v", payload.ID, err, e.ID)
+	}
+
+	// The relay may hand the same event over again; it is not resent.
+	if err := d.Dispatch(context.Background(), e); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.deliveries) != 1 || rec.calls() != 1 {
+		t.Fatalf("redispatch created %d deliveries and %d calls, want 1 and 1", len(repo.deliveries), rec.calls())
+	}
+}
+
+func TestWebhookDispatchSkipsUnmatchedSubscriptions(t *testing.T) {
+	rec := newReceiver(t, http.StatusOK)
+	other := testSubscription(rec.URL)
+	other.Events = pq.StringArray{"student.*"}
+	uni := uuid.New()
+	scoped := testSubscription(rec.URL)
+	scoped.UniversityID = &uni
+	inactive := testSubscription(rec.URL)
+	inactive.Active = false
+
+	repo := newMemWebhookRepo(other, scoped, inactive)
+	if err := NewWebhookDispatcher(repo, rec.Client()).Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.deliveries) != 0 || rec.calls() != 0 {
+		t.Fatalf("got %d deliveries and %d calls, want none", len(repo.deliveries), rec.calls())
+	}
+}
+
+func TestWebhookRetryBackoff(t *testing.T) {
+	rec := newReceiver(t, http.StatusInternalServerError)
+	repo := newMemWebhookRepo(testSubscription(rec.URL))
+	d := NewWebhookDispatcher(repo, rec.Client())
+
+	start := time.Now()
+	if err := d.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	delivery := repo.deliveries[0]
+	if delivery.Status != domain.WebhookDeliveryPending || delivery.Attempts != 1 || delivery.ResponseCode != http.StatusInternalServerError {
+		t.Fatalf("delivery = %+v, want pending after 1 attempt with 500", delivery)
+	}
+	if delivery.NextAttemptAt == nil {
+		t.Fatal("no next attempt scheduled")
+	}
+	if wait := delivery.NextAttemptAt.Sub(start); wait < webhookBaseBackoff || wait > webhookBaseBackoff+5*time.Second {
+		t.Fatalf("first retry in %v, want about %v", wait, webhookBaseBackoff)
+	}
+
+	// Not due yet: nothing is sent.
+	if err := d.RetryDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rec.calls() != 1 {
+		t.Fatalf("retried before the backoff elapsed: %d calls", rec.calls())
+	}
+
+	// Once due, the second failure doubles the wait.
+	past := time.Now().Add(-time.Second)
+	delivery.NextAttemptAt = &past
+	start = time.Now()
+	if err := d.RetryDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rec.calls() != 2 {
+		t.Fatalf("got %d calls, want 2", rec.calls())
+	}
+	due, _ := repo.DueDeliveries(context.Background(), time.Now().Add(time.Hour), 10)
+	if len(due) != 1 || due[0].Attempts != 2 {
+		t.Fatalf("due deliveries = %+v, want one after 2 attempts", due)
+	}
+	if wait := due[0].NextAttemptAt.Sub(start); wait < 2*webhookBaseBackoff || wait > 2*webhookBaseBackoff+5*time.Second {
+		t.Fatalf("second retry in %v, want about %v", wait, 2*webhookBaseBackoff)
+	}
+
+	// A later success completes the delivery.
+	rec.setStatus(http.StatusNoContent)
+	repo.deliveries[0].NextAttemptAt = &past
+	if err := d.RetryDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := repo.deliveries[0]; got.Status != domain.WebhookDeliverySucceeded || got.Attempts != 3 {
+		t.Fatalf("delivery = %+v, want succeeded after 3 attempts", got)
+	}
+}
+
+func TestWebhookMaxAttemptsFails(t *testing.T) {
+	rec := newReceiver(t, http.StatusBadGateway)
+	repo := newMemWebhookRepo(testSubscription(rec.URL))
+	d := NewWebhookDispatcher(repo, rec.Client())
+
+	if err := d.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i < webhookMaxAttempts; i++ {
+		past := time.Now().Add(-time.Second)
+		repo.deliveries[0].NextAttemptAt = &past
+		if err := d.RetryDue(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	delivery := repo.deliveries[0]
+	if rec.calls() != webhookMaxAttempts || delivery.Attempts != webhookMaxAttempts {
+		t.Fatalf("got %d calls and %d attempts, want %d", rec.calls(), delivery.Attempts, webhookMaxAttempts)
+	}
+	if delivery.Status != domain.WebhookDeliveryFailed || delivery.NextAttemptAt != nil {
+		t.Fatalf("delivery = %+v, want failed without a next attempt", delivery)
+	}
+}
+
+func TestWebhookRetryFailsForDisabledSubscription(t *testing.T) {
+	rec := newReceiver(t, http.StatusInternalServerError)
+	sub := testSubscription(rec.URL)
+	repo := newMemWebhookRepo(sub)
+	d := NewWebhookDispatcher(repo, rec.Client())
+
+	if err := d.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	repo.subs[sub.ID].Active = false
+	past := time.Now().Add(-time.Second)
+	repo.deliveries[0].NextAttemptAt = &past
+	if err := d.RetryDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rec.calls() != 1 || repo.deliveries[0].Status != domain.WebhookDeliveryFailed {
+		t.Fatalf("got %d calls and status %s, want 1 and failed", rec.calls(), repo.deliveries[0].Status)
+	}
+}
+
+func TestWebhookRedeliver(t *testing.T) {
+	rec := newReceiver(t, http.StatusOK)
+	sub := testSubscription(rec.URL)
+	repo := newMemWebhookRepo(sub)
+	d := NewWebhookDispatcher(repo, rec.Client())
+
+	if err := d.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	orig := repo.deliveries[0]
+
+	again, err := d.Redeliver(context.Background(), sub.ID, orig.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID == orig.ID || again.RedeliveryOf == nil || *again.RedeliveryOf != orig.ID {
+		t.Fatalf("redelivery = %+v, want a new row pointing at %s", again, orig.ID)
+	}
+	if again.EventID != orig.EventID || string(again.Payload) != string(orig.Payload) {
+		t.Fatal("redelivery changed the event or payload")
+	}
+	if again.Status != domain.WebhookDeliverySucceeded || rec.calls() != 2 {
+		t.Fatalf("got status %s and %d calls, want succeeded and 2", again.Status, rec.calls())
+	}
+	if string(rec.bodies[0]) != string(rec.bodies[1]) {
+		t.Fatal("receiver got different bodies for the same event")
+	}
+
+	if _, err := d.Redeliver(context.Background(), uuid.New(), orig.ID); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("Redeliver() for another subscription = %v, want not found", err)
+	}
+}
+
+func TestWebhookDispatchLeavesLateAttemptsToRetries(t *testing.T) {
+	rec := newReceiver(t, http.StatusOK)
+	repo := newMemWebhookRepo(testSubscription(rec.URL), testSubscription(rec.URL))
+	d := NewWebhookDispatcher(repo, rec.Client())
+	d.inlineBudget = 0
+
+	if err := d.Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if len(repo.deliveries) != 2 || rec.calls() != 0 {
+		t.Fatalf("got %d deliveries and %d calls, want 2 and none past the budget", len(repo.deliveries), rec.calls())
+	}
+	for _, delivery := range repo.deliveries {
+		if delivery.Status != domain.WebhookDeliveryPending || delivery.Attempts != 0 || delivery.NextAttemptAt == nil {
+			t.Fatalf("delivery = %+v, want pending and due", delivery)
+		}
+	}
+
+	if err := d.RetryDue(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if rec.calls() != 2 {
+		t.Fatalf("retry pass made %d calls, want 2", rec.calls())
+	}
+	for _, delivery := range repo.deliveries {
+		if delivery.Status != domain.WebhookDeliverySucceeded || delivery.Attempts != 1 {
+			t.Errorf("delivery = %+v, want succeeded on its first attempt", delivery)
+		}
+	}
+}
+
+func TestWebhookDefaultClientRefusesPrivateAddresses(t *testing.T) {
+	rec := newReceiver(t, http.StatusOK)
+	repo := newMemWebhookRepo(testSubscription(rec.URL))
+
+	if err := NewWebhookDispatcher(repo, nil).Dispatch(context.Background(), testEvent()); err != nil {
+		t.Fatal(err)
+	}
+	if rec.calls() != 0 {
+		t.Fatal("default client connected to a loopback receiver")
+	}
+	if delivery := repo.deliveries[0]; delivery.Status != domain.WebhookDeliveryPending || delivery.LastError == "" {
+		t.Fatalf("delivery = %+v, want a pending failure", delivery)
+	}
+}
+
+func TestValidateWebhook(t *testing.T) {
+	tests := []struct {
+		url  string
+		want error
+	}{
+		{"https://93.184.216.34/hooks", nil},
+		{"ftp://93.184.216.34/hooks", ErrInvalidWebhookURL},
+		{"https:///hooks", ErrInvalidWebhookURL},
+		{"http://127.0.0.1:8080/hooks", ErrWebhookAddressBlocked},
+		{"http://localhost/hooks", ErrWebhookAddressBlocked},
+		{"http://10.1.2.3/hooks", ErrWebhookAddressBlocked},
+		{"http://192.168.0.10/hooks", ErrWebhookAddressBlocked},
+		{"http://169.254.169.254/latest/meta-data", ErrWebhookAddressBlocked},
+		{"http://0.0.0.0/hooks", ErrWebhookAddressBlocked},
+		{"http://[::1]/hooks", ErrWebhookAddressBlocked},
+		{"http://[fe80::1]/hooks", ErrWebhookAddressBlocked},
+		{"http://[::ffff:127.0.0.1]/hooks", ErrWebhookAddressBlocked},
+		{"http://100.64.0.1/hooks", ErrWebhookAddressBlocked},
+		{"http://100.127.255.254/hooks", ErrWebhookAddressBlocked},
+		{"https://100.128.0.1/hooks", nil},
+		{"http://192.0.0.170/hooks", ErrWebhookAddressBlocked},
+		{"http://198.18.0.1/hooks", ErrWebhookAddressBlocked},
+		{"http://198.19.255.1/hooks", ErrWebhookAddressBlocked},
+		{"http://[64:ff9b::a9fe:a9fe]/latest/meta-data", ErrWebhookAddressBlocked},
+	}
+	for _, tt := range tests {
+		sub := &domain.WebhookSubscription{URL: tt.url, Events: pq.StringArray{" notice.* ", ""}}
+		if err := ValidateWebhook(context.Background(), sub); !errors.Is(err, tt.want) {
+			t.Errorf("ValidateWebhook(%q) = %v, want %v", tt.url, err, tt.want)
+		}
+	}
+
+	sub := &domain.WebhookSubscription{URL: "https://93.184.216.34/hooks", Events: pq.StringArray{" ", ""}}
+	if err := ValidateWebhook(context.Background(), sub); !errors.Is(err, ErrNoWebhookEvents) {
+		t.Errorf("ValidateWebhook() without events = %v, want %v", err, ErrNoWebhookEvents)
+	}
+}
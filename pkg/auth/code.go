@@ -0,0 +1,32 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+)
+
+// GenerateNumericCode returns a cryptographically random numeric code of the given length.
+func GenerateNumericCode(length int) (string, error) {
+	result := ""
+	for i := 0; i < length; i++ {
+		num, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		result += fmt.Sprintf("%d", num)
+	}
+	return result, nil
+}
+
+// HashCode returns the hex HMAC-SHA256 of code under secret. Short codes are
+// stored this way: equal codes still match, but a database dump does not
+// reveal them without the secret.
+func HashCode(secret, code string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(code))
+	return hex.EncodeToString(mac.Sum(nil))
+}
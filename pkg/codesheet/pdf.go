@@ -0,0 +1,128 @@
+// Package codesheet renders printable sheets of one-time codes, one
+// cut-out slip per person with the code in text and as a QR code.
+package codesheet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/skip2/go-qrcode"
+)
+
+// Entry is one slip on the sheet.
+type Entry struct {
+	Name      string
+	Reference string // e.g. the student ID, printed under the name
+	Code      string // printed large and encoded in the QR code
+}
+
+// Sheet is a titled list of slips. Note is printed on every slip, e.g.
+// where to enter the code.
+type Sheet struct {
+	Title    string
+	Subtitle string
+	Note     string
+	Entries  []Entry
+}
+
+// A4 portrait, two columns of slips.
+const (
+	pageMargin = 12.0
+	headerH    = 16.0
+	columns    = 2
+	gutter     = 6.0
+	slipH      = 46.0
+	slipGap    = 4.0
+	qrSize     = 34.0
+	slipPad    = 4.0
+)
+
+// WritePDF renders s as an A4 PDF. Text uses the core Helvetica font, so
+// characters outside Windows-1252 are not printed correctly.
+func WritePDF(w io.Writer, s Sheet) error {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pageMargin, pageMargin, pageMargin)
+	pdf.SetAutoPageBreak(false, pageMargin)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pageW, pageH := pdf.GetPageSize()
+	slipW := (pageW - 2*pageMargin - gutter*(columns-1)) / columns
+	rows := int((pageH - 2*pageMargin - headerH + slipGap) / (slipH + slipGap))
+	perPage := rows * columns
+
+	header := func() {
+		pdf.AddPage()
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(0, 7, tr(s.Title), "", 1, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.CellFormat(0, 5, tr(s.Subtitle), "", 1, "L", false, 0, "")
+		pdf.SetTextColor(0, 0, 0)
+	}
+
+	if len(s.Entries) == 0 {
+		header()
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(0, 10, "No codes on this sheet.", "", 1, "L", false, 0, "")
+	}
+
+	for i, e := range s.Entries {
+		if i%perPage == 0 {
+			header()
+		}
+		slot := i % perPage
+		x := pageMargin + float64(slot%columns)*(slipW+gutter)
+		y := pageMargin + headerH + float64(slot/columns)*(slipH+slipGap)
+
+		// Dashed cut line around the slip
+		pdf.SetDrawColor(150, 150, 150)
+		pdf.SetDashPattern([]float64{1.5, 1}, 0)
+		pdf.Rect(x, y, slipW, slipH, "D")
+		pdf.SetDashPattern([]float64{}, 0)
+
+		png, err := qrcode.Encode(e.Code, qrcode.Medium, 256)
+		if err != nil {
+			return fmt.Errorf("qr code for %q: %w", e.Name, err)
+		}
+		name := fmt.Sprintf("qr-%d", i)
+		opts := fpdf.ImageOptions{ImageType: "PNG"}
+		pdf.RegisterImageOptionsReader(name, opts, bytes.NewReader(png))
+		pdf.ImageOptions(name, x+slipW-qrSize-slipPad, y+(slipH-qrSize)/2, qrSize, qrSize, false, opts, 0, "")
+
+		textW := slipW - qrSize - 3*slipPad
+		pdf.SetXY(x+slipPad, y+slipPad)
+		pdf.SetFont("Helvetica", "B", 11)
+		pdf.CellFormat(textW, 6, fit(pdf, tr(e.Name), textW), "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 9)
+		pdf.CellFormat(textW, 5, fit(pdf, tr(e.Reference), textW), "", 2, "L", false, 0, "")
+
+		pdf.SetXY(x+slipPad, y+17)
+		pdf.SetFont("Courier", "B", 22)
+		pdf.CellFormat(textW, 11, e.Code, "", 2, "L", false, 0, "")
+
+		pdf.SetXY(x+slipPad, y+30)
+		pdf.SetFont("Helvetica", "", 7)
+		pdf.SetTextColor(90, 90, 90)
+		pdf.MultiCell(textW, 3.2, tr(s.Note), "", "L", false)
+		pdf.SetTextColor(0, 0, 0)
+	}
+
+	if err := pdf.Error(); err != nil {
+		return err
+	}
+	return pdf.Output(w)
+}
+
+// fit truncates s with an ellipsis so it fits in width w at the current font.
+func fit(pdf *fpdf.Fpdf, s string, w float64) string {
+	if pdf.GetStringWidth(s) <= w {
+		return s
+	}
+	r := []rune(s)
+	for len(r) > 0 && pdf.GetStringWidth(string(r)+"...") > w {
+		r = r[:len(r)-1]
+	}
+	return string(r) + "..."
+}
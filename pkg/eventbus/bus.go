@@ -0,0 +1,177 @@
+// Package eventbus is a small in-process publish/subscribe bus. Sync
+// subscribers run inside Publish; async subscribers run on a worker pool so
+// slow side effects (push, webhooks, cache warmup) stay off the request path.
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"campusassistant-api/pkg/logger"
+)
+
+// Wildcard subscribes to every event.
+const Wildcard = "*"
+
+// Event is anything with a stable name such as "resource.approved".
+type Event interface {
+	EventName() string
+}
+
+// Handler reacts to an event.
+type Handler func(ctx context.Context, e Event) error
+
+type job struct {
+	ctx     context.Context
+	event   Event
+	handler Handler
+}
+
+// Bus dispatches events to subscribers. A nil *Bus is valid and drops every
+// event, so collaborators can hold an optional bus without nil checks.
+type Bus struct {
+	mu     sync.RWMutex
+	sync   map[string][]Handler
+	async  map[string][]Handler
+	closed bool
+
+	queue   chan job
+	workers int
+	wg      sync.WaitGroup
+	once    sync.Once
+}
+
+// New returns a bus with the given number of async workers and queue size.
+// The workers run from Start; async work published before waits in the
+// queue.
+func New(workers, queueSize int) *Bus {
+	return &Bus{
+		sync:    map[string][]Handler{},
+		async:   map[string][]Handler{},
+		queue:   make(chan job, queueSize),
+		workers: workers,
+	}
+}
+
+// Start launches the async workers.
+func (b *Bus) Start() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return
+	}
+	for i := 0; i < b.workers; i++ {
+		b.wg.Add(1)
+		go b.work()
+	}
+}
+
+// Subscribe registers a handler that runs synchronously inside Publish.
+// Its error is returned to the publisher.
+func (b *Bus) Subscribe(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.sync[name] = append(b.sync[name], h)
+}
+
+// SubscribeAsync registers a handler that runs on the worker pool. Errors
+// and panics are logged.
+func (b *Bus) SubscribeAsync(name string, h Handler) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.async[name] = append(b.async[name], h)
+}
+
+// Publish runs sync subscribers in registration order, then queues async
+// ones with a context that outlives the request. Async work is dropped (and
+// logged) when the queue is full rather than blocking the caller.
+func (b *Bus) Publish(ctx context.Context, e Event) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.RLock()
+	syncHandlers := append(append([]Handler{}, b.sync[e.EventName()]...), b.sync[Wildcard]...)
+	b.mu.RUnlock()
+
+	var errs []error
+	for _, h := range syncHandlers {
+		if err := h(ctx, e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	// Enqueue under the read lock so Close cannot close the queue mid-send.
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	if b.closed {
+		return errors.Join(errs...)
+	}
+	detached := context.WithoutCancel(ctx)
+	for _, h := range append(append([]Handler{}, b.async[e.EventName()]...), b.async[Wildcard]...) {
+		select {
+		case b.queue <- job{ctx: detached, event: e, handler: h}:
+		default:
+			logger.Errorf("event bus queue full, dropping %s handler", e.EventName())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close stops accepting async work and waits for queued handlers to finish.
+// Work queued on a bus that was never started is dropped.
+func (b *Bus) Close() {
+	if b == nil {
+		return
+	}
+	b.once.Do(func() {
+		b.mu.Lock()
+		b.closed = true
+		close(b.queue)
+		b.mu.Unlock()
+		b.wg.Wait()
+	})
+}
+
+func (b *Bus) work() {
+	defer b.wg.Done()
+	for j := range b.queue {
+		if err := b.run(j); err != nil {
+			logger.Errorf("async handler for %s failed: %v", j.event.EventName(), err)
+		}
+	}
+}
+
+func (b *Bus) run(j job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.handler(j.ctx, j.event)
+}
+
+// On subscribes a typed handler for events of type E.
+func On[E Event](b *Bus, fn func(ctx context.Context, e E) error) {
+	var zero E
+	b.Subscribe(zero.EventName(), typed(fn))
+}
+
+// OnAsync subscribes a typed handler for events of type E on the worker pool.
+func OnAsync[E Event](b *Bus, fn func(ctx context.Context, e E) error) {
+	var zero E
+	b.SubscribeAsync(zero.EventName(), typed(fn))
+}
+
+func typed[E Event](fn func(ctx context.Context, e E) error) Handler {
+	return func(ctx context.Context, e Event) error {
+		ev, ok := e.(E)
+		if !ok {
+			return nil
+		}
+		return fn(ctx, ev)
+	}
+}
@@ -0,0 +1,153 @@
+package eventbus
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"campusassistant-api/pkg/logger"
+
+	"go.uber.org/zap"
+)
+
+func init() {
+	logger.Log = zap.NewNop()
+}
+
+type noticeCreated struct{ title string }
+
+func (noticeCreated) EventName() string { return "notice.created" }
+
+type noticeDeleted struct{}
+
+func (noticeDeleted) EventName() string { return "notice.deleted" }
+
+func TestPublishRunsSyncHandlers(t *testing.T) {
+	b := New(1, 8)
+	var got []string
+	On(b, func(_ context.Context, e noticeCreated) error {
+		got = append(got, "typed:"+e.title)
+		return nil
+	})
+	b.Subscribe(Wildcard, func(_ context.Context, e Event) error {
+		got = append(got, "wildcard:"+e.EventName())
+		return errors.New("wildcard failed")
+	})
+
+	err := b.Publish(context.Background(), noticeCreated{title: "exam"})
+	if err == nil || err.Error() != "wildcard failed" {
+		t.Fatalf("Publish() error = %v, want the sync handler's error", err)
+	}
+	want := []string{"typed:exam", "wildcard:notice.created"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("handlers ran as %v, want %v", got, want)
+	}
+
+	got = nil
+	if err := b.Publish(context.Background(), noticeDeleted{}); err == nil {
+		t.Fatal("wildcard handler should see every event")
+	}
+	if len(got) != 1 {
+		t.Fatalf("typed handler ran for another event: %v", got)
+	}
+}
+
+func TestAsyncHandlersRunAfterStart(t *testing.T) {
+	b := New(2, 8)
+	var wg sync.WaitGroup
+	var calls atomic.Int32
+	ctxErr := make(chan error, 1)
+	OnAsync(b, func(ctx context.Context, e noticeCreated) error {
+		defer wg.Done()
+		calls.Add(1)
+		select {
+		case ctxErr <- ctx.Err():
+		default:
+		}
+		return nil
+	})
+
+	// Published before Start: waits in the queue
+	ctx, cancel := context.WithCancel(context.Background())
+	wg.Add(1)
+	if err := b.Publish(ctx, noticeCreated{}); err != nil {
+		t.Fatal(err)
+	}
+	cancel() // the request ends before the handler runs
+	time.Sleep(10 * time.Millisecond)
+	if calls.Load() != 0 {
+		t.Fatal("async handler ran before Start")
+	}
+
+	b.Start()
+	defer b.Close()
+	wg.Wait()
+	if calls.Load() != 1 {
+		t.Fatalf("async handler ran %d times, want 1", calls.Load())
+	}
+	if err := <-ctxErr; err != nil {
+		t.Fatalf("async handler got a cancelled context: %v", err)
+	}
+}
+
+func TestAsyncHandlerPanicIsContained(t *testing.T) {
+	b := New(1, 8)
+	b.Start()
+	done := make(chan struct{})
+	b.SubscribeAsync("notice.created", func(context.Context, Event) error { panic("boom") })
+	b.SubscribeAsync("notice.created", func(context.Context, Event) error {
+		close(done)
+		return nil
+	})
+
+	if err := b.Publish(context.Background(), noticeCreated{}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("worker died with the panicking handler")
+	}
+	b.Close()
+}
+
+func TestCloseDrainsQueueAndRejectsLaterWork(t *testing.T) {
+	b := New(1, 8)
+	var calls atomic.Int32
+	b.SubscribeAsync("notice.created", func(context.Context, Event) error {
+		time.Sleep(5 * time.Millisecond)
+		calls.Add(1)
+		return nil
+	})
+	b.Start()
+
+	for i := 0; i < 3; i++ {
+		if err := b.Publish(context.Background(), noticeCreated{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b.Close()
+	if calls.Load() != 3 {
+		t.Fatalf("Close returned after %d of 3 queued handlers", calls.Load())
+	}
+
+	// After Close async work is dropped, not sent on the closed queue
+	if err := b.Publish(context.Background(), noticeCreated{}); err != nil {
+		t.Fatal(err)
+	}
+	b.Close()
+	if calls.Load() != 3 {
+		t.Fatalf("handler ran after Close")
+	}
+}
+
+func TestNilBus(t *testing.T) {
+	var b *Bus
+	if err := b.Publish(context.Background(), noticeCreated{}); err != nil {
+		t.Fatalf("nil bus Publish() = %v", err)
+	}
+	b.Close()
+}
@@ -0,0 +1,153 @@
+// Package ical writes iCalendar (RFC 5545) files that calendar apps can
+// import or subscribe to.
+package ical
+
+import (
+	"bytes"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ContentType is the media type of iCalendar files.
+const ContentType = "text/calendar; charset=utf-8"
+
+// Event statuses.
+const (
+	StatusTentative = "TENTATIVE"
+	StatusConfirmed = "CONFIRMED"
+	StatusCancelled = "CANCELLED"
+)
+
+// Calendar methods (RFC 5546): MethodRequest for new or changed events,
+// MethodCancel to remove them. Leave Method empty for a plain feed.
+const (
+	MethodPublish = "PUBLISH"
+	MethodRequest = "REQUEST"
+	MethodCancel  = "CANCEL"
+)
+
+// Event is one VEVENT. UID must stay the same across updates of an event
+// and Sequence must grow with each of them. An AllDay event covers the
+// dates of Start up to, but not including, End in their own time zone.
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	AllDay      bool
+	Status      string
+	Sequence    int
+	Updated     time.Time // DTSTAMP; defaults to now
+}
+
+// Calendar is a VCALENDAR with its events.
+type Calendar struct {
+	Name   string // X-WR-CALNAME, shown by most apps
+	Method string
+	Events []Event
+}
+
+const prodID = "-//Campus Assistant//API//EN"
+
+// Write renders c with CRLF line endings and long lines folded. Times are
+// written in UTC, all-day dates as they are.
+func (c Calendar) Write(w io.Writer) error {
+	var b bytes.Buffer
+	line := func(name, value string) {
+		fold(&b, name+":"+value)
+	}
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", prodID)
+	line("CALSCALE", "GREGORIAN")
+	if c.Method != "" {
+		line("METHOD", c.Method)
+	}
+	if c.Name != "" {
+		line("X-WR-CALNAME", Escape(c.Name))
+	}
+	for _, e := range c.Events {
+		stamp := e.Updated
+		if stamp.IsZero() {
+			stamp = time.Now()
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", e.UID)
+		line("DTSTAMP", formatTime(stamp))
+		if e.AllDay {
+			line("DTSTART;VALUE=DATE", formatDate(e.Start))
+			line("DTEND;VALUE=DATE", formatDate(e.End))
+		} else {
+			line("DTSTART", formatTime(e.Start))
+			line("DTEND", formatTime(e.End))
+		}
+		line("SUMMARY", Escape(e.Summary))
+		if e.Description != "" {
+			line("DESCRIPTION", Escape(e.Description))
+		}
+		if e.Location != "" {
+			line("LOCATION", Escape(e.Location))
+		}
+		if e.Status != "" {
+			line("STATUS", e.Status)
+		}
+		line("SEQUENCE", strconv.Itoa(e.Sequence))
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+
+	_, err := w.Write(b.Bytes())
+	return err
+}
+
+// Bytes renders c; see Write.
+func (c Calendar) Bytes() []byte {
+	var b bytes.Buffer
+	_ = c.Write(&b)
+	return b.Bytes()
+}
+
+// Escape escapes a TEXT value: backslashes, commas, semicolons and
+// newlines.
+func Escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, ";", `\;`)
+	s = strings.ReplaceAll(s, ",", `\,`)
+	s = strings.ReplaceAll(s, "\r\n", `\n`)
+	return strings.ReplaceAll(s, "\n", `\n`)
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format("20060102T150405Z")
+}
+
+func formatDate(t time.Time) string {
+	return t.Format("20060102")
+}
+
+// fold writes a content line, breaking it into lines of at most 75 octets
+// (continuations start with a space) without splitting UTF-8 sequences.
+func fold(b *bytes.Buffer, s string) {
+	limit := 75
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		limit = 74 // the leading space counts
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
+
+func isRuneStart(c byte) bool {
+	return c&0xC0 != 0x80
+}
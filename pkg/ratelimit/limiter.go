@@ -0,0 +1,77 @@
+// Package ratelimit provides an in-memory fixed-window rate limiter.
+package ratelimit
+
+import (
+	"sync"
+	"time"
+)
+
+// Limiter allows up to limit calls per key in each window. State lives in
+// process memory, so with several instances each enforces its own budget.
+type Limiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	windows   map[string]*window
+	lastSweep time.Time
+}
+
+type window struct {
+	start time.Time
+	count int
+}
+
+// New returns a limiter allowing limit calls per key every per.
+func New(limit int, per time.Duration) *Limiter {
+	return &Limiter{
+		limit:     limit,
+		window:    per,
+		windows:   map[string]*window{},
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow counts a call for key. When the budget is spent it returns false
+// and how long until the window resets.
+func (l *Limiter) Allow(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) > l.window {
+		for k, w := range l.windows {
+			if now.Sub(w.start) >= l.window {
+				delete(l.windows, k)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		w = &window{start: now}
+		l.windows[key] = w
+	}
+	if w.count >= l.limit {
+		return false, w.start.Add(l.window).Sub(now)
+	}
+	w.count++
+	return true, 0
+}
+
+// Spent reports, without counting a call, whether key's budget is used up
+// and how long until the window resets.
+func (l *Limiter) Spent(key string) (bool, time.Duration) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	w, ok := l.windows[key]
+	if !ok || now.Sub(w.start) >= l.window || w.count < l.limit {
+		return false, 0
+	}
+	return true, w.start.Add(l.window).Sub(now)
+}
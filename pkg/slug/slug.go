@@ -0,0 +1,141 @@
+// Package slug turns names into URL slugs, transliterating Bengali script
+// and stripping Latin diacritics.
+package slug
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Make returns a lowercase ASCII slug for s: "ঢাকা বিশ্ববিদ্যালয়" becomes
+// "dhaka-bishwabidyalay" and "Café Lounge" becomes "cafe-lounge".
+func Make(s string) string {
+	s = transliterateBengali(norm.NFC.String(s))
+
+	var sb strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// combining accents left over from NFD
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(r)
+		default:
+			dash = true
+		}
+	}
+	return sb.String()
+}
+
+// WithSuffix returns the n-th variant of a slug ("batch-10-2" for n=2).
+func WithSuffix(base string, n int) string {
+	if n <= 1 {
+		return base
+	}
+	return base + "-" + strconv.Itoa(n)
+}
+
+const (
+	hasant = '\u09CD' // ্ virama: drops the inherent vowel
+	nukta  = '\u09BC' // ়
+)
+
+var bnVowels = map[rune]string{
+	'অ': "a", 'আ': "a", 'ই': "i", 'ঈ': "i", 'উ': "u", 'ঊ': "u",
+	'ঋ': "ri", 'এ': "e", 'ঐ': "oi", 'ও': "o", 'ঔ': "ou",
+}
+
+var bnVowelSigns = map[rune]string{
+	'া': "a", 'ি': "i", 'ী': "i", 'ু': "u", 'ূ': "u",
+	'ৃ': "ri", 'ে': "e", 'ৈ': "oi", 'ো': "o", 'ৌ': "ou",
+}
+
+var bnConsonants = map[rune]string{
+	'ক': "k", 'খ': "kh", 'গ': "g", 'ঘ': "gh", 'ঙ': "ng",
+	'চ': "ch", 'ছ': "chh", 'জ': "j", 'ঝ': "jh", 'ঞ': "n",
+	'ট': "t", 'ঠ': "th", 'ড': "d", 'ঢ': "dh", 'ণ': "n",
+	'ত': "t", 'থ': "th", 'দ': "d", 'ধ': "dh", 'ন': "n",
+	'প': "p", 'ফ': "f", 'ব': "b", 'ভ': "bh", 'ম': "m",
+	'য': "j", 'র': "r", 'ল': "l", 'শ': "sh", 'ষ': "sh",
+	'স': "s", 'হ': "h",
+	'\u09DC': "r", '\u09DD': "rh", '\u09DF': "y", // ড় ঢ় য় (precomposed)
+}
+
+// Consonants written with a nukta after NFC (ড়, ঢ়, য় are composition exclusions).
+var bnNukta = map[rune]string{'ড': "r", 'ঢ': "rh", 'য': "y"}
+
+var bnSigns = map[rune]string{
+	'ং': "ng", 'ঃ': "h", 'ঁ': "", 'ৎ': "t",
+}
+
+// transliterateBengali romanizes Bengali runes phonetically and leaves
+// everything else untouched. Consonants carry an inherent "a" unless followed
+// by a vowel sign or hasant, or at the end of a word; ্ব and ্য after a
+// consonant are written "w" and "y".
+func transliterateBengali(s string) string {
+	runes := []rune(s)
+
+	var sb strings.Builder
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		if r >= '০' && r <= '৯' {
+			sb.WriteRune('0' + (r - '০'))
+			continue
+		}
+		if v, ok := bnVowels[r]; ok {
+			sb.WriteString(v)
+			continue
+		}
+		if v, ok := bnVowelSigns[r]; ok {
+			sb.WriteString(v)
+			continue
+		}
+		if v, ok := bnSigns[r]; ok {
+			sb.WriteString(v)
+			continue
+		}
+
+		c, ok := bnConsonants[r]
+		if !ok {
+			if r != hasant && r != nukta {
+				sb.WriteRune(r)
+			}
+			continue
+		}
+		if i+1 < len(runes) && runes[i+1] == nukta {
+			if v, ok := bnNukta[r]; ok {
+				c = v
+			}
+			i++
+		}
+		if i > 0 && runes[i-1] == hasant {
+			switch r {
+			case 'ব':
+				c = "w"
+			case 'য':
+				c = "y"
+			}
+		}
+		sb.WriteString(c)
+
+		next := rune(0)
+		if i+1 < len(runes) {
+			next = runes[i+1]
+		}
+		_, sign := bnVowelSigns[next]
+		_, cons := bnConsonants[next]
+		_, mod := bnSigns[next]
+		if !sign && next != hasant && (cons || mod) {
+			sb.WriteByte('a')
+		}
+	}
+	return sb.String()
+}
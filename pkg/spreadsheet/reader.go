@@ -0,0 +1,128 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format identifies a supported tabular file format.
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported file format (use .csv or .xlsx)")
+
+// Row is a single data row keyed by normalized header name.
+type Row map[string]string
+
+// FormatFromFilename picks the format from the file extension.
+func FormatFromFilename(name string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".xlsx":
+		return FormatXLSX, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// ReadRows parses a CSV or XLSX file (first sheet) into rows.
+// The first non-empty row is the header; headers are normalized with
+// NormalizeHeader and fully empty rows are skipped. maxRows <= 0 means no limit.
+func ReadRows(r io.Reader, format Format, maxRows int) ([]Row, error) {
+	var records [][]string
+
+	switch format {
+	case FormatCSV:
+		reader := csv.NewReader(r)
+		reader.FieldsPerRecord = -1
+		reader.TrimLeadingSpace = true
+		all, err := reader.ReadAll()
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		records = all
+	case FormatXLSX:
+		f, err := excelize.OpenReader(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid XLSX: %w", err)
+		}
+		defer f.Close()
+
+		sheets := f.GetSheetList()
+		if len(sheets) == 0 {
+			return nil, errors.New("workbook has no sheets")
+		}
+		all, err := f.GetRows(sheets[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid XLSX: %w", err)
+		}
+		records = all
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+
+	// Find the header row
+	start := 0
+	for start < len(records) && isEmptyRecord(records[start]) {
+		start++
+	}
+	if start == len(records) {
+		return nil, errors.New("file has no header row")
+	}
+
+	headers := make([]string, len(records[start]))
+	for i, h := range records[start] {
+		headers[i] = NormalizeHeader(h)
+	}
+
+	rows := make([]Row, 0, len(records)-start-1)
+	for _, record := range records[start+1:] {
+		if isEmptyRecord(record) {
+			continue
+		}
+		if maxRows > 0 && len(rows) >= maxRows {
+			return nil, fmt.Errorf("file exceeds the limit of %d rows", maxRows)
+		}
+		row := make(Row, len(headers))
+		for i, h := range headers {
+			if h == "" || i >= len(record) {
+				continue
+			}
+			row[h] = strings.TrimSpace(record[i])
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// NormalizeHeader lowercases a column title and replaces spaces, dashes and
+// dots with underscores, e.g. "Blood Group" -> "blood_group".
+func NormalizeHeader(h string) string {
+	h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+	h = strings.NewReplacer(" ", "_", "-", "_", ".", "_").Replace(h)
+	for strings.Contains(h, "__") {
+		h = strings.ReplaceAll(h, "__", "_")
+	}
+	return strings.Trim(h, "_")
+}
+
+func isEmptyRecord(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,96 @@
+package spreadsheet
+
+import (
+	"encoding/csv"
+	"io"
+
+	"github.com/xuri/excelize/v2"
+)
+
+const csvFlushEvery = 100
+
+// RowWriter writes records one at a time; call Close to finish the file.
+type RowWriter interface {
+	Write(record []string) error
+	Close() error
+}
+
+// ContentType returns the MIME type for a format.
+func ContentType(format Format) string {
+	switch format {
+	case FormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return "text/csv; charset=utf-8"
+	}
+}
+
+// NewWriter returns a RowWriter for format. CSV is written through to w as it
+// goes; XLSX is built with excelize's stream writer and copied to w on Close.
+func NewWriter(w io.Writer, format Format) (RowWriter, error) {
+	switch format {
+	case FormatCSV:
+		return &csvRowWriter{w: csv.NewWriter(w)}, nil
+	case FormatXLSX:
+		f := excelize.NewFile()
+		sw, err := f.NewStreamWriter(f.GetSheetName(0))
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		return &xlsxRowWriter{out: w, file: f, sheet: sw}, nil
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+type csvRowWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func (c *csvRowWriter) Write(record []string) error {
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+	c.rows++
+	if c.rows%csvFlushEvery == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvRowWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+type xlsxRowWriter struct {
+	out   io.Writer
+	file  *excelize.File
+	sheet *excelize.StreamWriter
+	rows  int
+}
+
+func (x *xlsxRowWriter) Write(record []string) error {
+	x.rows++
+	cell, err := excelize.CoordinatesToCellName(1, x.rows)
+	if err != nil {
+		return err
+	}
+	values := make([]interface{}, len(record))
+	for i, v := range record {
+		values[i] = v
+	}
+	return x.sheet.SetRow(cell, values)
+}
+
+func (x *xlsxRowWriter) Close() error {
+	defer x.file.Close()
+	if err := x.sheet.Flush(); err != nil {
+		return err
+	}
+	_, err := x.file.WriteTo(x.out)
+	return err
+}
@@ -0,0 +1,71 @@
+package webhook
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignVerify(t *testing.T) {
+	secret, err := NewSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := []byte(`{"id":"1","event":"notice.created"}`)
+	header := Sign(secret, time.Now(), body)
+
+	if !strings.HasPrefix(header, "t=") || !strings.Contains(header, ",v1=") {
+		t.Fatalf("unexpected header format %q", header)
+	}
+	if err := Verify(secret, header, body, 0); err != nil {
+		t.Fatalf("Verify() = %v, want nil", err)
+	}
+}
+
+func TestVerifyRejects(t *testing.T) {
+	secret := "whsec_test"
+	body := []byte(`{"id":"1"}`)
+	now := time.Now()
+
+	tests := []struct {
+		name   string
+		secret string
+		header string
+		body   []byte
+		want   error
+	}{
+		{"tampered body", secret, Sign(secret, now, body), []byte(`{"id":"2"}`), ErrInvalidSignature},
+		{"wrong secret", "whsec_other", Sign(secret, now, body), body, ErrInvalidSignature},
+		{"stale timestamp", secret, Sign(secret, now.Add(-10*time.Minute), body), body, ErrSignatureExpired},
+		{"future timestamp", secret, Sign(secret, now.Add(10*time.Minute), body), body, ErrSignatureExpired},
+		{"empty header", secret, "", body, ErrMissingSignature},
+		{"no signature", secret, "t=123", body, ErrMissingSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := Verify(tt.secret, tt.header, tt.body, 0); !errors.Is(err, tt.want) {
+				t.Fatalf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyTolerance(t *testing.T) {
+	body := []byte(`{}`)
+	header := Sign("s", time.Now().Add(-10*time.Minute), body)
+	if err := Verify("s", header, body, time.Hour); err != nil {
+		t.Fatalf("Verify() with 1h tolerance = %v, want nil", err)
+	}
+}
+
+func TestVerifyAcceptsAnyOfSeveralSignatures(t *testing.T) {
+	body := []byte(`{}`)
+	now := time.Now()
+	header := Sign("new", now, body) + ",v1=" + strings.SplitN(Sign("old", now, body), ",v1=", 2)[1]
+	for _, secret := range []string{"new", "old"} {
+		if err := Verify(secret, header, body, 0); err != nil {
+			t.Fatalf("Verify(%q) = %v, want nil", secret, err)
+		}
+	}
+}